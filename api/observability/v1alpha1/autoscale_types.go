@@ -0,0 +1,110 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AutoscaleSpec scales a limit of a tenant to keep its usage at a target
+// utilization. The limit set in the limits of the tenant is the baseline the
+// limit starts from. Usage is queried as configured in the usage section of
+// the Config.
+// +kubebuilder:validation:XValidation:rule="self.max >= self.min",message="max must not be lower than min"
+type AutoscaleSpec struct {
+	// Limit is the path of the scaled limit in the limits of the tenant.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=mimir.max_global_series_per_user;mimir.ingestion_rate;loki.ingestion_rate_mb
+	Limit string `json:"limit"`
+
+	// Min is the lowest value the limit is scaled to.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=0
+	Min float64 `json:"min"`
+
+	// Max is the highest value the limit is scaled to.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=0
+	Max float64 `json:"max"`
+
+	// TargetUtilization is the percentage of the limit the usage of the tenant is scaled to.
+	// +kubebuilder:default:=80
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Optional
+	TargetUtilization *int32 `json:"targetUtilization,omitempty"`
+
+	// Cooldown is the minimum time between two changes of the limit.
+	// +kubebuilder:default:="1h"
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	Cooldown *metav1.Duration `json:"cooldown,omitempty"`
+}
+
+// AutoscaleReason is why an autoscaled limit changed.
+// +kubebuilder:validation:Enum=Baseline;Bounds;Usage
+type AutoscaleReason string
+
+const (
+	// AutoscaleBaselineReason is used when the limit was reset to a changed baseline.
+	AutoscaleBaselineReason AutoscaleReason = "Baseline"
+	// AutoscaleBoundsReason is used when the limit was moved within changed bounds.
+	AutoscaleBoundsReason AutoscaleReason = "Bounds"
+	// AutoscaleUsageReason is used when the limit was scaled to the usage of the tenant.
+	AutoscaleUsageReason AutoscaleReason = "Usage"
+)
+
+// AutoscaleStatus is the state of an autoscaled limit.
+type AutoscaleStatus struct {
+	// Limit is the path of the scaled limit in the limits of the tenant.
+	Limit string `json:"limit"`
+
+	// Baseline is the limit set in the limits of the tenant when it was last reset.
+	// +optional
+	Baseline *float64 `json:"baseline,omitempty"`
+
+	// Value is the value the limit is rendered with.
+	Value float64 `json:"value"`
+
+	// LastScaleTime is when the limit last changed.
+	// +optional
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
+
+	// History lists the latest changes of the limit, oldest first.
+	// +optional
+	History []AutoscaleEvent `json:"history,omitempty"`
+}
+
+// AutoscaleEvent is a change of an autoscaled limit.
+type AutoscaleEvent struct {
+	// Time is when the limit changed.
+	Time metav1.Time `json:"time"`
+
+	// Reason is why the limit changed.
+	Reason AutoscaleReason `json:"reason"`
+
+	// From is the value of the limit before the change.
+	From float64 `json:"from"`
+
+	// To is the value of the limit after the change.
+	To float64 `json:"to"`
+
+	// Usage is the usage of the tenant the limit was scaled to.
+	// +optional
+	Usage *float64 `json:"usage,omitempty"`
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// BudgetSpec caps the sum of a limit over the children of a tenant. Children
+// that do not set a budgeted limit themselves get an even share of what is
+// left of the budget.
+type BudgetSpec struct {
+	// +kubebuilder:validation:Optional
+	Mimir *MimirBudget `json:"mimir,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Loki *LokiBudget `json:"loki,omitempty"`
+}
+
+// MimirBudget lists the Mimir limits that can be budgeted. Field names match MimirLimits.
+type MimirBudget struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	IngestionRate *float64 `json:"ingestion_rate,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	IngestionBurstSize *int `json:"ingestion_burst_size,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	MaxGlobalSeriesPerUser *int `json:"max_global_series_per_user,omitempty"`
+}
+
+// LokiBudget lists the Loki limits that can be budgeted. Field names match LokiLimits.
+type LokiBudget struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	IngestionRateMB *float64 `json:"ingestion_rate_mb,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	IngestionBurstSizeMB *float64 `json:"ingestion_burst_size_mb,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	MaxGlobalStreamsPerUser *int `json:"max_global_streams_per_user,omitempty"`
+}
@@ -36,14 +36,63 @@ type ConfigSpec struct {
 
 	// +kubebuilder:validation:Optional
 	Tempo *TempoSpec `json:"tempo,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Keto *KetoSpec `json:"keto,omitempty"`
+
+	// Gateway configures the generation of per-tenant gateway credentials.
+	// +kubebuilder:validation:Optional
+	Gateway *GatewaySpec `json:"gateway,omitempty"`
+
+	// QueryFederation configures where the query federation of tenants is exported for the query gateway.
+	// +kubebuilder:validation:Optional
+	QueryFederation *QueryFederationSpec `json:"queryFederation,omitempty"`
+
+	// Usage configures where the usage of tenants is queried from to recommend limits.
+	// +kubebuilder:validation:Optional
+	Usage *UsageSpec `json:"usage,omitempty"`
+
+	// UnownedKeys is how the keys of the Mimir, Loki and Tempo runtime
+	// configurations the controller does not own are handled when it writes
+	// them. The controller owns the modeled fields of the global configuration
+	// and of the overrides of tenants that have a Tenant.
+	// +kubebuilder:default:="Preserve"
+	// +kubebuilder:validation:Optional
+	UnownedKeys UnownedKeysPolicy `json:"unownedKeys,omitempty"`
 }
 
+// UnownedKeysPolicy is how the keys of a runtime configuration the controller
+// does not own are handled.
+// +kubebuilder:validation:Enum=Preserve;Refuse;Drop
+type UnownedKeysPolicy string
+
+const (
+	// UnownedKeysPreserve keeps unknown keys, unknown fields of overrides and
+	// the overrides of tenants without a Tenant as they are.
+	UnownedKeysPreserve UnownedKeysPolicy = "Preserve"
+	// UnownedKeysRefuse does not write a runtime configuration as long as it
+	// has keys the controller does not own.
+	UnownedKeysRefuse UnownedKeysPolicy = "Refuse"
+	// UnownedKeysDrop removes the keys the controller does not own, including
+	// the overrides of tenants without a Tenant.
+	UnownedKeysDrop UnownedKeysPolicy = "Drop"
+)
+
 type MimirSpec struct {
 	// +kubebuilder:validation:Required
 	ConfigMap ConfigMapSelector `json:"configMap"`
 
 	// +kubebuilder:validation:Optional
 	Config *MimirConfigSpec `json:"config,omitempty"`
+
+	// Ruler configures the Mimir ruler API TenantRuleGroups are synced to.
+	// +kubebuilder:validation:Optional
+	Ruler *RulerSpec `json:"ruler,omitempty"`
+
+	// Alertmanager configures the Mimir Alertmanager API
+	// TenantAlertmanagerConfigs are synced to.
+	// +kubebuilder:validation:Optional
+	Alertmanager *AlertmanagerSpec `json:"alertmanager,omitempty"`
 }
 
 type LokiSpec struct {
@@ -52,6 +101,10 @@ type LokiSpec struct {
 
 	// +kubebuilder:validation:Optional
 	Config *LokiConfigSpec `json:"config,omitempty"`
+
+	// Ruler configures the Loki ruler API TenantRuleGroups are synced to.
+	// +kubebuilder:validation:Optional
+	Ruler *RulerSpec `json:"ruler,omitempty"`
 }
 
 type TempoSpec struct {
@@ -120,6 +173,38 @@ type MimirDistributorInstanceLimits struct {
 	MaxInflightPushRequestsBytes int `json:"max_inflight_push_requests_bytes,omitempty"`
 }
 
+// RulerSpec configures a ruler API rule groups are synced to.
+type RulerSpec struct {
+	// URL of the ruler API. For Mimir it includes the Prometheus HTTP prefix,
+	// e.g. http://mimir-ruler.mimir.svc:8080/prometheus, for Loki it is the
+	// base URL, e.g. http://loki-ruler.loki.svc:3100.
+	// +kubebuilder:validation:Required
+	URL string `json:"url"`
+
+	// SyncInterval is how often rule groups are synced again to undo changes
+	// made directly in the ruler.
+	// +kubebuilder:default:="5m"
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	SyncInterval *metav1.Duration `json:"syncInterval,omitempty"`
+}
+
+// AlertmanagerSpec configures an Alertmanager API configurations are synced to.
+type AlertmanagerSpec struct {
+	// URL of the Mimir Alertmanager, e.g. http://mimir-alertmanager.mimir.svc:8080.
+	// +kubebuilder:validation:Required
+	URL string `json:"url"`
+
+	// SyncInterval is how often configurations are synced again to undo
+	// changes made directly in the Alertmanager.
+	// +kubebuilder:default:="5m"
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	SyncInterval *metav1.Duration `json:"syncInterval,omitempty"`
+}
+
 type ConfigMapSelector struct {
 	// +kubebuilder:default:="mimir-runtime"
 	Name string `json:"name"`
@@ -131,11 +216,103 @@ type ConfigMapSelector struct {
 	Key string `json:"key"`
 }
 
-// type KetoConfig struct {
-// 	ReadRemote string `json:"readRemote,omitempty"`
+type KetoSpec struct {
+	// Enabled toggles the management of tenant permissions in Keto. When disabled,
+	// tenants are neither registered in nor removed from Keto.
+	// +kubebuilder:default:=true
+	// +kubebuilder:validation:Optional
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// GatewayCredentialType is the kind of credential generated for tenants.
+// +kubebuilder:validation:Enum=token;basic-auth
+type GatewayCredentialType string
+
+const (
+	// GatewayCredentialToken generates a random bearer token per tenant.
+	GatewayCredentialToken GatewayCredentialType = "token"
+	// GatewayCredentialBasicAuth generates a basic-auth password and htpasswd entry per tenant.
+	GatewayCredentialBasicAuth GatewayCredentialType = "basic-auth"
+)
+
+type GatewaySpec struct {
+	// Namespace is where the per-tenant credential Secrets and the aggregated Secret are created.
+	// +kubebuilder:validation:Required
+	Namespace string `json:"namespace"`
+
+	// CredentialType is the kind of credential generated for each tenant.
+	// +kubebuilder:default:="token"
+	// +kubebuilder:validation:Optional
+	CredentialType GatewayCredentialType `json:"credentialType,omitempty"`
+
+	// SecretName is the name of the aggregated Secret mapping credentials to tenant IDs.
+	// +kubebuilder:default:="gateway-tenant-credentials"
+	// +kubebuilder:validation:Optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+type QueryFederationSpec struct {
+	// ConfigMap holds a YAML map from each tenant to the |-separated tenant IDs its queries may span.
+	// +kubebuilder:validation:Required
+	ConfigMap ConfigMapSelector `json:"configMap"`
+}
+
+// UsageSpec configures the Prometheus compatible API the usage of tenants is
+// queried from and how limits are recommended from it.
+type UsageSpec struct {
+	// PrometheusURL is the address of a Prometheus compatible query API holding
+	// the metrics of Mimir and Loki.
+	// +kubebuilder:validation:Required
+	PrometheusURL string `json:"prometheusURL"`
+
+	// OrgID is sent as the X-Scope-OrgID header when the query API is multi-tenant.
+	// +kubebuilder:validation:Optional
+	OrgID string `json:"orgID,omitempty"`
 
-// 	WriteRemote string `json:"writeRemote,omitempty"`
-// }
+	// Headroom is the percentage added to the peak usage of a tenant when recommending limits.
+	// +kubebuilder:default:=20
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Optional
+	Headroom *int32 `json:"headroom,omitempty"`
+
+	// Window is the period the peak usage of a tenant is taken over.
+	// +kubebuilder:default:="24h"
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	Window *metav1.Duration `json:"window,omitempty"`
+
+	// Interval is how often the usage of a tenant is queried.
+	// +kubebuilder:default:="1h"
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Queries overrides the PromQL queries used to measure usage.
+	// +kubebuilder:validation:Optional
+	Queries *UsageQueries `json:"queries,omitempty"`
+}
+
+// UsageQueries are Go templates of PromQL queries returning a single value.
+// {{.TenantID}} is replaced by the tenant ID and {{.Window}} by the usage window.
+type UsageQueries struct {
+	// ActiveSeries measures the peak number of active series of a tenant in Mimir.
+	// +kubebuilder:validation:Optional
+	ActiveSeries string `json:"activeSeries,omitempty"`
+
+	// IngestionRate measures the peak rate of samples per second a tenant sends to Mimir.
+	// +kubebuilder:validation:Optional
+	IngestionRate string `json:"ingestionRate,omitempty"`
+
+	// IngestionRateMB measures the peak rate of MB per second a tenant sends to Loki.
+	// +kubebuilder:validation:Optional
+	IngestionRateMB string `json:"ingestionRateMB,omitempty"`
+
+	// DiscardedSamples measures the number of samples of a tenant Mimir discarded.
+	// +kubebuilder:validation:Optional
+	DiscardedSamples string `json:"discardedSamples,omitempty"`
+}
 
 // ConfigStatus defines the observed state of Config
 type ConfigStatus struct {
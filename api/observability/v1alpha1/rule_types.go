@@ -0,0 +1,75 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// RuleGroup is a group of rules evaluated together, as in the spec of a
+// PrometheusRule.
+type RuleGroup struct {
+	// Name of the rule group, unique within its TenantRuleGroup.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name" yaml:"name"`
+
+	// Interval is how often the rules of the group are evaluated.
+	// +kubebuilder:validation:Optional
+	Interval *RuleDuration `json:"interval,omitempty" yaml:"interval,omitempty"`
+
+	// Limit is the number of alerts an alerting rule and series a recording
+	// rule of the group may produce. 0 is no limit.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	Limit *int `json:"limit,omitempty" yaml:"limit,omitempty"`
+
+	// Rules of the group.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Rules []Rule `json:"rules" yaml:"rules"`
+}
+
+// Rule is an alerting or recording rule, as in the spec of a PrometheusRule.
+// +kubebuilder:validation:XValidation:rule="has(self.record) != has(self.alert)",message="exactly one of record and alert must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.record) || (!has(self.annotations) && !has(self.__for__))",message="for and annotations are only supported by alerting rules"
+type Rule struct {
+	// Record is the name of the series the recording rule writes.
+	// +kubebuilder:validation:Optional
+	Record string `json:"record,omitempty" yaml:"record,omitempty"`
+
+	// Alert is the name of the alert the alerting rule fires.
+	// +kubebuilder:validation:Optional
+	Alert string `json:"alert,omitempty" yaml:"alert,omitempty"`
+
+	// Expr is the query evaluated by the rule.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Expr string `json:"expr" yaml:"expr"`
+
+	// For is how long the alert has to be pending before it fires.
+	// +kubebuilder:validation:Optional
+	For *RuleDuration `json:"for,omitempty" yaml:"for,omitempty"`
+
+	// Labels are added to the recorded series or fired alerts.
+	// +kubebuilder:validation:Optional
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+
+	// Annotations are added to the fired alerts.
+	// +kubebuilder:validation:Optional
+	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+}
+
+// RuleDuration is a duration in the format used by Prometheus, e.g. 30s or 1h30m.
+// +kubebuilder:validation:Pattern="^(0|(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$"
+type RuleDuration string
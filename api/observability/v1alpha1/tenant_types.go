@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	crhelperTypes "github.com/pluralsh/controller-reconcile-helper/pkg/types"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -32,8 +33,56 @@ type TenantSpec struct {
 	// DisplayName is a human readable name for the tenant
 	DisplayName string `json:"displayName,omitempty"`
 
+	// TenantID is the ID of the tenant in Mimir, Loki and Tempo, as sent in the
+	// X-Scope-OrgID header. Defaults to the name of the Tenant and cannot be
+	// changed once set.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=150
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9!_.*'()-]+$`
+	// +kubebuilder:validation:XValidation:rule="self != '.' && self != '..'",message="tenantID must not be . or .."
+	TenantID string `json:"tenantID,omitempty"`
+
+	// Parent is the name of the Tenant this tenant inherits its limits and
+	// access grants from. Limits set on the tenant override the inherited ones.
+	// +kubebuilder:validation:Optional
+	Parent string `json:"parent,omitempty"`
+
 	// Limits is the set of limits for the tenant
 	Limits *LimitSpec `json:"limits,omitempty"`
+
+	// Suspended cuts the tenant off without deleting it. The ingestion rate
+	// and burst limits of the tenant are rendered as zero while the configured
+	// limits are kept, so that resuming the tenant restores them.
+	// +kubebuilder:validation:Optional
+	Suspended bool `json:"suspended,omitempty"`
+
+	// BlockQueriesWhenSuspended also blocks every Mimir and Loki query of the
+	// tenant while it is suspended.
+	// +kubebuilder:validation:Optional
+	BlockQueriesWhenSuspended bool `json:"blockQueriesWhenSuspended,omitempty"`
+
+	// TemporaryLimits are merged on top of the limits of the tenant between
+	// their start and expiry, in order.
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=name
+	TemporaryLimits []TemporaryLimitSpec `json:"temporaryLimits,omitempty"`
+
+	// Budget caps the sum of limits over the children of the tenant.
+	// +kubebuilder:validation:Optional
+	Budget *BudgetSpec `json:"budget,omitempty"`
+
+	// Autoscale scales limits of the tenant to its usage.
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=limit
+	Autoscale []AutoscaleSpec `json:"autoscale,omitempty"`
+
+	// QueryFederation lists the names of the other Tenants whose data this
+	// tenant may read through cross-tenant queries.
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	QueryFederation []string `json:"queryFederation,omitempty"`
 }
 
 // Defines the limits for a tenant
@@ -50,13 +99,167 @@ type LimitSpec struct {
 	Tempo *TempoLimits `json:"tempo,omitempty"`
 }
 
+// TemporaryLimitSpec is a set of limits that only applies for a period of time.
+// +kubebuilder:validation:XValidation:rule="!has(self.start) || timestamp(self.expiry) > timestamp(self.start)",message="expiry must be after start"
+type TemporaryLimitSpec struct {
+	// Name identifies the temporary limits in the status of the tenant.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Reason describes why the temporary limits are needed, e.g. a load test.
+	// +kubebuilder:validation:Optional
+	Reason string `json:"reason,omitempty"`
+
+	// Start is when the limits start to apply. Defaults to immediately.
+	// +kubebuilder:validation:Optional
+	Start *metav1.Time `json:"start,omitempty"`
+
+	// Expiry is when the limits stop to apply.
+	// +kubebuilder:validation:Required
+	Expiry metav1.Time `json:"expiry"`
+
+	// Limits are merged on top of the limits of the tenant while active.
+	// +kubebuilder:validation:Required
+	Limits LimitSpec `json:"limits"`
+}
+
+// TemporaryLimitState is the state of temporary limits.
+// +kubebuilder:validation:Enum=Pending;Active;Expired;Removed
+type TemporaryLimitState string
+
+const (
+	TemporaryLimitPending TemporaryLimitState = "Pending"
+	TemporaryLimitActive  TemporaryLimitState = "Active"
+	TemporaryLimitExpired TemporaryLimitState = "Expired"
+	// TemporaryLimitRemoved is the state of temporary limits removed from the
+	// spec before they expired.
+	TemporaryLimitRemoved TemporaryLimitState = "Removed"
+)
+
+// TemporaryLimitStatus records the history of temporary limits.
+type TemporaryLimitStatus struct {
+	Name  string              `json:"name"`
+	State TemporaryLimitState `json:"state"`
+
+	// ActivatedAt is when the limits were first applied.
+	// +optional
+	ActivatedAt *metav1.Time `json:"activatedAt,omitempty"`
+
+	// DeactivatedAt is when the limits stopped being applied.
+	// +optional
+	DeactivatedAt *metav1.Time `json:"deactivatedAt,omitempty"`
+}
+
 // TenantStatus defines the observed state of Tenant
 type TenantStatus struct {
 	// Conditions defines current service state of the PacketMachine.
 	// +optional
 	Conditions crhelperTypes.Conditions `json:"conditions,omitempty"`
+
+	// GatewayCredentials references the Secret holding the gateway credentials of the tenant.
+	// +optional
+	GatewayCredentials *corev1.SecretReference `json:"gatewayCredentials,omitempty"`
+
+	// TemporaryLimits records the history of the temporary limits of the tenant.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	TemporaryLimits []TemporaryLimitStatus `json:"temporaryLimits,omitempty"`
+
+	// Autoscale is the state of the autoscaled limits of the tenant.
+	// +optional
+	// +listType=map
+	// +listMapKey=limit
+	Autoscale []AutoscaleStatus `json:"autoscale,omitempty"`
+
+	// Recommendations are limits suggested from the usage of the tenant.
+	// +optional
+	Recommendations *Recommendations `json:"recommendations,omitempty"`
+}
+
+// Recommendations are limits suggested from the peak usage of a tenant.
+type Recommendations struct {
+	// UpdatedAt is when the usage of the tenant was last queried.
+	UpdatedAt metav1.Time `json:"updatedAt"`
+
+	// Limits are the recommended limits.
+	// +optional
+	// +listType=map
+	// +listMapKey=limit
+	Limits []LimitRecommendation `json:"limits,omitempty"`
+
+	// DiscardedSamples is the number of samples of the tenant Mimir discarded over the usage window.
+	// +optional
+	DiscardedSamples *float64 `json:"discardedSamples,omitempty"`
 }
 
+// LimitRecommendation is a limit suggested from the peak usage of a tenant.
+type LimitRecommendation struct {
+	// Limit is the path of the limit in the limits of the tenant, e.g. mimir.max_global_series_per_user.
+	Limit string `json:"limit"`
+
+	// Usage is the peak usage of the tenant.
+	Usage float64 `json:"usage"`
+
+	// Current is the limit currently configured for the tenant, if any.
+	// +optional
+	Current *float64 `json:"current,omitempty"`
+
+	// Recommended is the peak usage of the tenant with headroom.
+	Recommended float64 `json:"recommended"`
+}
+
+const (
+	// KetoReadyCondition reports on whether the Tenant has been registered in Keto.
+	KetoReadyCondition crhelperTypes.ConditionType = "KetoReady"
+
+	// KetoUnavailableReason used when the Tenant could not be registered because Keto is unavailable.
+	KetoUnavailableReason = "KetoUnavailable"
+
+	// QueryFederationReadyCondition reports on whether every tenant in the query federation exists.
+	QueryFederationReadyCondition crhelperTypes.ConditionType = "QueryFederationReady"
+
+	// FederatedTenantNotFoundReason used when a tenant in the query federation does not exist.
+	FederatedTenantNotFoundReason = "FederatedTenantNotFound"
+
+	// HierarchyReadyCondition reports on whether the parents of the Tenant could be resolved.
+	HierarchyReadyCondition crhelperTypes.ConditionType = "HierarchyReady"
+
+	// ParentNotFoundReason used when the parent of the Tenant, or one of its ancestors, does not exist.
+	ParentNotFoundReason = "ParentNotFound"
+
+	// ParentCycleReason used when the Tenant is its own ancestor.
+	ParentCycleReason = "ParentCycle"
+
+	// BudgetReadyCondition reports on whether the children of the Tenant stay within its budget.
+	BudgetReadyCondition crhelperTypes.ConditionType = "BudgetReady"
+
+	// BudgetExceededReason used when the limits of the children of the Tenant exceed its budget.
+	BudgetExceededReason = "BudgetExceeded"
+
+	// SuspendedCondition reports on whether the Tenant is suspended.
+	SuspendedCondition crhelperTypes.ConditionType = "Suspended"
+
+	// RecommendationsReadyCondition reports on whether the usage of the Tenant could be queried.
+	RecommendationsReadyCondition crhelperTypes.ConditionType = "RecommendationsReady"
+
+	// UsageUnavailableReason used when the usage of the Tenant could not be queried.
+	UsageUnavailableReason = "UsageUnavailable"
+
+	// RuntimeConfigReadyCondition reports on whether the runtime configurations
+	// holding the limits of the Tenant can be written under the unowned keys
+	// policy of the Config.
+	RuntimeConfigReadyCondition crhelperTypes.ConditionType = "RuntimeConfigReady"
+
+	// UnownedKeysReason used when a runtime configuration is not written because
+	// it has keys the controller does not own.
+	UnownedKeysReason = "UnownedKeys"
+
+	// RotateCredentialsAnnotation triggers a rotation of the gateway credentials
+	// of a Tenant whenever its value changes.
+	RotateCredentialsAnnotation = "observability.traceshield.io/rotate-credentials"
+)
+
 //+genclient
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
@@ -64,6 +267,7 @@ type TenantStatus struct {
 
 // +genclient:nonNamespaced
 // Tenant is the Schema for the tenants API
+// +kubebuilder:validation:XValidation:rule="(has(oldSelf.spec) && has(oldSelf.spec.tenantID) ? oldSelf.spec.tenantID : oldSelf.metadata.name) == (has(self.spec) && has(self.spec.tenantID) ? self.spec.tenantID : self.metadata.name)",message="spec.tenantID is immutable"
 type Tenant struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -81,6 +285,14 @@ type TenantList struct {
 	Items           []Tenant `json:"items"`
 }
 
+// GetTenantID returns the ID of the tenant in Mimir, Loki and Tempo.
+func (t *Tenant) GetTenantID() string {
+	if t.Spec.TenantID != "" {
+		return t.Spec.TenantID
+	}
+	return t.Name
+}
+
 // GetConditions returns the list of conditions for a WireGuardServer API object.
 func (t *Tenant) GetConditions() crhelperTypes.Conditions {
 	return t.Status.Conditions
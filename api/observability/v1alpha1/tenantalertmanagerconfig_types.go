@@ -0,0 +1,121 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	crhelperTypes "github.com/pluralsh/controller-reconcile-helper/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TenantAlertmanagerConfigSpec defines the desired state of TenantAlertmanagerConfig
+type TenantAlertmanagerConfigSpec struct {
+	// Tenant is the name of the Tenant the Alertmanager is configured for.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="tenant is immutable"
+	Tenant string `json:"tenant"`
+
+	// Config is the Alertmanager configuration of the tenant in the format of
+	// the Alertmanager configuration file, with its global, route, receivers,
+	// inhibit_rules and time_intervals sections.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	Config WrappedMap `json:"config"`
+
+	// Templates are the notification templates of the tenant keyed by file name.
+	// +kubebuilder:validation:Optional
+	Templates map[string]string `json:"templates,omitempty"`
+}
+
+// TenantAlertmanagerConfigStatus defines the observed state of TenantAlertmanagerConfig
+type TenantAlertmanagerConfigStatus struct {
+	// Conditions defines current service state of the TenantAlertmanagerConfig.
+	// +optional
+	Conditions crhelperTypes.Conditions `json:"conditions,omitempty"`
+
+	// ObservedGeneration is the generation of the TenantAlertmanagerConfig that was last synced.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// TenantID is the ID of the tenant the configuration is synced for.
+	// +optional
+	TenantID string `json:"tenantID,omitempty"`
+
+	// LastSyncTime is when the configuration was last synced to the Alertmanager.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+const (
+	// AlertmanagerConfigSyncedCondition reports on whether the configuration has been synced to the Alertmanager.
+	AlertmanagerConfigSyncedCondition crhelperTypes.ConditionType = "AlertmanagerConfigSynced"
+
+	// AlertmanagerConfigConflictReason used when another TenantAlertmanagerConfig already configures the tenant.
+	AlertmanagerConfigConflictReason = "AlertmanagerConfigConflict"
+
+	// AlertmanagerLimitExceededReason used when the configuration exceeds the Alertmanager limits of the tenant.
+	AlertmanagerLimitExceededReason = "AlertmanagerLimitExceeded"
+
+	// ReceiverBlockedReason used when a receiver sends to a network that is blocked for the tenant.
+	ReceiverBlockedReason = "ReceiverBlocked"
+
+	// InvalidAlertmanagerConfigReason used when the configuration is invalid or the Alertmanager rejected it.
+	InvalidAlertmanagerConfigReason = "InvalidAlertmanagerConfig"
+
+	// AlertmanagerUnavailableReason used when the Alertmanager could not be reached.
+	AlertmanagerUnavailableReason = "AlertmanagerUnavailable"
+)
+
+//+genclient
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Tenant",type=string,JSONPath=`.spec.tenant`
+//+kubebuilder:printcolumn:name="Synced",type=string,JSONPath=`.status.conditions[?(@.type=="AlertmanagerConfigSynced")].status`
+
+// TenantAlertmanagerConfig is the Schema for the tenantalertmanagerconfigs API
+type TenantAlertmanagerConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TenantAlertmanagerConfigSpec   `json:"spec,omitempty"`
+	Status TenantAlertmanagerConfigStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// TenantAlertmanagerConfigList contains a list of TenantAlertmanagerConfig
+type TenantAlertmanagerConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TenantAlertmanagerConfig `json:"items"`
+}
+
+// GetConditions returns the list of conditions for a TenantAlertmanagerConfig API object.
+func (t *TenantAlertmanagerConfig) GetConditions() crhelperTypes.Conditions {
+	return t.Status.Conditions
+}
+
+// SetConditions will set the given conditions on a TenantAlertmanagerConfig object.
+func (t *TenantAlertmanagerConfig) SetConditions(conditions crhelperTypes.Conditions) {
+	t.Status.Conditions = conditions
+}
+
+func init() {
+	SchemeBuilder.Register(&TenantAlertmanagerConfig{}, &TenantAlertmanagerConfigList{})
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	crhelperTypes "github.com/pluralsh/controller-reconcile-helper/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TenantRuleGroupSpec defines the desired state of TenantRuleGroup
+type TenantRuleGroupSpec struct {
+	// Tenant is the name of the Tenant the rules are evaluated for.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="tenant is immutable"
+	Tenant string `json:"tenant"`
+
+	// Ruler is the ruler the rule groups are synced to. Mimir evaluates
+	// PromQL rules on metrics and Loki evaluates LogQL rules on logs.
+	// +kubebuilder:default:=Mimir
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="ruler is immutable"
+	Ruler RulerType `json:"ruler,omitempty"`
+
+	// Groups are the rule groups synced to the ruler of the tenant.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +listType=map
+	// +listMapKey=name
+	Groups []RuleGroup `json:"groups"`
+}
+
+// RulerType is a ruler rule groups can be synced to.
+// +kubebuilder:validation:Enum=Mimir;Loki
+type RulerType string
+
+const (
+	// RulerTypeMimir syncs PromQL rule groups to the Mimir ruler.
+	RulerTypeMimir RulerType = "Mimir"
+
+	// RulerTypeLoki syncs LogQL rule groups to the Loki ruler.
+	RulerTypeLoki RulerType = "Loki"
+)
+
+// TenantRuleGroupStatus defines the observed state of TenantRuleGroup
+type TenantRuleGroupStatus struct {
+	// Conditions defines current service state of the TenantRuleGroup.
+	// +optional
+	Conditions crhelperTypes.Conditions `json:"conditions,omitempty"`
+
+	// ObservedGeneration is the generation of the TenantRuleGroup that was last synced.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// TenantID is the ID of the tenant the rule groups are synced for.
+	// +optional
+	TenantID string `json:"tenantID,omitempty"`
+
+	// Namespace is the ruler namespace the rule groups are synced to.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// LastSyncTime is when the rule groups were last synced to the ruler.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+const (
+	// RulesSyncedCondition reports on whether the rule groups have been synced to the ruler.
+	RulesSyncedCondition crhelperTypes.ConditionType = "RulesSynced"
+
+	// TenantNotFoundReason used when the referenced Tenant does not exist.
+	TenantNotFoundReason = "TenantNotFound"
+
+	// RulerLimitExceededReason used when the rule groups exceed the ruler limits of the tenant.
+	RulerLimitExceededReason = "RulerLimitExceeded"
+
+	// InvalidRulesReason used when the rule groups are invalid or the ruler rejected them.
+	InvalidRulesReason = "InvalidRules"
+
+	// RulerUnavailableReason used when the ruler could not be reached.
+	RulerUnavailableReason = "RulerUnavailable"
+)
+
+//+genclient
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Tenant",type=string,JSONPath=`.spec.tenant`
+//+kubebuilder:printcolumn:name="Ruler",type=string,JSONPath=`.spec.ruler`
+//+kubebuilder:printcolumn:name="Synced",type=string,JSONPath=`.status.conditions[?(@.type=="RulesSynced")].status`
+
+// TenantRuleGroup is the Schema for the tenantrulegroups API
+type TenantRuleGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TenantRuleGroupSpec   `json:"spec,omitempty"`
+	Status TenantRuleGroupStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// TenantRuleGroupList contains a list of TenantRuleGroup
+type TenantRuleGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TenantRuleGroup `json:"items"`
+}
+
+// GetRuler returns the ruler the rule groups are synced to, which is Mimir
+// unless set otherwise.
+func (t *TenantRuleGroup) GetRuler() RulerType {
+	if t.Spec.Ruler == "" {
+		return RulerTypeMimir
+	}
+	return t.Spec.Ruler
+}
+
+// GetConditions returns the list of conditions for a TenantRuleGroup API object.
+func (t *TenantRuleGroup) GetConditions() crhelperTypes.Conditions {
+	return t.Status.Conditions
+}
+
+// SetConditions will set the given conditions on a TenantRuleGroup object.
+func (t *TenantRuleGroup) SetConditions(conditions crhelperTypes.Conditions) {
+	t.Status.Conditions = conditions
+}
+
+func init() {
+	SchemeBuilder.Register(&TenantRuleGroup{}, &TenantRuleGroupList{})
+}
@@ -24,10 +24,108 @@ package v1alpha1
 import (
 	"github.com/pluralsh/controller-reconcile-helper/pkg/types"
 	"github.com/prometheus/common/config"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertmanagerSpec) DeepCopyInto(out *AlertmanagerSpec) {
+	*out = *in
+	if in.SyncInterval != nil {
+		in, out := &in.SyncInterval, &out.SyncInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerSpec.
+func (in *AlertmanagerSpec) DeepCopy() *AlertmanagerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertmanagerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscaleEvent) DeepCopyInto(out *AutoscaleEvent) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(float64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscaleEvent.
+func (in *AutoscaleEvent) DeepCopy() *AutoscaleEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscaleEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscaleSpec) DeepCopyInto(out *AutoscaleSpec) {
+	*out = *in
+	if in.TargetUtilization != nil {
+		in, out := &in.TargetUtilization, &out.TargetUtilization
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Cooldown != nil {
+		in, out := &in.Cooldown, &out.Cooldown
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscaleSpec.
+func (in *AutoscaleSpec) DeepCopy() *AutoscaleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscaleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscaleStatus) DeepCopyInto(out *AutoscaleStatus) {
+	*out = *in
+	if in.Baseline != nil {
+		in, out := &in.Baseline, &out.Baseline
+		*out = new(float64)
+		**out = **in
+	}
+	if in.LastScaleTime != nil {
+		in, out := &in.LastScaleTime, &out.LastScaleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]AutoscaleEvent, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscaleStatus.
+func (in *AutoscaleStatus) DeepCopy() *AutoscaleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscaleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BlockedQuery) DeepCopyInto(out *BlockedQuery) {
 	*out = *in
@@ -82,6 +180,31 @@ func (in BlockedQueryTypes) DeepCopy() BlockedQueryTypes {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BudgetSpec) DeepCopyInto(out *BudgetSpec) {
+	*out = *in
+	if in.Mimir != nil {
+		in, out := &in.Mimir, &out.Mimir
+		*out = new(MimirBudget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Loki != nil {
+		in, out := &in.Loki, &out.Loki
+		*out = new(LokiBudget)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BudgetSpec.
+func (in *BudgetSpec) DeepCopy() *BudgetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BudgetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Config) DeepCopyInto(out *Config) {
 	*out = *in
@@ -174,6 +297,26 @@ func (in *ConfigSpec) DeepCopyInto(out *ConfigSpec) {
 		*out = new(TempoSpec)
 		**out = **in
 	}
+	if in.Keto != nil {
+		in, out := &in.Keto, &out.Keto
+		*out = new(KetoSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(GatewaySpec)
+		**out = **in
+	}
+	if in.QueryFederation != nil {
+		in, out := &in.QueryFederation, &out.QueryFederation
+		*out = new(QueryFederationSpec)
+		**out = **in
+	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(UsageSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigSpec.
@@ -256,6 +399,21 @@ func (in *FilterPolicy) DeepCopy() *FilterPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewaySpec) DeepCopyInto(out *GatewaySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewaySpec.
+func (in *GatewaySpec) DeepCopy() *GatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPClientConfig) DeepCopyInto(out *HTTPClientConfig) {
 	*out = *in
@@ -302,6 +460,46 @@ func (in *HTTPClientConfig) DeepCopy() *HTTPClientConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KetoSpec) DeepCopyInto(out *KetoSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KetoSpec.
+func (in *KetoSpec) DeepCopy() *KetoSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KetoSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LimitRecommendation) DeepCopyInto(out *LimitRecommendation) {
+	*out = *in
+	if in.Current != nil {
+		in, out := &in.Current, &out.Current
+		*out = new(float64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LimitRecommendation.
+func (in *LimitRecommendation) DeepCopy() *LimitRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(LimitRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LimitSpec) DeepCopyInto(out *LimitSpec) {
 	*out = *in
@@ -332,6 +530,36 @@ func (in *LimitSpec) DeepCopy() *LimitSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LokiBudget) DeepCopyInto(out *LokiBudget) {
+	*out = *in
+	if in.IngestionRateMB != nil {
+		in, out := &in.IngestionRateMB, &out.IngestionRateMB
+		*out = new(float64)
+		**out = **in
+	}
+	if in.IngestionBurstSizeMB != nil {
+		in, out := &in.IngestionBurstSizeMB, &out.IngestionBurstSizeMB
+		*out = new(float64)
+		**out = **in
+	}
+	if in.MaxGlobalStreamsPerUser != nil {
+		in, out := &in.MaxGlobalStreamsPerUser, &out.MaxGlobalStreamsPerUser
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LokiBudget.
+func (in *LokiBudget) DeepCopy() *LokiBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(LokiBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LokiConfigSpec) DeepCopyInto(out *LokiConfigSpec) {
 	*out = *in
@@ -666,6 +894,10 @@ func (in *LokiLimits) DeepCopyInto(out *LokiLimits) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.Extra != nil {
+		in, out := &in.Extra, &out.Extra
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LokiLimits.
@@ -977,6 +1209,10 @@ func (in *LokiLimitsInput) DeepCopyInto(out *LokiLimitsInput) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.Extra != nil {
+		in, out := &in.Extra, &out.Extra
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LokiLimitsInput.
@@ -1033,6 +1269,11 @@ func (in *LokiSpec) DeepCopyInto(out *LokiSpec) {
 		*out = new(LokiConfigSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Ruler != nil {
+		in, out := &in.Ruler, &out.Ruler
+		*out = new(RulerSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LokiSpec.
@@ -1091,6 +1332,61 @@ func (in *MetadataConfig) DeepCopy() *MetadataConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MimirBlockedQuery) DeepCopyInto(out *MimirBlockedQuery) {
+	*out = *in
+	if in.Pattern != nil {
+		in, out := &in.Pattern, &out.Pattern
+		*out = new(string)
+		**out = **in
+	}
+	if in.Regex != nil {
+		in, out := &in.Regex, &out.Regex
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirBlockedQuery.
+func (in *MimirBlockedQuery) DeepCopy() *MimirBlockedQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(MimirBlockedQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MimirBudget) DeepCopyInto(out *MimirBudget) {
+	*out = *in
+	if in.IngestionRate != nil {
+		in, out := &in.IngestionRate, &out.IngestionRate
+		*out = new(float64)
+		**out = **in
+	}
+	if in.IngestionBurstSize != nil {
+		in, out := &in.IngestionBurstSize, &out.IngestionBurstSize
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxGlobalSeriesPerUser != nil {
+		in, out := &in.MaxGlobalSeriesPerUser, &out.MaxGlobalSeriesPerUser
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirBudget.
+func (in *MimirBudget) DeepCopy() *MimirBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(MimirBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MimirConfigSpec) DeepCopyInto(out *MimirConfigSpec) {
 	*out = *in
@@ -1408,6 +1704,13 @@ func (in *MimirLimits) DeepCopyInto(out *MimirLimits) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.BlockedQueries != nil {
+		in, out := &in.BlockedQueries, &out.BlockedQueries
+		*out = make([]MimirBlockedQuery, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.CardinalityAnalysisEnabled != nil {
 		in, out := &in.CardinalityAnalysisEnabled, &out.CardinalityAnalysisEnabled
 		*out = new(bool)
@@ -1575,6 +1878,10 @@ func (in *MimirLimits) DeepCopyInto(out *MimirLimits) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.Extra != nil {
+		in, out := &in.Extra, &out.Extra
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirLimits.
@@ -1839,6 +2146,13 @@ func (in *MimirLimitsInput) DeepCopyInto(out *MimirLimitsInput) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.BlockedQueries != nil {
+		in, out := &in.BlockedQueries, &out.BlockedQueries
+		*out = make([]MimirBlockedQuery, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.CardinalityAnalysisEnabled != nil {
 		in, out := &in.CardinalityAnalysisEnabled, &out.CardinalityAnalysisEnabled
 		*out = new(bool)
@@ -2006,6 +2320,10 @@ func (in *MimirLimitsInput) DeepCopyInto(out *MimirLimitsInput) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.Extra != nil {
+		in, out := &in.Extra, &out.Extra
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirLimitsInput.
@@ -2027,6 +2345,16 @@ func (in *MimirSpec) DeepCopyInto(out *MimirSpec) {
 		*out = new(MimirConfigSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Ruler != nil {
+		in, out := &in.Ruler, &out.Ruler
+		*out = new(RulerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Alertmanager != nil {
+		in, out := &in.Alertmanager, &out.Alertmanager
+		*out = new(AlertmanagerSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirSpec.
@@ -2314,6 +2642,22 @@ func (in *ProxyConfig) DeepCopy() *ProxyConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryFederationSpec) DeepCopyInto(out *QueryFederationSpec) {
+	*out = *in
+	out.ConfigMap = in.ConfigMap
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryFederationSpec.
+func (in *QueryFederationSpec) DeepCopy() *QueryFederationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryFederationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *QueueConfig) DeepCopyInto(out *QueueConfig) {
 	*out = *in
@@ -2370,22 +2714,50 @@ func (in *QueueConfig) DeepCopy() *QueueConfig {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RelabelConfig) DeepCopyInto(out *RelabelConfig) {
+func (in *Recommendations) DeepCopyInto(out *Recommendations) {
 	*out = *in
-	if in.SourceLabels != nil {
-		in, out := &in.SourceLabels, &out.SourceLabels
-		*out = make([]LabelName, len(*in))
-		copy(*out, *in)
+	in.UpdatedAt.DeepCopyInto(&out.UpdatedAt)
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = make([]LimitRecommendation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.Separator != nil {
-		in, out := &in.Separator, &out.Separator
-		*out = new(string)
+	if in.DiscardedSamples != nil {
+		in, out := &in.DiscardedSamples, &out.DiscardedSamples
+		*out = new(float64)
 		**out = **in
 	}
-	if in.Regex != nil {
-		in, out := &in.Regex, &out.Regex
-		*out = new(string)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Recommendations.
+func (in *Recommendations) DeepCopy() *Recommendations {
+	if in == nil {
+		return nil
+	}
+	out := new(Recommendations)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RelabelConfig) DeepCopyInto(out *RelabelConfig) {
+	*out = *in
+	if in.SourceLabels != nil {
+		in, out := &in.SourceLabels, &out.SourceLabels
+		*out = make([]LabelName, len(*in))
+		copy(*out, *in)
+	}
+	if in.Separator != nil {
+		in, out := &in.Separator, &out.Separator
+		*out = new(string)
+		**out = **in
+	}
+	if in.Regex != nil {
+		in, out := &in.Regex, &out.Regex
+		*out = new(string)
+		**out = **in
 	}
 	if in.Modulus != nil {
 		in, out := &in.Modulus, &out.Modulus
@@ -2488,6 +2860,72 @@ func (in *RemoteWriteSpec) DeepCopy() *RemoteWriteSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Rule) DeepCopyInto(out *Rule) {
+	*out = *in
+	if in.For != nil {
+		in, out := &in.For, &out.For
+		*out = new(RuleDuration)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Rule.
+func (in *Rule) DeepCopy() *Rule {
+	if in == nil {
+		return nil
+	}
+	out := new(Rule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleGroup) DeepCopyInto(out *RuleGroup) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(RuleDuration)
+		**out = **in
+	}
+	if in.Limit != nil {
+		in, out := &in.Limit, &out.Limit
+		*out = new(int)
+		**out = **in
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]Rule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleGroup.
+func (in *RuleGroup) DeepCopy() *RuleGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RulerAlertManagerConfig) DeepCopyInto(out *RulerAlertManagerConfig) {
 	*out = *in
@@ -2540,6 +2978,26 @@ func (in *RulerAlertManagerConfig) DeepCopy() *RulerAlertManagerConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RulerSpec) DeepCopyInto(out *RulerSpec) {
+	*out = *in
+	if in.SyncInterval != nil {
+		in, out := &in.SyncInterval, &out.SyncInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RulerSpec.
+func (in *RulerSpec) DeepCopy() *RulerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RulerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ShardstreamsConfig) DeepCopyInto(out *ShardstreamsConfig) {
 	*out = *in
@@ -2819,6 +3277,10 @@ func (in *TempoLimits) DeepCopyInto(out *TempoLimits) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.Extra != nil {
+		in, out := &in.Extra, &out.Extra
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TempoLimits.
@@ -3010,6 +3472,10 @@ func (in *TempoLimitsInput) DeepCopyInto(out *TempoLimitsInput) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.Extra != nil {
+		in, out := &in.Extra, &out.Extra
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TempoLimitsInput.
@@ -3038,6 +3504,50 @@ func (in *TempoSpec) DeepCopy() *TempoSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemporaryLimitSpec) DeepCopyInto(out *TemporaryLimitSpec) {
+	*out = *in
+	if in.Start != nil {
+		in, out := &in.Start, &out.Start
+		*out = (*in).DeepCopy()
+	}
+	in.Expiry.DeepCopyInto(&out.Expiry)
+	in.Limits.DeepCopyInto(&out.Limits)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemporaryLimitSpec.
+func (in *TemporaryLimitSpec) DeepCopy() *TemporaryLimitSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TemporaryLimitSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemporaryLimitStatus) DeepCopyInto(out *TemporaryLimitStatus) {
+	*out = *in
+	if in.ActivatedAt != nil {
+		in, out := &in.ActivatedAt, &out.ActivatedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.DeactivatedAt != nil {
+		in, out := &in.DeactivatedAt, &out.DeactivatedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemporaryLimitStatus.
+func (in *TemporaryLimitStatus) DeepCopy() *TemporaryLimitStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TemporaryLimitStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Tenant) DeepCopyInto(out *Tenant) {
 	*out = *in
@@ -3065,6 +3575,114 @@ func (in *Tenant) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantAlertmanagerConfig) DeepCopyInto(out *TenantAlertmanagerConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantAlertmanagerConfig.
+func (in *TenantAlertmanagerConfig) DeepCopy() *TenantAlertmanagerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantAlertmanagerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantAlertmanagerConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantAlertmanagerConfigList) DeepCopyInto(out *TenantAlertmanagerConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TenantAlertmanagerConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantAlertmanagerConfigList.
+func (in *TenantAlertmanagerConfigList) DeepCopy() *TenantAlertmanagerConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantAlertmanagerConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantAlertmanagerConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantAlertmanagerConfigSpec) DeepCopyInto(out *TenantAlertmanagerConfigSpec) {
+	*out = *in
+	in.Config.DeepCopyInto(&out.Config)
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantAlertmanagerConfigSpec.
+func (in *TenantAlertmanagerConfigSpec) DeepCopy() *TenantAlertmanagerConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantAlertmanagerConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantAlertmanagerConfigStatus) DeepCopyInto(out *TenantAlertmanagerConfigStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(types.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantAlertmanagerConfigStatus.
+func (in *TenantAlertmanagerConfigStatus) DeepCopy() *TenantAlertmanagerConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantAlertmanagerConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TenantList) DeepCopyInto(out *TenantList) {
 	*out = *in
@@ -3097,6 +3715,113 @@ func (in *TenantList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantRuleGroup) DeepCopyInto(out *TenantRuleGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantRuleGroup.
+func (in *TenantRuleGroup) DeepCopy() *TenantRuleGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantRuleGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantRuleGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantRuleGroupList) DeepCopyInto(out *TenantRuleGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TenantRuleGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantRuleGroupList.
+func (in *TenantRuleGroupList) DeepCopy() *TenantRuleGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantRuleGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantRuleGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantRuleGroupSpec) DeepCopyInto(out *TenantRuleGroupSpec) {
+	*out = *in
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]RuleGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantRuleGroupSpec.
+func (in *TenantRuleGroupSpec) DeepCopy() *TenantRuleGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantRuleGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantRuleGroupStatus) DeepCopyInto(out *TenantRuleGroupStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(types.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantRuleGroupStatus.
+func (in *TenantRuleGroupStatus) DeepCopy() *TenantRuleGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantRuleGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TenantSpec) DeepCopyInto(out *TenantSpec) {
 	*out = *in
@@ -3105,6 +3830,30 @@ func (in *TenantSpec) DeepCopyInto(out *TenantSpec) {
 		*out = new(LimitSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TemporaryLimits != nil {
+		in, out := &in.TemporaryLimits, &out.TemporaryLimits
+		*out = make([]TemporaryLimitSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Budget != nil {
+		in, out := &in.Budget, &out.Budget
+		*out = new(BudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Autoscale != nil {
+		in, out := &in.Autoscale, &out.Autoscale
+		*out = make([]AutoscaleSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.QueryFederation != nil {
+		in, out := &in.QueryFederation, &out.QueryFederation
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantSpec.
@@ -3127,6 +3876,30 @@ func (in *TenantStatus) DeepCopyInto(out *TenantStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.GatewayCredentials != nil {
+		in, out := &in.GatewayCredentials, &out.GatewayCredentials
+		*out = new(corev1.SecretReference)
+		**out = **in
+	}
+	if in.TemporaryLimits != nil {
+		in, out := &in.TemporaryLimits, &out.TemporaryLimits
+		*out = make([]TemporaryLimitStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Autoscale != nil {
+		in, out := &in.Autoscale, &out.Autoscale
+		*out = make([]AutoscaleStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Recommendations != nil {
+		in, out := &in.Recommendations, &out.Recommendations
+		*out = new(Recommendations)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantStatus.
@@ -3138,3 +3911,53 @@ func (in *TenantStatus) DeepCopy() *TenantStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UsageQueries) DeepCopyInto(out *UsageQueries) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UsageQueries.
+func (in *UsageQueries) DeepCopy() *UsageQueries {
+	if in == nil {
+		return nil
+	}
+	out := new(UsageQueries)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UsageSpec) DeepCopyInto(out *UsageSpec) {
+	*out = *in
+	if in.Headroom != nil {
+		in, out := &in.Headroom, &out.Headroom
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Queries != nil {
+		in, out := &in.Queries, &out.Queries
+		*out = new(UsageQueries)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UsageSpec.
+func (in *UsageSpec) DeepCopy() *UsageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UsageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
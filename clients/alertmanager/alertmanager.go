@@ -0,0 +1,159 @@
+// Package alertmanager manages the configuration of tenants through the
+// configuration API of the Mimir Alertmanager.
+package alertmanager
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// configPath is the path of the configuration API of the Mimir Alertmanager.
+const configPath = "/api/v1/alerts"
+
+// UserConfig is the Alertmanager configuration of a tenant as exchanged with
+// the configuration API.
+type UserConfig struct {
+	// TemplateFiles are the notification templates keyed by file name.
+	TemplateFiles map[string]string `json:"template_files,omitempty"`
+	// AlertmanagerConfig is the Alertmanager configuration file.
+	AlertmanagerConfig string `json:"alertmanager_config"`
+}
+
+// Client manages the configurations of tenants in an Alertmanager.
+type Client struct {
+	configURL  string
+	httpClient *http.Client
+}
+
+// NewClient returns a Client for the Mimir Alertmanager at url.
+func NewClient(url string) *Client {
+	return &Client{
+		configURL:  strings.TrimSuffix(url, "/") + configPath,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// StatusError is returned for responses outside the 2xx range.
+type StatusError struct {
+	Method, Path string
+	Code         int
+	Message      string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s %s: unexpected status %d: %s", e.Method, e.Path, e.Code, e.Message)
+}
+
+// IsInvalid reports whether the Alertmanager rejected a configuration as
+// invalid, e.g. because it does not parse or a limit is exceeded.
+func IsInvalid(err error) bool {
+	var se *StatusError
+	return errors.As(err, &se) && se.Code == http.StatusBadRequest
+}
+
+// Config returns the configuration of the tenant, which is nil if it has none.
+func (c *Client) Config(ctx context.Context, tenantID string) (*UserConfig, error) {
+	data, err := c.do(ctx, http.MethodGet, tenantID, nil)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	config := &UserConfig{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// SetConfig creates or replaces the configuration of the tenant.
+func (c *Client) SetConfig(ctx context.Context, tenantID string, config UserConfig) error {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	_, err = c.do(ctx, http.MethodPost, tenantID, data)
+	return err
+}
+
+// DeleteConfig deletes the configuration of the tenant, if it exists.
+func (c *Client) DeleteConfig(ctx context.Context, tenantID string) error {
+	_, err := c.do(ctx, http.MethodDelete, tenantID, nil)
+	return err
+}
+
+// Sync uploads the configuration of the tenant if it differs from the one in
+// the Alertmanager.
+func (c *Client) Sync(ctx context.Context, tenantID string, config UserConfig) error {
+	current, err := c.Config(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if current != nil && Equal(*current, config) {
+		return nil
+	}
+	return c.SetConfig(ctx, tenantID, config)
+}
+
+// Equal reports whether the configurations are the same, regardless of how
+// the Alertmanager configuration file is formatted.
+func Equal(a, b UserConfig) bool {
+	if len(a.TemplateFiles) != len(b.TemplateFiles) {
+		return false
+	}
+	for k, v := range a.TemplateFiles {
+		if w, ok := b.TemplateFiles[k]; !ok || v != w {
+			return false
+		}
+	}
+	var configA, configB interface{}
+	if err := yaml.Unmarshal([]byte(a.AlertmanagerConfig), &configA); err != nil {
+		return false
+	}
+	if err := yaml.Unmarshal([]byte(b.AlertmanagerConfig), &configB); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(configA, configB)
+}
+
+// do sends the request as the tenant and returns the response body, which is
+// nil if the tenant has no configuration.
+func (c *Client) do(ctx context.Context, method, tenantID string, body []byte) ([]byte, error) {
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.configURL, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Scope-OrgID", tenantID)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/yaml")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, nil
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return nil, &StatusError{Method: method, Path: req.URL.Path, Code: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+	}
+	return data, nil
+}
@@ -0,0 +1,77 @@
+package alertmanager_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/traceshield/trace-shield-controller/clients/alertmanager"
+	"github.com/traceshield/trace-shield-controller/clients/alertmanager/alertmanagertest"
+)
+
+var _ = Describe("Client", func() {
+	var server *alertmanagertest.Server
+	var client *alertmanager.Client
+	ctx := context.Background()
+
+	config := alertmanager.UserConfig{
+		TemplateFiles: map[string]string{
+			"default.tmpl": `{{ define "summary" }}{{ .CommonLabels.alertname }}{{ end }}`,
+		},
+		AlertmanagerConfig: "route:\n  receiver: default\nreceivers:\n- name: default\n",
+	}
+
+	BeforeEach(func() {
+		server = alertmanagertest.NewServer()
+		DeferCleanup(server.Close)
+		client = alertmanager.NewClient(server.URL())
+	})
+
+	It("syncs the configuration of a tenant", func() {
+		current, err := client.Config(ctx, "team-a")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(current).To(BeNil())
+
+		Expect(client.Sync(ctx, "team-a", config)).To(Succeed())
+		synced, ok := server.Config("team-a")
+		Expect(ok).To(BeTrue())
+		Expect(synced).To(Equal(config))
+		_, ok = server.Config("team-b")
+		Expect(ok).To(BeFalse())
+
+		By("deleting the configuration")
+		Expect(client.DeleteConfig(ctx, "team-a")).To(Succeed())
+		_, ok = server.Config("team-a")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("only uploads configurations that changed", func() {
+		Expect(client.Sync(ctx, "team-a", config)).To(Succeed())
+		writes := server.Writes()
+
+		reformatted := config
+		reformatted.AlertmanagerConfig = "receivers: [{name: default}]\nroute: {receiver: default}\n"
+		server.SetConfig("team-a", reformatted)
+		Expect(client.Sync(ctx, "team-a", config)).To(Succeed())
+		Expect(server.Writes()).To(Equal(writes))
+
+		By("correcting drift")
+		edited := config
+		edited.TemplateFiles = nil
+		server.SetConfig("team-a", edited)
+		Expect(client.Sync(ctx, "team-a", config)).To(Succeed())
+		synced, _ := server.Config("team-a")
+		Expect(synced).To(Equal(config))
+	})
+
+	It("reports configurations rejected by the Alertmanager", func() {
+		server.Validate = func(config alertmanager.UserConfig) error {
+			return errors.New("undefined receiver")
+		}
+		err := client.Sync(ctx, "team-a", config)
+		Expect(err).To(MatchError(ContainSubstring("undefined receiver")))
+		Expect(alertmanager.IsInvalid(err)).To(BeTrue())
+	})
+})
@@ -0,0 +1,127 @@
+// Package alertmanagertest provides an in-memory Alertmanager configuration
+// API for tests.
+//
+// The server stores a configuration per tenant as the Mimir Alertmanager
+// does, and rejects configurations for which a configured validation
+// function returns an error with a 400 status.
+package alertmanagertest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/traceshield/trace-shield-controller/clients/alertmanager"
+)
+
+// Server is an in-memory Alertmanager listening on a local port.
+type Server struct {
+	// Validate, if set, is called for every uploaded configuration and
+	// rejects it when it returns an error.
+	Validate func(config alertmanager.UserConfig) error
+
+	mu      sync.Mutex
+	configs map[string]alertmanager.UserConfig
+	writes  int
+
+	srv *httptest.Server
+}
+
+// NewServer starts an in-memory Alertmanager. Call Close to shut it down.
+func NewServer() *Server {
+	s := &Server{configs: map[string]alertmanager.UserConfig{}}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// URL returns the URL clients are configured with.
+func (s *Server) URL() string {
+	return s.srv.URL
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Config returns the configuration of the tenant and whether it has one.
+func (s *Server) Config(tenantID string) (alertmanager.UserConfig, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	config, ok := s.configs[tenantID]
+	return config, ok
+}
+
+// SetConfig stores the configuration as if it was uploaded by someone else.
+func (s *Server) SetConfig(tenantID string, config alertmanager.UserConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.configs[tenantID] = config
+}
+
+// Writes returns the number of configurations uploaded or deleted through the API.
+func (s *Server) Writes() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.writes
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Header.Get("X-Scope-OrgID")
+	if tenantID == "" {
+		http.Error(w, "no org id", http.StatusUnauthorized)
+		return
+	}
+	if r.URL.Path != "/api/v1/alerts" {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		config, ok := s.configs[tenantID]
+		if !ok {
+			http.Error(w, "alertmanager storage object not found", http.StatusNotFound)
+			return
+		}
+		data, err := yaml.Marshal(config)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml")
+		_, _ = w.Write(data)
+	case http.MethodPost:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		config := alertmanager.UserConfig{}
+		if err := yaml.UnmarshalStrict(body, &config); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if s.Validate != nil {
+			if err := s.Validate(config); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		s.writes++
+		s.configs[tenantID] = config
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		s.writes++
+		delete(s.configs, tenantID)
+		w.WriteHeader(http.StatusOK)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
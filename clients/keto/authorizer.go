@@ -0,0 +1,189 @@
+package keto
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	rts "github.com/ory/keto/proto/ory/keto/relation_tuples/v1alpha2"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	ObservabilityTenantNamespace = "ObservabilityTenant"
+	OrganizationNamespace        = "Organization"
+
+	// TenantOrganizationsRelation relates an observability tenant to the organization that owns it.
+	TenantOrganizationsRelation = "organizations"
+	// DefaultOrganization is the organization every observability tenant is registered in.
+	DefaultOrganization = "main" //TODO: decide whether to hardcode this or not
+)
+
+// TenantAuthorizer manages the authorization model for observability tenants.
+type TenantAuthorizer interface {
+	// RegisterTenant makes sure the tenant exists in the authorization model.
+	RegisterTenant(ctx context.Context, name string) error
+	// UnregisterTenant removes the tenant from the authorization model.
+	UnregisterTenant(ctx context.Context, name string) error
+	// SyncAccessGrants makes sure exactly the given subjects hold the relation on the tenant.
+	SyncAccessGrants(ctx context.Context, name, relation string, subjects []Subject) error
+	// SyncQueryFederation makes sure the tenant may read exactly the given tenants.
+	SyncQueryFederation(ctx context.Context, name string, tenants []string) error
+}
+
+// readyzTimeout bounds how long a readiness probe waits for Keto.
+const readyzTimeout = 2 * time.Second
+
+// ReadinessChecker is implemented by TenantAuthorizers that can report
+// whether their backend is reachable. ReadyzCheck matches healthz.Checker.
+type ReadinessChecker interface {
+	ReadyzCheck(req *http.Request) error
+}
+
+// PermissionChecker is implemented by TenantAuthorizers that can check
+// whether a subject holds a permission on an observability tenant.
+type PermissionChecker interface {
+	CheckTenantPermission(ctx context.Context, name, permission string, subject Subject) (bool, error)
+}
+
+// Subject is either a subject ID or a subject set.
+type Subject struct {
+	ID  string
+	Set *SubjectSet
+}
+
+// SubjectSet refers to all subjects holding a relation on an object.
+type SubjectSet struct {
+	Namespace string
+	Object    string
+	Relation  string
+}
+
+// NewSubjectID returns a Subject with a subject ID.
+func NewSubjectID(id string) Subject {
+	return Subject{ID: id}
+}
+
+// NewSubjectSet returns a Subject with a subject set.
+func NewSubjectSet(namespace, object, relation string) Subject {
+	return Subject{Set: &SubjectSet{
+		Namespace: namespace,
+		Object:    object,
+		Relation:  relation,
+	}}
+}
+
+func (s Subject) toProto() *rts.Subject {
+	if s.Set != nil {
+		return rts.NewSubjectSet(s.Set.Namespace, s.Set.Object, s.Set.Relation)
+	}
+	return rts.NewSubjectID(s.ID)
+}
+
+// NoopAuthorizer is a TenantAuthorizer for clusters without Keto.
+type NoopAuthorizer struct{}
+
+var _ TenantAuthorizer = NoopAuthorizer{}
+
+func (NoopAuthorizer) RegisterTenant(ctx context.Context, name string) error {
+	return nil
+}
+
+func (NoopAuthorizer) UnregisterTenant(ctx context.Context, name string) error {
+	return nil
+}
+
+func (NoopAuthorizer) SyncAccessGrants(ctx context.Context, name, relation string, subjects []Subject) error {
+	return nil
+}
+
+func (NoopAuthorizer) SyncQueryFederation(ctx context.Context, name string, tenants []string) error {
+	return nil
+}
+
+var (
+	_ TenantAuthorizer  = &KetoGrpcClient{}
+	_ TenantAuthorizer  = &KetoHttpClient{}
+	_ ReadinessChecker  = &KetoGrpcClient{}
+	_ ReadinessChecker  = &KetoHttpClient{}
+	_ NamespaceVerifier = &KetoGrpcClient{}
+	_ NamespaceVerifier = &KetoHttpClient{}
+	_ PermissionChecker = &KetoGrpcClient{}
+	_ PermissionChecker = &KetoHttpClient{}
+	_ AccessExplainer   = &KetoGrpcClient{}
+	_ AccessExplainer   = &KetoHttpClient{}
+)
+
+// observabilityTenantTuple returns the tuple registering an observability tenant in the default organization.
+func observabilityTenantTuple(name string) *rts.RelationTuple {
+	return &rts.RelationTuple{
+		Namespace: ObservabilityTenantNamespace,
+		Object:    name,
+		Relation:  TenantOrganizationsRelation,
+		Subject:   rts.NewSubjectSet(OrganizationNamespace, DefaultOrganization, ""),
+	}
+}
+
+func tenantPermissionTuple(name, permission string, subject Subject) *rts.RelationTuple {
+	return &rts.RelationTuple{
+		Namespace: ObservabilityTenantNamespace,
+		Object:    name,
+		Relation:  permission,
+		Subject:   subject.toProto(),
+	}
+}
+
+func accessGrantTuples(name, relation string, subjects []Subject) []*rts.RelationTuple {
+	tuples := make([]*rts.RelationTuple, len(subjects))
+	for i, s := range subjects {
+		tuples[i] = &rts.RelationTuple{
+			Namespace: ObservabilityTenantNamespace,
+			Object:    name,
+			Relation:  relation,
+			Subject:   s.toProto(),
+		}
+	}
+	return tuples
+}
+
+// federatedReaderSubject is the subject a tenant is related by to the tenants it may read.
+func federatedReaderSubject(name string) Subject {
+	return NewSubjectSet(ObservabilityTenantNamespace, name, "")
+}
+
+func queryFederationTuples(name string, tenants []string) []*rts.RelationTuple {
+	tuples := make([]*rts.RelationTuple, len(tenants))
+	for i, t := range tenants {
+		tuples[i] = &rts.RelationTuple{
+			Namespace: ObservabilityTenantNamespace,
+			Object:    t,
+			Relation:  TenantFederatedReadersRelation,
+			Subject:   federatedReaderSubject(name).toProto(),
+		}
+	}
+	return tuples
+}
+
+// diffTuples returns the tuples to insert and delete to get from current to desired.
+func diffTuples(current, desired []*rts.RelationTuple) (ins, del []*rts.RelationTuple) {
+	for _, d := range desired {
+		if !containsTuple(current, d) && !containsTuple(ins, d) {
+			ins = append(ins, d)
+		}
+	}
+	for _, c := range current {
+		if !containsTuple(desired, c) {
+			del = append(del, c)
+		}
+	}
+	return ins, del
+}
+
+func containsTuple(tuples []*rts.RelationTuple, t *rts.RelationTuple) bool {
+	for _, candidate := range tuples {
+		if proto.Equal(candidate, t) {
+			return true
+		}
+	}
+	return false
+}
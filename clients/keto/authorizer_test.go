@@ -0,0 +1,230 @@
+package keto_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	rts "github.com/ory/keto/proto/ory/keto/relation_tuples/v1alpha2"
+
+	"github.com/traceshield/trace-shield-controller/clients/keto"
+	"github.com/traceshield/trace-shield-controller/clients/keto/ketotest"
+)
+
+var _ = Describe("TenantAuthorizer", func() {
+	var (
+		ctx    context.Context
+		server *ketotest.Server
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		var err error
+		server, err = ketotest.NewServer()
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(server.Stop)
+	})
+
+	authorizerSpecs := func(newAuthorizer func() keto.TenantAuthorizer) {
+		var authorizer keto.TenantAuthorizer
+
+		BeforeEach(func() {
+			authorizer = newAuthorizer()
+		})
+
+		It("registers and unregisters tenants", func() {
+			Expect(authorizer.RegisterTenant(ctx, "team-a")).To(Succeed())
+			Expect(authorizer.RegisterTenant(ctx, "team-a")).To(Succeed())
+			Expect(server.Tuples()).To(HaveLen(1))
+
+			Expect(authorizer.UnregisterTenant(ctx, "team-a")).To(Succeed())
+			Expect(server.Tuples()).To(BeEmpty())
+		})
+
+		It("does not carry the grants of a deleted tenant over to a tenant of the same name", func() {
+			for _, name := range []string{"team-a", "team-b", "team-c"} {
+				Expect(authorizer.RegisterTenant(ctx, name)).To(Succeed())
+			}
+			Expect(authorizer.SyncAccessGrants(ctx, "team-a", keto.TenantViewersRelation, []keto.Subject{keto.NewSubjectID("alice")})).To(Succeed())
+			Expect(authorizer.SyncQueryFederation(ctx, "team-a", []string{"team-b"})).To(Succeed())
+			Expect(authorizer.SyncAccessGrants(ctx, "team-c", keto.TenantParentRelation, []keto.Subject{keto.NewSubjectSet(keto.ObservabilityTenantNamespace, "team-a", "")})).To(Succeed())
+
+			Expect(authorizer.UnregisterTenant(ctx, "team-a")).To(Succeed())
+			Expect(authorizer.RegisterTenant(ctx, "team-a")).To(Succeed())
+
+			Expect(server.Tuples()).To(ConsistOf(
+				HaveField("Object", "team-a"),
+				HaveField("Object", "team-b"),
+				HaveField("Object", "team-c"),
+			))
+			Expect(server.Tuples()).To(HaveEach(HaveField("Relation", keto.TenantOrganizationsRelation)))
+		})
+
+		It("syncs access grants for a single relation", func() {
+			Expect(authorizer.RegisterTenant(ctx, "team-a")).To(Succeed())
+			server.Insert(&rts.RelationTuple{
+				Namespace: keto.ObservabilityTenantNamespace,
+				Object:    "team-a",
+				Relation:  "viewers",
+				Subject:   rts.NewSubjectID("mallory"),
+			})
+
+			Expect(authorizer.SyncAccessGrants(ctx, "team-a", "viewers", []keto.Subject{
+				keto.NewSubjectID("alice"),
+				keto.NewSubjectSet("Group", "sre", "members"),
+			})).To(Succeed())
+
+			tuples := server.Tuples()
+			Expect(tuples).To(HaveLen(3))
+			Expect(tuples).To(ContainElement(HaveField("Relation", keto.TenantOrganizationsRelation)))
+			Expect(tuples).NotTo(ContainElement(HaveField("Subject.Ref", Equal(&rts.Subject_Id{Id: "mallory"}))))
+
+			Expect(authorizer.SyncAccessGrants(ctx, "team-a", "viewers", nil)).To(Succeed())
+			Expect(server.Tuples()).To(HaveLen(1))
+		})
+
+		It("checks tenant permissions", func() {
+			checker, ok := authorizer.(keto.PermissionChecker)
+			Expect(ok).To(BeTrue())
+
+			alice := keto.NewSubjectSet(keto.UserNamespace, "alice", "")
+			Expect(checker.CheckTenantPermission(ctx, "team-a", keto.TenantViewPermission, alice)).To(BeFalse())
+
+			server.Insert(&rts.RelationTuple{
+				Namespace: keto.ObservabilityTenantNamespace,
+				Object:    "team-a",
+				Relation:  keto.TenantViewPermission,
+				Subject:   rts.NewSubjectSet(keto.UserNamespace, "alice", ""),
+			})
+			Expect(checker.CheckTenantPermission(ctx, "team-a", keto.TenantViewPermission, alice)).To(BeTrue())
+		})
+
+		It("explains tenant access", func() {
+			explainer, ok := authorizer.(keto.AccessExplainer)
+			Expect(ok).To(BeTrue())
+
+			Expect(authorizer.RegisterTenant(ctx, "team-a")).To(Succeed())
+			Expect(authorizer.SyncAccessGrants(ctx, "team-a", keto.TenantViewersRelation, []keto.Subject{
+				keto.NewSubjectSet(keto.GroupNamespace, "sre", keto.GroupMembersRelation),
+			})).To(Succeed())
+			server.Insert(
+				&rts.RelationTuple{Namespace: keto.GroupNamespace, Object: "sre", Relation: keto.GroupMembersRelation, Subject: rts.NewSubjectSet(keto.UserNamespace, "bob", "")},
+				&rts.RelationTuple{Namespace: keto.OrganizationNamespace, Object: keto.DefaultOrganization, Relation: keto.OrganizationAdminsRelation, Subject: rts.NewSubjectSet(keto.UserNamespace, "carol", "")},
+			)
+
+			access, err := keto.ExplainTenantAccess(ctx, explainer, "team-a", 5)
+			Expect(err).NotTo(HaveOccurred())
+
+			bob := keto.ParseSubject("User:bob")
+			Expect(access.GrantsFor(bob)).To(ConsistOf(
+				HaveField("String()", "view: ObservabilityTenant:team-a#viewers <- Group:sre#members <- User:bob"),
+			))
+			Expect(access.GrantsFor(keto.ParseSubject("User:carol"))).To(ConsistOf(
+				HaveField("String()", "edit: ObservabilityTenant:team-a#organizations <- Organization:main <- Organization:main#admins <- User:carol"),
+			))
+			Expect(access.GrantsFor(keto.ParseSubject("User:dave"))).To(BeEmpty())
+
+			out := &strings.Builder{}
+			Expect(access.Render(out, &bob)).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("User:bob *"))
+		})
+
+		It("explains access through the parent and federated tenants", func() {
+			explainer, ok := authorizer.(keto.AccessExplainer)
+			Expect(ok).To(BeTrue())
+
+			for _, name := range []string{"team-a", "team-b", "team-c"} {
+				Expect(authorizer.RegisterTenant(ctx, name)).To(Succeed())
+			}
+			Expect(authorizer.SyncAccessGrants(ctx, "team-a", keto.TenantParentRelation, []keto.Subject{
+				keto.NewSubjectSet(keto.ObservabilityTenantNamespace, "team-b", ""),
+			})).To(Succeed())
+			Expect(authorizer.SyncAccessGrants(ctx, "team-b", keto.TenantParentRelation, []keto.Subject{
+				keto.NewSubjectSet(keto.ObservabilityTenantNamespace, "team-a", ""),
+			})).To(Succeed())
+			Expect(authorizer.SyncQueryFederation(ctx, "team-c", []string{"team-a"})).To(Succeed())
+			Expect(authorizer.SyncAccessGrants(ctx, "team-b", keto.TenantAdminsRelation, []keto.Subject{keto.ParseSubject("User:alice")})).To(Succeed())
+			Expect(authorizer.SyncAccessGrants(ctx, "team-c", keto.TenantEditorsRelation, []keto.Subject{keto.ParseSubject("User:bob")})).To(Succeed())
+
+			access, err := keto.ExplainTenantAccess(ctx, explainer, "team-a", 5)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(access.GrantsFor(keto.ParseSubject("User:alice"))).To(ConsistOf(
+				HaveField("String()", "edit: ObservabilityTenant:team-a#parent <- ObservabilityTenant:team-b <- ObservabilityTenant:team-b#admins <- User:alice"),
+			))
+			Expect(access.GrantsFor(keto.ParseSubject("User:bob"))).To(ConsistOf(
+				HaveField("String()", "view: ObservabilityTenant:team-a#federatedReaders <- ObservabilityTenant:team-c <- ObservabilityTenant:team-c#editors <- User:bob"),
+			))
+		})
+
+		It("verifies the namespace model", func() {
+			verifier, ok := authorizer.(keto.NamespaceVerifier)
+			Expect(ok).To(BeTrue())
+			Expect(verifier.VerifyNamespaces(ctx)).To(Succeed())
+
+			server.SetNamespaces([]keto.Namespace{
+				{Name: keto.UserNamespace},
+				{Name: keto.OrganizationNamespace, Relations: []string{keto.OrganizationAdminsRelation, keto.OrganizationMembersRelation}},
+				{Name: keto.ObservabilityTenantNamespace, Relations: []string{keto.TenantOrganizationsRelation, keto.TenantAdminsRelation}},
+			})
+
+			err := verifier.VerifyNamespaces(ctx)
+			var mismatch *keto.ModelMismatchError
+			Expect(errors.As(err, &mismatch)).To(BeTrue())
+			Expect(mismatch.MissingNamespaces).To(ConsistOf(keto.GroupNamespace))
+			Expect(mismatch.MissingRelations).To(ConsistOf("ObservabilityTenant#editors", "ObservabilityTenant#viewers", "ObservabilityTenant#federatedReaders", "ObservabilityTenant#parent"))
+			Expect(mismatch.SyntaxErrors).To(BeEmpty())
+		})
+
+		It("syncs the query federation of a tenant", func() {
+			Expect(authorizer.SyncQueryFederation(ctx, "team-a", []string{"team-b", "team-c"})).To(Succeed())
+			Expect(authorizer.SyncQueryFederation(ctx, "team-d", []string{"team-b"})).To(Succeed())
+			Expect(server.Tuples()).To(HaveLen(3))
+
+			Expect(authorizer.SyncQueryFederation(ctx, "team-a", []string{"team-c"})).To(Succeed())
+			tuples := server.Tuples()
+			Expect(tuples).To(HaveLen(2))
+			Expect(tuples).To(ContainElement(And(
+				HaveField("Object", "team-b"),
+				HaveField("Relation", keto.TenantFederatedReadersRelation),
+				HaveField("Subject", Equal(rts.NewSubjectSet(keto.ObservabilityTenantNamespace, "team-d", ""))),
+			)))
+
+			Expect(authorizer.SyncQueryFederation(ctx, "team-a", nil)).To(Succeed())
+			Expect(server.Tuples()).To(HaveLen(1))
+		})
+
+		It("reports readiness", func() {
+			checker, ok := authorizer.(keto.ReadinessChecker)
+			Expect(ok).To(BeTrue())
+
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			Expect(checker.ReadyzCheck(req)).To(Succeed())
+
+			server.SetServing(false)
+			Expect(checker.ReadyzCheck(req)).NotTo(Succeed())
+		})
+	}
+
+	Context("over gRPC", func() {
+		authorizerSpecs(func() keto.TenantAuthorizer {
+			client, err := keto.NewKetoGrpcClient(ctx, server.ConnectionDetails())
+			Expect(err).NotTo(HaveOccurred())
+			return client
+		})
+	})
+
+	Context("over HTTP", func() {
+		authorizerSpecs(func() keto.TenantAuthorizer {
+			httpServer := httptest.NewServer(server.HTTPHandler())
+			DeferCleanup(httpServer.Close)
+			return keto.NewKetoHttpClient(httpServer.URL, httpServer.URL)
+		})
+	})
+})
@@ -0,0 +1,232 @@
+package keto
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	rts "github.com/ory/keto/proto/ory/keto/relation_tuples/v1alpha2"
+)
+
+// AccessExplainer is implemented by TenantAuthorizers that can expand the
+// subjects holding a relation on an object.
+type AccessExplainer interface {
+	ExpandSubjectSet(ctx context.Context, set SubjectSet, depth int) (*AccessTree, error)
+}
+
+// AccessTree is a subject and the subjects it expands to.
+type AccessTree struct {
+	Subject  Subject
+	Children []*AccessTree
+}
+
+// relationPermissions are the permissions each relation grants, per namespace.
+var relationPermissions = map[string]map[string]string{
+	ObservabilityTenantNamespace: {
+		TenantAdminsRelation:           TenantEditPermission,
+		TenantEditorsRelation:          TenantEditPermission,
+		TenantViewersRelation:          TenantViewPermission,
+		TenantFederatedReadersRelation: TenantViewPermission,
+	},
+	OrganizationNamespace: {
+		OrganizationAdminsRelation:  TenantEditPermission,
+		OrganizationMembersRelation: TenantViewPermission,
+	},
+}
+
+// directRelations are the tenant relations granting access directly, which
+// a tenant passes on to its children and to the tenants it reads.
+var directRelations = []string{TenantAdminsRelation, TenantEditorsRelation, TenantViewersRelation, TenantOrganizationsRelation}
+
+// TenantAccess is the expansion of every relation granting access to a tenant.
+type TenantAccess struct {
+	Tenant string
+	// Relations holds one tree per tenant relation. Organizations are expanded
+	// into the organization relations that grant access through them.
+	Relations []*AccessTree
+}
+
+// Grant is a chain of subject sets through which a subject holds a permission.
+type Grant struct {
+	Permission string
+	// Path starts at the tenant relation and ends at the subject.
+	Path []Subject
+}
+
+func (g Grant) String() string {
+	parts := make([]string, len(g.Path))
+	for i, s := range g.Path {
+		parts[i] = s.String()
+	}
+	return g.Permission + ": " + strings.Join(parts, " <- ")
+}
+
+// ExplainTenantAccess expands the relations of a tenant, following the
+// organizations it belongs to, its parent and the tenants reading it, up to
+// depth levels deep. The parent and the reading tenants are expanded into
+// their direct relations only, as they pass on nothing else.
+func ExplainTenantAccess(ctx context.Context, e AccessExplainer, name string, depth int) (*TenantAccess, error) {
+	relations := append(directRelations[:len(directRelations):len(directRelations)], TenantParentRelation, TenantFederatedReadersRelation)
+	trees, err := expandTenant(ctx, e, name, relations, depth, map[string]bool{name: true})
+	if err != nil {
+		return nil, err
+	}
+	return &TenantAccess{Tenant: name, Relations: trees}, nil
+}
+
+// expandTenant expands the given relations of a tenant. Related tenants are
+// expanded into their direct relations unless they are already being
+// expanded, which stops cycles of parents and federated tenants.
+func expandTenant(ctx context.Context, e AccessExplainer, name string, relations []string, depth int, seen map[string]bool) ([]*AccessTree, error) {
+	var trees []*AccessTree
+	for _, rel := range relations {
+		tree, err := e.ExpandSubjectSet(ctx, SubjectSet{Namespace: ObservabilityTenantNamespace, Object: name, Relation: rel}, depth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand %s#%s: %w", name, rel, err)
+		}
+		switch rel {
+		case TenantOrganizationsRelation:
+			for _, org := range tree.Children {
+				set := org.Subject.Set
+				if set == nil || set.Namespace != OrganizationNamespace {
+					continue
+				}
+				org.Children = nil
+				for _, orgRel := range []string{OrganizationAdminsRelation, OrganizationMembersRelation} {
+					orgTree, err := e.ExpandSubjectSet(ctx, SubjectSet{Namespace: OrganizationNamespace, Object: set.Object, Relation: orgRel}, depth)
+					if err != nil {
+						return nil, fmt.Errorf("failed to expand organization %s#%s: %w", set.Object, orgRel, err)
+					}
+					org.Children = append(org.Children, orgTree)
+				}
+			}
+		case TenantParentRelation, TenantFederatedReadersRelation:
+			for _, tenant := range tree.Children {
+				set := tenant.Subject.Set
+				if set == nil || set.Namespace != ObservabilityTenantNamespace || seen[set.Object] {
+					continue
+				}
+				seen[set.Object] = true
+				tenant.Children, err = expandTenant(ctx, e, set.Object, directRelations, depth, seen)
+				delete(seen, set.Object)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		trees = append(trees, tree)
+	}
+	return trees, nil
+}
+
+// GrantsFor returns every path through which subject holds a permission on the tenant.
+func (a *TenantAccess) GrantsFor(subject Subject) []Grant {
+	var grants []Grant
+	var walk func(node *AccessTree, path []Subject, permission string)
+	walk = func(node *AccessTree, path []Subject, permission string) {
+		path = append(path[:len(path):len(path)], node.Subject)
+		if set := node.Subject.Set; set != nil {
+			// A relation never widens the permission granted above it, e.g.
+			// the editors of a reading tenant only view this tenant.
+			if p, ok := relationPermissions[set.Namespace][set.Relation]; ok && permission != TenantViewPermission {
+				permission = p
+			}
+		}
+		if node.Subject.equal(subject) && permission != "" {
+			grants = append(grants, Grant{Permission: permission, Path: path})
+		}
+		for _, child := range node.Children {
+			walk(child, path, permission)
+		}
+	}
+	for _, tree := range a.Relations {
+		walk(tree, nil, "")
+	}
+	return grants
+}
+
+// Render writes the access trees, marking the nodes equal to subject if it is set.
+func (a *TenantAccess) Render(w io.Writer, subject *Subject) error {
+	if _, err := fmt.Fprintf(w, "%s:%s\n", ObservabilityTenantNamespace, a.Tenant); err != nil {
+		return err
+	}
+	for i, tree := range a.Relations {
+		if err := tree.render(w, "", i == len(a.Relations)-1, subject); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *AccessTree) render(w io.Writer, prefix string, last bool, subject *Subject) error {
+	branch, indent := "├── ", "│   "
+	if last {
+		branch, indent = "└── ", "    "
+	}
+	label := t.Subject.String()
+	if set := t.Subject.Set; set != nil && set.Namespace == ObservabilityTenantNamespace && set.Relation != "" {
+		label = set.Relation
+	}
+	if subject != nil && t.Subject.equal(*subject) {
+		label += " *"
+	}
+	if _, err := fmt.Fprintf(w, "%s%s%s\n", prefix, branch, label); err != nil {
+		return err
+	}
+	for i, child := range t.Children {
+		if err := child.render(w, prefix+indent, i == len(t.Children)-1, subject); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseSubject parses a subject in Keto notation: namespace:object#relation
+// for subject sets, namespace:object for subject sets with an empty relation,
+// and anything else as a subject ID.
+func ParseSubject(s string) Subject {
+	namespace, rest, ok := strings.Cut(s, ":")
+	if !ok {
+		return NewSubjectID(s)
+	}
+	object, relation, _ := strings.Cut(rest, "#")
+	return NewSubjectSet(namespace, object, relation)
+}
+
+// String returns the subject in the notation accepted by ParseSubject.
+func (s Subject) String() string {
+	if s.Set == nil {
+		return s.ID
+	}
+	if s.Set.Relation == "" {
+		return s.Set.Namespace + ":" + s.Set.Object
+	}
+	return s.Set.Namespace + ":" + s.Set.Object + "#" + s.Set.Relation
+}
+
+func (s Subject) equal(o Subject) bool {
+	if s.Set == nil || o.Set == nil {
+		return s.Set == nil && o.Set == nil && s.ID == o.ID
+	}
+	return *s.Set == *o.Set
+}
+
+func subjectFromProto(s *rts.Subject) Subject {
+	if set := s.GetSet(); set != nil {
+		return NewSubjectSet(set.Namespace, set.Object, set.Relation)
+	}
+	return NewSubjectID(s.GetId())
+}
+
+func accessTreeFromProto(t *rts.SubjectTree) *AccessTree {
+	subject := t.GetTuple().GetSubject()
+	if subject == nil {
+		subject = t.GetSubject() //nolint:staticcheck // older Keto versions only set the deprecated field
+	}
+	tree := &AccessTree{Subject: subjectFromProto(subject)}
+	for _, child := range t.GetChildren() {
+		tree.Children = append(tree.Children, accessTreeFromProto(child))
+	}
+	return tree
+}
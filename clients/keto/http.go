@@ -0,0 +1,476 @@
+package keto
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	rts "github.com/ory/keto/proto/ory/keto/relation_tuples/v1alpha2"
+)
+
+const (
+	KetoReadURLDefault  = "http://127.0.0.1:4466"
+	KetoWriteURLDefault = "http://127.0.0.1:4467"
+	KetoEnvReadURL      = "KETO_READ_URL"
+	KetoEnvWriteURL     = "KETO_WRITE_URL"
+)
+
+// KetoHttpClient talks to the Keto REST API.
+type KetoHttpClient struct {
+	ReadURL, WriteURL string
+	token             string
+	tokenFile         *fileToken
+	httpClient        *http.Client
+}
+
+func NewKetoHttpClient(readURL, writeURL string) *KetoHttpClient {
+	return &KetoHttpClient{
+		ReadURL:    readURL,
+		WriteURL:   writeURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func NewKetoHttpClientFromEnv() (*KetoHttpClient, error) {
+	c := NewKetoHttpClient(
+		getKetoURL(KetoEnvReadURL, KetoReadURLDefault),
+		getKetoURL(KetoEnvWriteURL, KetoWriteURLDefault),
+	)
+	if c.token = os.Getenv(KetoEnvAuthToken); c.token != "" {
+		if err := c.requireTLS(); err != nil {
+			return nil, err
+		}
+	}
+	if path := os.Getenv(KetoEnvAuthTokenFile); path != "" {
+		if err := c.SetTokenFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.SetTLSOptions(TLSOptionsFromEnv()); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// SetTLSOptions sets the TLS configuration used for https Keto URLs.
+func (c *KetoHttpClient) SetTLSOptions(opts TLSOptions) error {
+	tlsConfig, err := opts.Config()
+	if err != nil {
+		return err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	c.httpClient.Transport = transport
+	return nil
+}
+
+// SetTokenFile reads the bearer token from the given file instead of the
+// environment. The file is reread whenever it changes.
+func (c *KetoHttpClient) SetTokenFile(path string) error {
+	if err := c.requireTLS(); err != nil {
+		return err
+	}
+	c.tokenFile = &fileToken{path: path}
+	return nil
+}
+
+// requireTLS returns an error unless both Keto URLs use https, so that the
+// bearer token is never sent in the clear.
+func (c *KetoHttpClient) requireTLS() error {
+	for _, u := range []string{c.ReadURL, c.WriteURL} {
+		parsed, err := url.Parse(u)
+		if err != nil {
+			return fmt.Errorf("invalid Keto URL %q: %w", u, err)
+		}
+		if parsed.Scheme != "https" {
+			return fmt.Errorf("a bearer token is configured but the Keto URL %s does not use https", u)
+		}
+	}
+	return nil
+}
+
+func getKetoURL(envURL, urlDefault string) string {
+	if u, isSet := os.LookupEnv(envURL); isSet {
+		return u
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "env var %s is not set, falling back to %s\n", envURL, urlDefault)
+	return urlDefault
+}
+
+type httpSubjectSet struct {
+	Namespace string `json:"namespace"`
+	Object    string `json:"object"`
+	Relation  string `json:"relation"`
+}
+
+type httpRelationTuple struct {
+	Namespace  string          `json:"namespace"`
+	Object     string          `json:"object"`
+	Relation   string          `json:"relation"`
+	SubjectID  *string         `json:"subject_id,omitempty"`
+	SubjectSet *httpSubjectSet `json:"subject_set,omitempty"`
+}
+
+type httpRelationTuplePatch struct {
+	Action        string            `json:"action"`
+	RelationTuple httpRelationTuple `json:"relation_tuple"`
+}
+
+type httpRelationTuplesResponse struct {
+	RelationTuples []httpRelationTuple `json:"relation_tuples"`
+	NextPageToken  string              `json:"next_page_token"`
+}
+
+func toHttpRelationTuple(t *rts.RelationTuple) httpRelationTuple {
+	out := httpRelationTuple{
+		Namespace: t.Namespace,
+		Object:    t.Object,
+		Relation:  t.Relation,
+	}
+	if set := t.Subject.GetSet(); set != nil {
+		out.SubjectSet = &httpSubjectSet{
+			Namespace: set.Namespace,
+			Object:    set.Object,
+			Relation:  set.Relation,
+		}
+	} else {
+		id := t.Subject.GetId()
+		out.SubjectID = &id
+	}
+	return out
+}
+
+func (t httpRelationTuple) toProto() *rts.RelationTuple {
+	out := &rts.RelationTuple{
+		Namespace: t.Namespace,
+		Object:    t.Object,
+		Relation:  t.Relation,
+	}
+	if t.SubjectSet != nil {
+		out.Subject = rts.NewSubjectSet(t.SubjectSet.Namespace, t.SubjectSet.Object, t.SubjectSet.Relation)
+	} else if t.SubjectID != nil {
+		out.Subject = rts.NewSubjectID(*t.SubjectID)
+	}
+	return out
+}
+
+func (t httpRelationTuple) query() url.Values {
+	q := url.Values{}
+	q.Set("namespace", t.Namespace)
+	q.Set("object", t.Object)
+	q.Set("relation", t.Relation)
+	if t.SubjectID != nil {
+		q.Set("subject_id", *t.SubjectID)
+	}
+	if t.SubjectSet != nil {
+		q.Set("subject_set.namespace", t.SubjectSet.Namespace)
+		q.Set("subject_set.object", t.SubjectSet.Object)
+		q.Set("subject_set.relation", t.SubjectSet.Relation)
+	}
+	return q
+}
+
+// statusError is returned for responses outside the 2xx range.
+type statusError struct {
+	method, path string
+	code         int
+	msg          string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%s %s: unexpected status %d: %s", e.method, e.path, e.code, e.msg)
+}
+
+func statusCode(err error) int {
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.code
+	}
+	return 0
+}
+
+func (c *KetoHttpClient) do(ctx context.Context, method, u string, body interface{}, out interface{}) error {
+	var reqBody io.Reader
+	contentType := "application/json"
+	switch b := body.(type) {
+	case nil:
+	case []byte:
+		reqBody = bytes.NewReader(b)
+		contentType = "text/plain"
+	default:
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	switch {
+	case c.tokenFile != nil:
+		token, err := c.tokenFile.Token()
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &statusError{method: method, path: req.URL.Path, code: resp.StatusCode, msg: string(bytes.TrimSpace(msg))}
+	}
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
+
+// QueryAllTuples lists all relation tuples matching the query, following page tokens.
+func (c *KetoHttpClient) QueryAllTuples(ctx context.Context, q url.Values, pagesize int) ([]*rts.RelationTuple, error) {
+	tuples := make([]*rts.RelationTuple, 0)
+	q.Set("page_size", strconv.Itoa(pagesize))
+	for {
+		resp := &httpRelationTuplesResponse{}
+		if err := c.do(ctx, http.MethodGet, c.ReadURL+"/relation-tuples?"+q.Encode(), nil, resp); err != nil {
+			return nil, err
+		}
+		for _, t := range resp.RelationTuples {
+			tuples = append(tuples, t.toProto())
+		}
+		if resp.NextPageToken == "" {
+			return tuples, nil
+		}
+		q.Set("page_token", resp.NextPageToken)
+	}
+}
+
+// TransactTuples inserts and deletes relation tuples in a single transaction.
+func (c *KetoHttpClient) TransactTuples(ctx context.Context, ins []*rts.RelationTuple, del []*rts.RelationTuple) error {
+	patches := make([]httpRelationTuplePatch, 0, len(ins)+len(del))
+	for _, t := range ins {
+		patches = append(patches, httpRelationTuplePatch{Action: "insert", RelationTuple: toHttpRelationTuple(t)})
+	}
+	for _, t := range del {
+		patches = append(patches, httpRelationTuplePatch{Action: "delete", RelationTuple: toHttpRelationTuple(t)})
+	}
+	return c.do(ctx, http.MethodPatch, c.WriteURL+"/admin/relation-tuples", patches, nil)
+}
+
+// RegisterTenant implements TenantAuthorizer.
+func (c *KetoHttpClient) RegisterTenant(ctx context.Context, name string) error {
+	tenantTuple := observabilityTenantTuple(name)
+	existing, err := c.QueryAllTuples(ctx, toHttpRelationTuple(tenantTuple).query(), 100)
+	if err != nil {
+		return fmt.Errorf("failed to check if observability tenant exists: %w", err)
+	}
+	if len(existing) > 0 {
+		return nil
+	}
+	return c.TransactTuples(ctx, []*rts.RelationTuple{tenantTuple}, nil)
+}
+
+// UnregisterTenant implements TenantAuthorizer.
+func (c *KetoHttpClient) UnregisterTenant(ctx context.Context, name string) error {
+	if name == "" {
+		return fmt.Errorf("observability tenant name cannot be empty")
+	}
+	q := url.Values{}
+	q.Set("namespace", ObservabilityTenantNamespace)
+	q.Set("object", name)
+	tuples, err := c.QueryAllTuples(ctx, q, 100)
+	if err != nil {
+		return fmt.Errorf("failed to query tuples: %w", err)
+	}
+	// The tenant is also the subject of the parent and federatedReaders
+	// tuples of other tenants, which would otherwise grant access to a
+	// tenant reusing its name.
+	q = url.Values{}
+	q.Set("namespace", ObservabilityTenantNamespace)
+	q.Set("subject_set.namespace", ObservabilityTenantNamespace)
+	q.Set("subject_set.object", name)
+	q.Set("subject_set.relation", "")
+	related, err := c.QueryAllTuples(ctx, q, 100)
+	if err != nil {
+		return fmt.Errorf("failed to query tuples: %w", err)
+	}
+	tuples = append(tuples, related...)
+	if len(tuples) == 0 {
+		return nil
+	}
+	return c.TransactTuples(ctx, nil, tuples)
+}
+
+// SyncAccessGrants implements TenantAuthorizer.
+func (c *KetoHttpClient) SyncAccessGrants(ctx context.Context, name, relation string, subjects []Subject) error {
+	q := url.Values{}
+	q.Set("namespace", ObservabilityTenantNamespace)
+	q.Set("object", name)
+	q.Set("relation", relation)
+	current, err := c.QueryAllTuples(ctx, q, 100)
+	if err != nil {
+		return fmt.Errorf("failed to query tuples: %w", err)
+	}
+
+	ins, del := diffTuples(current, accessGrantTuples(name, relation, subjects))
+	if len(ins) == 0 && len(del) == 0 {
+		return nil
+	}
+	return c.TransactTuples(ctx, ins, del)
+}
+
+// SyncQueryFederation implements TenantAuthorizer.
+func (c *KetoHttpClient) SyncQueryFederation(ctx context.Context, name string, tenants []string) error {
+	q := url.Values{}
+	q.Set("namespace", ObservabilityTenantNamespace)
+	q.Set("relation", TenantFederatedReadersRelation)
+	q.Set("subject_set.namespace", ObservabilityTenantNamespace)
+	q.Set("subject_set.object", name)
+	q.Set("subject_set.relation", "")
+	current, err := c.QueryAllTuples(ctx, q, 100)
+	if err != nil {
+		return fmt.Errorf("failed to query tuples: %w", err)
+	}
+
+	ins, del := diffTuples(current, queryFederationTuples(name, tenants))
+	if len(ins) == 0 && len(del) == 0 {
+		return nil
+	}
+	return c.TransactTuples(ctx, ins, del)
+}
+
+// CheckTenantPermission implements PermissionChecker.
+func (c *KetoHttpClient) CheckTenantPermission(ctx context.Context, name, permission string, subject Subject) (bool, error) {
+	q := toHttpRelationTuple(tenantPermissionTuple(name, permission, subject)).query()
+	resp := &struct {
+		Allowed bool `json:"allowed"`
+	}{}
+	if err := c.do(ctx, http.MethodGet, c.ReadURL+"/relation-tuples/check/openapi?"+q.Encode(), nil, resp); err != nil {
+		return false, err
+	}
+	return resp.Allowed, nil
+}
+
+type httpSubjectTree struct {
+	Type     string             `json:"type"`
+	Tuple    *httpRelationTuple `json:"tuple"`
+	Children []httpSubjectTree  `json:"children"`
+}
+
+func (t httpSubjectTree) accessTree() *AccessTree {
+	tree := &AccessTree{}
+	if t.Tuple != nil {
+		tree.Subject = subjectFromProto(t.Tuple.toProto().Subject)
+	}
+	for _, child := range t.Children {
+		tree.Children = append(tree.Children, child.accessTree())
+	}
+	return tree
+}
+
+// ExpandSubjectSet implements AccessExplainer.
+func (c *KetoHttpClient) ExpandSubjectSet(ctx context.Context, set SubjectSet, depth int) (*AccessTree, error) {
+	q := url.Values{}
+	q.Set("namespace", set.Namespace)
+	q.Set("object", set.Object)
+	q.Set("relation", set.Relation)
+	q.Set("max-depth", strconv.Itoa(depth))
+	resp := &httpSubjectTree{}
+	if err := c.do(ctx, http.MethodGet, c.ReadURL+"/relation-tuples/expand?"+q.Encode(), nil, resp); err != nil {
+		return nil, err
+	}
+	return resp.accessTree(), nil
+}
+
+// ReadyzCheck implements ReadinessChecker using the Keto readiness endpoints
+// of both the read and the write API.
+func (c *KetoHttpClient) ReadyzCheck(req *http.Request) error {
+	ctx, cancel := context.WithTimeout(req.Context(), readyzTimeout)
+	defer cancel()
+
+	for _, api := range []struct{ name, url string }{{"read", c.ReadURL}, {"write", c.WriteURL}} {
+		if err := c.do(ctx, http.MethodGet, api.url+"/health/ready", nil, nil); err != nil {
+			return fmt.Errorf("keto %s API is not ready: %w", api.name, err)
+		}
+	}
+	return nil
+}
+
+// VerifyNamespaces implements NamespaceVerifier.
+func (c *KetoHttpClient) VerifyNamespaces(ctx context.Context) error {
+	return verifyNamespaces(ctx, c)
+}
+
+func (c *KetoHttpClient) listNamespaces(ctx context.Context) ([]string, error) {
+	resp := &struct {
+		Namespaces []struct {
+			Name string `json:"name"`
+		} `json:"namespaces"`
+	}{}
+	if err := c.do(ctx, http.MethodGet, c.ReadURL+"/namespaces", nil, resp); err != nil {
+		return nil, err
+	}
+	names := make([]string, len(resp.Namespaces))
+	for i, ns := range resp.Namespaces {
+		names[i] = ns.Name
+	}
+	return names, nil
+}
+
+func (c *KetoHttpClient) relationDeclared(ctx context.Context, namespace, relation string) (bool, error) {
+	q := url.Values{}
+	q.Set("namespace", namespace)
+	q.Set("relation", relation)
+	q.Set("page_size", "1")
+	err := c.do(ctx, http.MethodGet, c.ReadURL+"/relation-tuples?"+q.Encode(), nil, nil)
+	switch statusCode(err) {
+	case http.StatusBadRequest, http.StatusNotFound:
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (c *KetoHttpClient) checkOPL(ctx context.Context, content []byte) ([]string, error) {
+	resp := &struct {
+		Errors []struct {
+			Message string `json:"message"`
+			Start   struct {
+				Line   int `json:"line"`
+				Column int `json:"column"`
+			} `json:"start"`
+		} `json:"errors"`
+	}{}
+	err := c.do(ctx, http.MethodPost, c.ReadURL+"/opl/syntax/check", content, resp)
+	if statusCode(err) == http.StatusNotFound {
+		return nil, errOPLCheckUnsupported
+	}
+	if err != nil {
+		return nil, err
+	}
+	var parseErrors []string
+	for _, e := range resp.Errors {
+		parseErrors = append(parseErrors, fmt.Sprintf("%d:%d: %s", e.Start.Line, e.Start.Column, e.Message))
+	}
+	return parseErrors, nil
+}
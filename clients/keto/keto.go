@@ -3,8 +3,8 @@ package keto
 import (
 	"context"
 	. "context"
-	"crypto/tls"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 	"time"
@@ -12,10 +12,14 @@ import (
 	"golang.org/x/oauth2"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/credentials/oauth"
+	"google.golang.org/grpc/status"
 
+	opl "github.com/ory/keto/proto/ory/keto/opl/v1alpha1"
 	rts "github.com/ory/keto/proto/ory/keto/relation_tuples/v1alpha2"
 	px "github.com/ory/x/pointerx"
 	grpcHealthV1 "google.golang.org/grpc/health/grpc_health_v1"
@@ -36,8 +40,8 @@ const (
 
 type KetoConnectionDetails struct {
 	readRemote, writeRemote, token, authority string
-	skipHostVerification                      bool
-	noTransportSecurity                       bool
+	tokenFile                                 string
+	tls                                       TLSOptions
 }
 
 func getKetoRemote(envRemote, remoteDefault string) (remote string) {
@@ -55,8 +59,16 @@ func getKetoRemote(envRemote, remoteDefault string) (remote string) {
 	}
 }
 
-func (cd *KetoConnectionDetails) dialOptions() (opts []grpc.DialOption) {
-	if cd.token != "" {
+func (cd *KetoConnectionDetails) dialOptions() (opts []grpc.DialOption, err error) {
+	// gRPC refuses to send per-RPC credentials over an insecure connection,
+	// so a token without TLS would only fail on the first call.
+	if cd.tls.Mode == TLSModeDisabled && (cd.token != "" || cd.tokenFile != "") {
+		return nil, fmt.Errorf("a bearer token is configured but TLS is disabled, set %s to %s or %s", KetoEnvTLSMode, TLSModeEnabled, TLSModeSkipVerify)
+	}
+	switch {
+	case cd.tokenFile != "":
+		opts = append(opts, grpc.WithPerRPCCredentials(&fileToken{path: cd.tokenFile}))
+	case cd.token != "":
 		opts = append(opts,
 			grpc.WithPerRPCCredentials(
 				oauth.NewOauthAccess(&oauth2.Token{AccessToken: cd.token})))
@@ -66,19 +78,16 @@ func (cd *KetoConnectionDetails) dialOptions() (opts []grpc.DialOption) {
 	}
 
 	// TLS settings
-	switch {
-	case cd.noTransportSecurity:
+	tlsConfig, err := cd.tls.Config()
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig == nil {
 		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	case cd.skipHostVerification:
-		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
-			// nolint explicity set through scary flag
-			InsecureSkipVerify: true,
-		})))
-	default:
-		// Defaults to the default host root CA bundle
-		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(nil)))
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
 	}
-	return opts
+	return opts, nil
 }
 
 func getKetoAuthority() string {
@@ -87,15 +96,36 @@ func getKetoAuthority() string {
 
 func NewKetoConnectionDetailsFromEnv() KetoConnectionDetails {
 	return KetoConnectionDetails{
-		readRemote:           getKetoRemote(KetoEnvReadRemote, KetoReadRemoteDefault),
-		writeRemote:          getKetoRemote(KetoEnvWriteRemote, KetoWriteRemoteDefault),
-		token:                os.Getenv(KetoEnvAuthToken),
-		authority:            getKetoAuthority(),
-		skipHostVerification: true,
-		noTransportSecurity:  true,
+		readRemote:  getKetoRemote(KetoEnvReadRemote, KetoReadRemoteDefault),
+		writeRemote: getKetoRemote(KetoEnvWriteRemote, KetoWriteRemoteDefault),
+		token:       os.Getenv(KetoEnvAuthToken),
+		tokenFile:   os.Getenv(KetoEnvAuthTokenFile),
+		authority:   getKetoAuthority(),
+		tls:         TLSOptionsFromEnv(),
+	}
+}
+
+// NewKetoConnectionDetails returns the connection details for a Keto instance
+// serving its read and write APIs on the given remotes without transport security.
+func NewKetoConnectionDetails(readRemote, writeRemote string) KetoConnectionDetails {
+	return KetoConnectionDetails{
+		readRemote:  readRemote,
+		writeRemote: writeRemote,
+		tls:         TLSOptions{Mode: TLSModeDisabled},
 	}
 }
 
+// SetTLSOptions sets the transport security used to connect to Keto.
+func (cd *KetoConnectionDetails) SetTLSOptions(opts TLSOptions) {
+	cd.tls = opts
+}
+
+// SetTokenFile reads the bearer token from the given file instead of the
+// environment. The file is reread whenever it changes.
+func (cd *KetoConnectionDetails) SetTokenFile(path string) {
+	cd.tokenFile = path
+}
+
 func (cd *KetoConnectionDetails) ReadConn(ctx context.Context) (*grpc.ClientConn, error) {
 	return KetoConn(ctx,
 		cd.readRemote,
@@ -110,22 +140,34 @@ func (cd *KetoConnectionDetails) WriteConn(ctx context.Context) (*grpc.ClientCon
 	)
 }
 
+// KetoConn returns a connection to the remote without waiting for it to be
+// established. The connection is set up in the background and re-established
+// with exponential backoff whenever it breaks, so RPCs fail with Unavailable
+// instead of blocking while Keto is unreachable.
 func KetoConn(ctx context.Context, remote string, cd *KetoConnectionDetails) (*grpc.ClientConn, error) {
 	timeout := 3 * time.Second
 	if d, ok := ctx.Value(ContextKeyTimeout).(time.Duration); ok {
 		timeout = d
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
-	defer cancel()
+	reconnectBackoff := backoff.DefaultConfig
+	reconnectBackoff.MaxDelay = 30 * time.Second
+
+	opts, err := cd.dialOptions()
+	if err != nil {
+		return nil, err
+	}
 
 	return grpc.DialContext(
 		ctx,
 		remote,
 		append([]grpc.DialOption{
-			grpc.WithBlock(),
+			grpc.WithConnectParams(grpc.ConnectParams{
+				Backoff:           reconnectBackoff,
+				MinConnectTimeout: timeout,
+			}),
 			grpc.WithDisableHealthCheck(),
-		}, cd.dialOptions()...)...,
+		}, opts...)...,
 	)
 }
 
@@ -235,12 +277,18 @@ func (g *KetoGrpcClient) QueryTuple(ctx Context, q *rts.RelationQuery, opts ...P
 func (g *KetoGrpcClient) QueryAllTuples(ctx Context, q *rts.RelationQuery, pagesize int) ([]*rts.RelationTuple, error) {
 	tuples := make([]*rts.RelationTuple, 0)
 	resp, err := g.QueryTuple(ctx, q, KetoWithSize(pagesize))
+	if err != nil {
+		return nil, err
+	}
 	tuples = append(tuples, resp.RelationTuples...)
-	for resp.NextPageToken != "" && err != nil {
+	for resp.NextPageToken != "" {
 		resp, err = g.QueryTuple(ctx, q, KetoWithToken(resp.NextPageToken), KetoWithSize(pagesize))
+		if err != nil {
+			return nil, err
+		}
 		tuples = append(tuples, resp.RelationTuples...)
 	}
-	return tuples, err
+	return tuples, nil
 }
 
 func (g *KetoGrpcClient) Check(ctx Context, r *rts.RelationTuple) (bool, error) {
@@ -250,8 +298,11 @@ func (g *KetoGrpcClient) Check(ctx Context, r *rts.RelationTuple) (bool, error)
 		Tuple: r,
 	}
 	resp, err := c.Check(ctx, req)
+	if err != nil {
+		return false, err
+	}
 
-	return resp.Allowed, err
+	return resp.Allowed, nil
 }
 
 func (g *KetoGrpcClient) Expand(ctx Context, ss *rts.Subject, depth int) (*rts.SubjectTree, error) {
@@ -261,53 +312,128 @@ func (g *KetoGrpcClient) Expand(ctx Context, ss *rts.Subject, depth int) (*rts.S
 		Subject:  ss,
 		MaxDepth: int32(depth),
 	})
-	return resp.Tree, err
+	if err != nil {
+		return nil, err
+	}
+	return resp.Tree, nil
 }
 
-// TODO: not sure if this is the correct thing to do
-func (g *KetoGrpcClient) WaitUntilLive(ctx Context) error {
-	c := grpcHealthV1.NewHealthClient(g.rc)
-
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
+// CheckTenantPermission implements PermissionChecker.
+func (g *KetoGrpcClient) CheckTenantPermission(ctx Context, name, permission string, subject Subject) (bool, error) {
+	return g.Check(ctx, tenantPermissionTuple(name, permission, subject))
+}
 
-	cl, err := c.Watch(ctx, &grpcHealthV1.HealthCheckRequest{})
+// ExpandSubjectSet implements AccessExplainer.
+func (g *KetoGrpcClient) ExpandSubjectSet(ctx Context, set SubjectSet, depth int) (*AccessTree, error) {
+	tree, err := g.Expand(ctx, rts.NewSubjectSet(set.Namespace, set.Object, set.Relation), depth)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return accessTreeFromProto(tree), nil
+}
 
-	for {
-		select {
-		case <-g.ctx.Done():
-			return nil
-		default:
+// Healthy checks the gRPC health service on both the read and the write API.
+func (g *KetoGrpcClient) Healthy(ctx Context) error {
+	for _, api := range []struct {
+		name string
+		conn *grpc.ClientConn
+	}{{"read", g.rc}, {"write", g.wc}} {
+		resp, err := grpcHealthV1.NewHealthClient(api.conn).Check(ctx, &grpcHealthV1.HealthCheckRequest{})
+		if err != nil {
+			return fmt.Errorf("keto %s API is unreachable: %w", api.name, err)
+		}
+		if resp.Status != grpcHealthV1.HealthCheckResponse_SERVING {
+			return fmt.Errorf("keto %s API is %s", api.name, resp.Status)
 		}
-		resp, err := cl.Recv()
+	}
+	return nil
+}
+
+// ReadyzCheck implements ReadinessChecker.
+func (g *KetoGrpcClient) ReadyzCheck(req *http.Request) error {
+	ctx, cancel := context.WithTimeout(req.Context(), readyzTimeout)
+	defer cancel()
+	return g.Healthy(ctx)
+}
+
+// WaitUntilLive blocks until both Keto APIs report serving, or returns the
+// last health check error once the timeout expires or ctx is done.
+func (g *KetoGrpcClient) WaitUntilLive(ctx Context, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-		if resp.Status == grpcHealthV1.HealthCheckResponse_SERVING {
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		err := g.Healthy(ctx)
+		if err == nil {
 			return nil
 		}
-		if err != nil {
-			return err
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("keto did not become live within %s: %w", timeout, err)
+		case <-ticker.C:
 		}
 	}
 }
 
-// TODO: add namespace client
-// func (g *KetoGrpcClient) Namespace() error {}
+// VerifyNamespaces implements NamespaceVerifier.
+func (g *KetoGrpcClient) VerifyNamespaces(ctx Context) error {
+	return verifyNamespaces(ctx, g)
+}
+
+func (g *KetoGrpcClient) listNamespaces(ctx Context) ([]string, error) {
+	resp, err := rts.NewNamespacesServiceClient(g.rc).ListNamespaces(ctx, &rts.ListNamespacesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(resp.Namespaces))
+	for i, ns := range resp.Namespaces {
+		names[i] = ns.Name
+	}
+	return names, nil
+}
+
+func (g *KetoGrpcClient) relationDeclared(ctx Context, namespace, relation string) (bool, error) {
+	_, err := g.QueryTuple(ctx, &rts.RelationQuery{
+		Namespace: px.Ptr(namespace),
+		Relation:  px.Ptr(relation),
+	}, KetoWithSize(1))
+	switch status.Code(err) {
+	case codes.OK:
+		return true, nil
+	case codes.InvalidArgument, codes.NotFound:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+func (g *KetoGrpcClient) checkOPL(ctx Context, content []byte) ([]string, error) {
+	resp, err := opl.NewSyntaxServiceClient(g.rc).Check(ctx, &opl.CheckRequest{Content: content})
+	if status.Code(err) == codes.Unimplemented {
+		return nil, errOPLCheckUnsupported
+	}
+	if err != nil {
+		return nil, err
+	}
+	var parseErrors []string
+	for _, e := range resp.ParseErrors {
+		parseErrors = append(parseErrors, fmt.Sprintf("%d:%d: %s", e.Start.GetLine(), e.Start.GetColumn(), e.Message))
+	}
+	return parseErrors, nil
+}
 
 // function that checks if an observability tenant exists in keto
 func (g *KetoGrpcClient) ObservabilityTenantExistsInKeto(ctx context.Context, name string) (bool, error) {
 
+	tenantTuple := observabilityTenantTuple(name)
 	query := rts.RelationQuery{
-		Namespace: px.Ptr("ObservabilityTenant"),
-		Object:    px.Ptr(name),
-		Relation:  px.Ptr("organizations"),
-		Subject: rts.NewSubjectSet(
-			"Organization",
-			"main", //TODO: decide whether to hardcode this or not
-			"",
-		),
+		Namespace: px.Ptr(tenantTuple.Namespace),
+		Object:    px.Ptr(tenantTuple.Object),
+		Relation:  px.Ptr(tenantTuple.Relation),
+		Subject:   tenantTuple.Subject,
 	}
 
 	respTuples, err := g.QueryAllTuples(ctx, &query, 100)
@@ -324,18 +450,7 @@ func (g *KetoGrpcClient) ObservabilityTenantExistsInKeto(ctx context.Context, na
 
 // function that creates an observability tenant in keto
 func (g *KetoGrpcClient) CreateObservabilityTenantInKeto(ctx context.Context, name string) error {
-	tenantTuple := &rts.RelationTuple{
-		Namespace: "ObservabilityTenant",
-		Object:    name,
-		Relation:  "organizations",
-		Subject: rts.NewSubjectSet(
-			"Organization",
-			"main", //TODO: decide whether to hardcode this or not
-			"",
-		),
-	}
-
-	return g.CreateTuple(ctx, tenantTuple)
+	return g.CreateTuple(ctx, observabilityTenantTuple(name))
 }
 
 // function that create an obersevability tenant in keto if it doesn't exist
@@ -358,16 +473,72 @@ func (g *KetoGrpcClient) DeleteObservabilityTenantInKeto(ctx context.Context, na
 		return fmt.Errorf("observability tenant name cannot be empty")
 	}
 
-	// delete the relation tuple for the tenant
-	tenantTuple := &rts.RelationTuple{
-		Namespace: "ObservabilityTenant",
-		Object:    name,
-		Relation:  "organizations",
-		Subject: rts.NewSubjectSet(
-			"Organization",
-			"main", //TODO: decide whether to hardcode this or not
-			"",
-		),
+	tuples, err := g.QueryAllTuples(ctx, &rts.RelationQuery{
+		Namespace: px.Ptr(ObservabilityTenantNamespace),
+		Object:    px.Ptr(name),
+	}, 100)
+	if err != nil {
+		return fmt.Errorf("failed to query tuples: %w", err)
+	}
+	// The tenant is also the subject of the parent and federatedReaders
+	// tuples of other tenants, which would otherwise grant access to a
+	// tenant reusing its name.
+	related, err := g.QueryAllTuples(ctx, &rts.RelationQuery{
+		Namespace: px.Ptr(ObservabilityTenantNamespace),
+		Subject:   NewSubjectSet(ObservabilityTenantNamespace, name, "").toProto(),
+	}, 100)
+	if err != nil {
+		return fmt.Errorf("failed to query tuples: %w", err)
+	}
+	tuples = append(tuples, related...)
+	if len(tuples) == 0 {
+		return nil
+	}
+	return g.TransactTuples(ctx, nil, tuples)
+}
+
+// RegisterTenant implements TenantAuthorizer.
+func (g *KetoGrpcClient) RegisterTenant(ctx context.Context, name string) error {
+	return g.CreateObservabilityTenantInKetoIfNotExists(ctx, name)
+}
+
+// UnregisterTenant implements TenantAuthorizer.
+func (g *KetoGrpcClient) UnregisterTenant(ctx context.Context, name string) error {
+	return g.DeleteObservabilityTenantInKeto(ctx, name)
+}
+
+// SyncAccessGrants implements TenantAuthorizer.
+func (g *KetoGrpcClient) SyncAccessGrants(ctx context.Context, name, relation string, subjects []Subject) error {
+	current, err := g.QueryAllTuples(ctx, &rts.RelationQuery{
+		Namespace: px.Ptr(ObservabilityTenantNamespace),
+		Object:    px.Ptr(name),
+		Relation:  px.Ptr(relation),
+	}, 100)
+	if err != nil {
+		return fmt.Errorf("failed to query tuples: %w", err)
+	}
+
+	ins, del := diffTuples(current, accessGrantTuples(name, relation, subjects))
+	if len(ins) == 0 && len(del) == 0 {
+		return nil
+	}
+	return g.TransactTuples(ctx, ins, del)
+}
+
+// SyncQueryFederation implements TenantAuthorizer.
+func (g *KetoGrpcClient) SyncQueryFederation(ctx Context, name string, tenants []string) error {
+	current, err := g.QueryAllTuples(ctx, &rts.RelationQuery{
+		Namespace: px.Ptr(ObservabilityTenantNamespace),
+		Relation:  px.Ptr(TenantFederatedReadersRelation),
+		Subject:   federatedReaderSubject(name).toProto(),
+	}, 100)
+	if err != nil {
+		return fmt.Errorf("failed to query tuples: %w", err)
+	}
+
+	ins, del := diffTuples(current, queryFederationTuples(name, tenants))
+	if len(ins) == 0 && len(del) == 0 {
+		return nil
 	}
-	return g.DeleteTuple(ctx, tenantTuple)
+	return g.TransactTuples(ctx, ins, del)
 }
@@ -0,0 +1,14 @@
+package keto_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestKeto(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Keto Client Suite")
+}
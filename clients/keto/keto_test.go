@@ -0,0 +1,157 @@
+package keto_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	rts "github.com/ory/keto/proto/ory/keto/relation_tuples/v1alpha2"
+	px "github.com/ory/x/pointerx"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/traceshield/trace-shield-controller/clients/keto"
+	"github.com/traceshield/trace-shield-controller/clients/keto/ketotest"
+)
+
+var _ = Describe("KetoGrpcClient", func() {
+	var (
+		ctx    context.Context
+		server *ketotest.Server
+		client *keto.KetoGrpcClient
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		var err error
+		server, err = ketotest.NewServer()
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(server.Stop)
+
+		client, err = keto.NewKetoGrpcClient(ctx, server.ConnectionDetails())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Context("observability tenants", func() {
+		It("creates a tenant only once", func() {
+			Expect(client.CreateObservabilityTenantInKetoIfNotExists(ctx, "team-a")).To(Succeed())
+			Expect(client.CreateObservabilityTenantInKetoIfNotExists(ctx, "team-a")).To(Succeed())
+
+			Expect(server.Tuples()).To(HaveLen(1))
+			exists, err := client.ObservabilityTenantExistsInKeto(ctx, "team-a")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeTrue())
+		})
+
+		It("deletes a tenant", func() {
+			Expect(client.CreateObservabilityTenantInKeto(ctx, "team-a")).To(Succeed())
+			Expect(client.DeleteObservabilityTenantInKeto(ctx, "team-a")).To(Succeed())
+
+			exists, err := client.ObservabilityTenantExistsInKeto(ctx, "team-a")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeFalse())
+		})
+
+		It("refuses to delete a tenant without a name", func() {
+			Expect(client.DeleteObservabilityTenantInKeto(ctx, "")).NotTo(Succeed())
+		})
+	})
+
+	Context("connecting", func() {
+		It("does not wait for keto to be reachable", func() {
+			server.Stop()
+
+			client, err := keto.NewKetoGrpcClient(ctx, server.ConnectionDetails())
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.ObservabilityTenantExistsInKeto(ctx, "team-a")
+			Expect(status.Code(errors.Unwrap(err))).To(Equal(codes.Unavailable))
+		})
+	})
+
+	Context("health", func() {
+		It("waits until keto is live", func() {
+			server.SetServing(false)
+			time.AfterFunc(500*time.Millisecond, func() { server.SetServing(true) })
+
+			Expect(client.WaitUntilLive(ctx, 5*time.Second)).To(Succeed())
+		})
+
+		It("gives up waiting after the timeout", func() {
+			server.Stop()
+
+			start := time.Now()
+			Expect(client.WaitUntilLive(ctx, time.Second)).NotTo(Succeed())
+			Expect(time.Since(start)).To(BeNumerically("<", 3*time.Second))
+		})
+	})
+
+	Context("querying tuples", func() {
+		It("follows page tokens", func() {
+			for _, name := range []string{"a", "b", "c", "d", "e"} {
+				Expect(client.CreateObservabilityTenantInKeto(ctx, name)).To(Succeed())
+			}
+
+			tuples, err := client.QueryAllTuples(ctx, &rts.RelationQuery{
+				Namespace: px.Ptr("ObservabilityTenant"),
+			}, 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tuples).To(HaveLen(5))
+		})
+	})
+
+	Context("checking and expanding", func() {
+		BeforeEach(func() {
+			server.Insert(
+				&rts.RelationTuple{
+					Namespace: "ObservabilityTenant",
+					Object:    "team-a",
+					Relation:  "viewers",
+					Subject:   rts.NewSubjectSet("Group", "sre", "members"),
+				},
+				&rts.RelationTuple{
+					Namespace: "Group",
+					Object:    "sre",
+					Relation:  "members",
+					Subject:   rts.NewSubjectID("alice"),
+				},
+			)
+		})
+
+		It("checks access through subject sets", func() {
+			allowed, err := client.Check(ctx, &rts.RelationTuple{
+				Namespace: "ObservabilityTenant",
+				Object:    "team-a",
+				Relation:  "viewers",
+				Subject:   rts.NewSubjectID("alice"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allowed).To(BeTrue())
+
+			allowed, err = client.Check(ctx, &rts.RelationTuple{
+				Namespace: "ObservabilityTenant",
+				Object:    "team-a",
+				Relation:  "viewers",
+				Subject:   rts.NewSubjectID("bob"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allowed).To(BeFalse())
+		})
+
+		It("expands subject sets into a tree", func() {
+			tree, err := client.Expand(ctx, rts.NewSubjectSet("ObservabilityTenant", "team-a", "viewers"), 3)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tree.NodeType).To(Equal(rts.NodeType_NODE_TYPE_UNION))
+			Expect(tree.Children).To(HaveLen(1))
+
+			group := tree.Children[0]
+			Expect(group.Tuple.Namespace).To(Equal("Group"))
+			Expect(group.Children).To(HaveLen(1))
+			Expect(group.Children[0].Tuple.Subject.GetId()).To(Equal("alice"))
+		})
+	})
+})
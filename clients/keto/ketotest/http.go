@@ -0,0 +1,277 @@
+package ketotest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	opl "github.com/ory/keto/proto/ory/keto/opl/v1alpha1"
+	rts "github.com/ory/keto/proto/ory/keto/relation_tuples/v1alpha2"
+	"google.golang.org/grpc/codes"
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+type httpSubjectSet struct {
+	Namespace string `json:"namespace"`
+	Object    string `json:"object"`
+	Relation  string `json:"relation"`
+}
+
+type httpNamespace struct {
+	Name string `json:"name"`
+}
+
+type httpRelationTuple struct {
+	Namespace  string          `json:"namespace"`
+	Object     string          `json:"object"`
+	Relation   string          `json:"relation"`
+	SubjectID  *string         `json:"subject_id,omitempty"`
+	SubjectSet *httpSubjectSet `json:"subject_set,omitempty"`
+}
+
+func (t httpRelationTuple) toProto() *rts.RelationTuple {
+	out := &rts.RelationTuple{
+		Namespace: t.Namespace,
+		Object:    t.Object,
+		Relation:  t.Relation,
+	}
+	if t.SubjectSet != nil {
+		out.Subject = rts.NewSubjectSet(t.SubjectSet.Namespace, t.SubjectSet.Object, t.SubjectSet.Relation)
+	} else if t.SubjectID != nil {
+		out.Subject = rts.NewSubjectID(*t.SubjectID)
+	}
+	return out
+}
+
+func fromProto(t *rts.RelationTuple) httpRelationTuple {
+	out := httpRelationTuple{
+		Namespace: t.Namespace,
+		Object:    t.Object,
+		Relation:  t.Relation,
+	}
+	if set := t.Subject.GetSet(); set != nil {
+		out.SubjectSet = &httpSubjectSet{Namespace: set.Namespace, Object: set.Object, Relation: set.Relation}
+	} else {
+		id := t.Subject.GetId()
+		out.SubjectID = &id
+	}
+	return out
+}
+
+// HTTPHandler returns a handler serving the subset of the Keto REST API used
+// by the controller, backed by the same tuple store as the gRPC services.
+func (s *Server) HTTPHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/relation-tuples", s.handleListRelationTuples)
+	mux.HandleFunc("/admin/relation-tuples", s.handlePatchRelationTuples)
+	mux.HandleFunc("/relation-tuples/check/openapi", s.handleCheck)
+	mux.HandleFunc("/relation-tuples/expand", s.handleExpand)
+	mux.HandleFunc("/namespaces", s.handleListNamespaces)
+	mux.HandleFunc("/opl/syntax/check", s.handleCheckOPL)
+	mux.HandleFunc("/health/ready", s.handleReady)
+	return mux
+}
+
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.health.Check(r.Context(), &healthgrpc.HealthCheckRequest{})
+	if err != nil || resp.Status != healthgrpc.HealthCheckResponse_SERVING {
+		http.Error(w, "not ready", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleCheck(w http.ResponseWriter, r *http.Request) {
+	q := queryFromValues(r.URL.Query())
+	if q.Namespace == nil || q.Object == nil || q.Relation == nil || q.Subject == nil {
+		http.Error(w, "namespace, object, relation and subject are required", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.RLock()
+	allowed := s.check(*q.Namespace, *q.Object, *q.Relation, q.Subject, defaultMaxDepth)
+	s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(struct {
+		Allowed bool `json:"allowed"`
+	}{Allowed: allowed})
+}
+
+type httpSubjectTree struct {
+	Type     string            `json:"type"`
+	Tuple    httpRelationTuple `json:"tuple"`
+	Children []httpSubjectTree `json:"children,omitempty"`
+}
+
+func toHttpSubjectTree(t *rts.SubjectTree) httpSubjectTree {
+	out := httpSubjectTree{
+		Type:  strings.TrimPrefix(strings.ToLower(t.NodeType.String()), "node_type_"),
+		Tuple: fromProto(t.Tuple),
+	}
+	for _, child := range t.Children {
+		out.Children = append(out.Children, toHttpSubjectTree(child))
+	}
+	return out
+}
+
+func (s *Server) handleExpand(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	depth, _ := strconv.Atoi(v.Get("max-depth"))
+
+	s.mu.RLock()
+	tree := s.expand(&rts.SubjectSet{
+		Namespace: v.Get("namespace"),
+		Object:    v.Get("object"),
+		Relation:  v.Get("relation"),
+	}, maxDepth(int32(depth)))
+	s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(toHttpSubjectTree(tree))
+}
+
+func (s *Server) handleListNamespaces(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	resp := struct {
+		Namespaces []httpNamespace `json:"namespaces"`
+	}{Namespaces: []httpNamespace{}}
+	for _, ns := range s.namespaces {
+		resp.Namespaces = append(resp.Namespaces, httpNamespace{Name: ns.Name})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (s *Server) handleCheckOPL(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	content, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, _ := syntaxService{}.Check(r.Context(), &opl.CheckRequest{Content: content})
+
+	type position struct {
+		Line   uint32 `json:"line"`
+		Column uint32 `json:"column"`
+	}
+	type parseError struct {
+		Message string   `json:"message"`
+		Start   position `json:"start"`
+		End     position `json:"end"`
+	}
+	out := struct {
+		Errors []parseError `json:"errors"`
+	}{Errors: []parseError{}}
+	for _, e := range resp.ParseErrors {
+		out.Errors = append(out.Errors, parseError{
+			Message: e.Message,
+			Start:   position{Line: e.Start.GetLine(), Column: e.Start.GetColumn()},
+			End:     position{Line: e.End.GetLine(), Column: e.End.GetColumn()},
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (s *Server) handleListRelationTuples(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := queryFromValues(r.URL.Query())
+	s.mu.RLock()
+	err := s.validateQuery(q)
+	tuples := s.query(q)
+	s.mu.RUnlock()
+	if err != nil {
+		code := http.StatusBadRequest
+		if status.Code(err) == codes.NotFound {
+			code = http.StatusNotFound
+		}
+		http.Error(w, status.Convert(err).Message(), code)
+		return
+	}
+
+	offset, _ := strconv.Atoi(r.URL.Query().Get("page_token"))
+	if offset < 0 || offset > len(tuples) {
+		http.Error(w, "invalid page token", http.StatusBadRequest)
+		return
+	}
+	end := len(tuples)
+	if size, _ := strconv.Atoi(r.URL.Query().Get("page_size")); size > 0 && offset+size < end {
+		end = offset + size
+	}
+
+	resp := struct {
+		RelationTuples []httpRelationTuple `json:"relation_tuples"`
+		NextPageToken  string              `json:"next_page_token"`
+	}{RelationTuples: []httpRelationTuple{}}
+	for _, t := range tuples[offset:end] {
+		resp.RelationTuples = append(resp.RelationTuples, fromProto(t))
+	}
+	if end < len(tuples) {
+		resp.NextPageToken = strconv.Itoa(end)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (s *Server) handlePatchRelationTuples(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var patches []struct {
+		Action        string            `json:"action"`
+		RelationTuple httpRelationTuple `json:"relation_tuple"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&patches); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range patches {
+		switch p.Action {
+		case "insert":
+			s.insert(p.RelationTuple.toProto())
+		case "delete":
+			s.delete(p.RelationTuple.toProto())
+		default:
+			http.Error(w, "unknown action "+p.Action, http.StatusBadRequest)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func queryFromValues(v url.Values) *rts.RelationQuery {
+	q := &rts.RelationQuery{
+		Namespace: optional(v.Get("namespace")),
+		Object:    optional(v.Get("object")),
+		Relation:  optional(v.Get("relation")),
+	}
+	if v.Has("subject_id") {
+		q.Subject = rts.NewSubjectID(v.Get("subject_id"))
+	} else if v.Has("subject_set.namespace") {
+		q.Subject = rts.NewSubjectSet(v.Get("subject_set.namespace"), v.Get("subject_set.object"), v.Get("subject_set.relation"))
+	}
+	return q
+}
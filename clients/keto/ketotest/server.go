@@ -0,0 +1,457 @@
+// Package ketotest provides an in-process Keto server for tests.
+//
+// The server implements the read, write, check, expand, namespaces, OPL syntax
+// and health services of the Keto gRPC API, and the matching endpoints of the
+// REST API, on top of a simple in-memory tuple store. Subject sets are
+// followed when checking and expanding, but no Ory Permission Language
+// rewrites are evaluated. The namespace model defaults to keto.Namespaces and
+// is only used to reject queries for unknown namespaces and relations.
+package ketotest
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+
+	opl "github.com/ory/keto/proto/ory/keto/opl/v1alpha1"
+	rts "github.com/ory/keto/proto/ory/keto/relation_tuples/v1alpha2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/traceshield/trace-shield-controller/clients/keto"
+)
+
+// defaultMaxDepth is used for check and expand requests that do not set a max depth.
+const defaultMaxDepth = 5
+
+// Server is an in-memory Keto server listening on two local TCP ports, one
+// for the read API and one for the write API.
+type Server struct {
+	mu         sync.RWMutex
+	tuples     []*rts.RelationTuple
+	namespaces []keto.Namespace
+
+	readLis, writeLis net.Listener
+	readSrv, writeSrv *grpc.Server
+	health            *health.Server
+}
+
+// NewServer starts a new in-memory Keto server on random local ports.
+// Call Stop to shut it down.
+func NewServer() (*Server, error) {
+	readLis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	writeLis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		readLis.Close()
+		return nil, err
+	}
+
+	s := &Server{
+		readLis:  readLis,
+		writeLis: writeLis,
+		readSrv:  grpc.NewServer(),
+		writeSrv: grpc.NewServer(),
+		health:   health.NewServer(),
+
+		namespaces: keto.Namespaces,
+	}
+
+	rts.RegisterReadServiceServer(s.readSrv, &readService{s: s})
+	rts.RegisterCheckServiceServer(s.readSrv, &checkService{s: s})
+	rts.RegisterExpandServiceServer(s.readSrv, &expandService{s: s})
+	rts.RegisterNamespacesServiceServer(s.readSrv, &namespacesService{s: s})
+	opl.RegisterSyntaxServiceServer(s.readSrv, &syntaxService{})
+	rts.RegisterWriteServiceServer(s.writeSrv, &writeService{s: s})
+	healthgrpc.RegisterHealthServer(s.readSrv, s.health)
+	healthgrpc.RegisterHealthServer(s.writeSrv, s.health)
+
+	go s.readSrv.Serve(readLis)   //nolint:errcheck
+	go s.writeSrv.Serve(writeLis) //nolint:errcheck
+
+	return s, nil
+}
+
+// ReadRemote returns the address of the read API.
+func (s *Server) ReadRemote() string {
+	return s.readLis.Addr().String()
+}
+
+// WriteRemote returns the address of the write API.
+func (s *Server) WriteRemote() string {
+	return s.writeLis.Addr().String()
+}
+
+// ConnectionDetails returns connection details pointing a Keto client at this server.
+func (s *Server) ConnectionDetails() keto.KetoConnectionDetails {
+	return keto.NewKetoConnectionDetails(s.ReadRemote(), s.WriteRemote())
+}
+
+// Stop shuts down both gRPC servers.
+func (s *Server) Stop() {
+	s.readSrv.Stop()
+	s.writeSrv.Stop()
+}
+
+// SetServing changes the status reported by the health services of both APIs.
+func (s *Server) SetServing(serving bool) {
+	if serving {
+		s.health.Resume()
+	} else {
+		s.health.Shutdown()
+	}
+}
+
+// SetNamespaces replaces the namespace model the server pretends to be
+// configured with.
+func (s *Server) SetNamespaces(namespaces []keto.Namespace) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.namespaces = namespaces
+}
+
+// validateQuery rejects queries for namespaces and relations missing from the model.
+func (s *Server) validateQuery(q *rts.RelationQuery) error {
+	if q == nil || q.Namespace == nil {
+		return nil
+	}
+	for _, ns := range s.namespaces {
+		if ns.Name != *q.Namespace {
+			continue
+		}
+		if q.Relation == nil {
+			return nil
+		}
+		for _, rel := range ns.Relations {
+			if rel == *q.Relation {
+				return nil
+			}
+		}
+		return status.Errorf(codes.InvalidArgument, "relation %q is not defined in namespace %q", *q.Relation, ns.Name)
+	}
+	return status.Errorf(codes.NotFound, "namespace %q is not defined", *q.Namespace)
+}
+
+// Tuples returns a copy of all relation tuples currently stored.
+func (s *Server) Tuples() []*rts.RelationTuple {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]*rts.RelationTuple, len(s.tuples))
+	for i, t := range s.tuples {
+		out[i] = proto.Clone(t).(*rts.RelationTuple)
+	}
+	return out
+}
+
+// Insert adds relation tuples to the store, ignoring duplicates.
+func (s *Server) Insert(tuples ...*rts.RelationTuple) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, t := range tuples {
+		s.insert(t)
+	}
+}
+
+// Reset removes all relation tuples from the store.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tuples = nil
+}
+
+func (s *Server) insert(t *rts.RelationTuple) {
+	for _, existing := range s.tuples {
+		if proto.Equal(existing, t) {
+			return
+		}
+	}
+	s.tuples = append(s.tuples, proto.Clone(t).(*rts.RelationTuple))
+}
+
+func (s *Server) delete(t *rts.RelationTuple) {
+	kept := s.tuples[:0]
+	for _, existing := range s.tuples {
+		if !proto.Equal(existing, t) {
+			kept = append(kept, existing)
+		}
+	}
+	s.tuples = kept
+}
+
+// query returns all stored tuples matching q. A nil query matches everything.
+func (s *Server) query(q *rts.RelationQuery) []*rts.RelationTuple {
+	out := make([]*rts.RelationTuple, 0)
+	for _, t := range s.tuples {
+		if matches(q, t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func matches(q *rts.RelationQuery, t *rts.RelationTuple) bool {
+	if q == nil {
+		return true
+	}
+	if q.Namespace != nil && *q.Namespace != t.Namespace {
+		return false
+	}
+	if q.Object != nil && *q.Object != t.Object {
+		return false
+	}
+	if q.Relation != nil && *q.Relation != t.Relation {
+		return false
+	}
+	if q.Subject != nil && !proto.Equal(q.Subject, t.Subject) {
+		return false
+	}
+	return true
+}
+
+// check reports whether subject is related to namespace:object#relation,
+// either directly or through subject sets.
+func (s *Server) check(namespace, object, relation string, subject *rts.Subject, depth int) bool {
+	if depth <= 0 {
+		return false
+	}
+	for _, t := range s.tuples {
+		if t.Namespace != namespace || t.Object != object || t.Relation != relation {
+			continue
+		}
+		if proto.Equal(t.Subject, subject) {
+			return true
+		}
+		if set := t.Subject.GetSet(); set != nil {
+			if s.check(set.Namespace, set.Object, set.Relation, subject, depth-1) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// expand builds the subject tree for the given subject set.
+func (s *Server) expand(set *rts.SubjectSet, depth int) *rts.SubjectTree {
+	node := &rts.SubjectTree{
+		NodeType: rts.NodeType_NODE_TYPE_LEAF,
+		Tuple: &rts.RelationTuple{
+			Namespace: set.Namespace,
+			Object:    set.Object,
+			Relation:  set.Relation,
+			Subject:   rts.NewSubjectSet(set.Namespace, set.Object, set.Relation),
+		},
+	}
+	if depth <= 0 {
+		return node
+	}
+
+	for _, t := range s.tuples {
+		if t.Namespace != set.Namespace || t.Object != set.Object || t.Relation != set.Relation {
+			continue
+		}
+		node.NodeType = rts.NodeType_NODE_TYPE_UNION
+		if child := t.Subject.GetSet(); child != nil {
+			node.Children = append(node.Children, s.expand(child, depth-1))
+			continue
+		}
+		node.Children = append(node.Children, &rts.SubjectTree{
+			NodeType: rts.NodeType_NODE_TYPE_LEAF,
+			Tuple:    proto.Clone(t).(*rts.RelationTuple),
+		})
+	}
+	return node
+}
+
+func maxDepth(depth int32) int {
+	if depth < 1 {
+		return defaultMaxDepth
+	}
+	return int(depth)
+}
+
+type readService struct {
+	rts.UnimplementedReadServiceServer
+	s *Server
+}
+
+func (r *readService) ListRelationTuples(ctx context.Context, req *rts.ListRelationTuplesRequest) (*rts.ListRelationTuplesResponse, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	q := req.GetRelationQuery()
+	if q == nil && req.GetQuery() != nil {
+		q = &rts.RelationQuery{
+			Namespace: optional(req.Query.Namespace),
+			Object:    optional(req.Query.Object),
+			Relation:  optional(req.Query.Relation),
+			Subject:   req.Query.Subject,
+		}
+	}
+	if err := r.s.validateQuery(q); err != nil {
+		return nil, err
+	}
+	tuples := r.s.query(q)
+
+	offset := 0
+	if req.PageToken != "" {
+		var err error
+		if offset, err = strconv.Atoi(req.PageToken); err != nil || offset < 0 || offset > len(tuples) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token %q", req.PageToken)
+		}
+	}
+	end := len(tuples)
+	if req.PageSize > 0 && offset+int(req.PageSize) < end {
+		end = offset + int(req.PageSize)
+	}
+
+	resp := &rts.ListRelationTuplesResponse{}
+	for _, t := range tuples[offset:end] {
+		resp.RelationTuples = append(resp.RelationTuples, proto.Clone(t).(*rts.RelationTuple))
+	}
+	if end < len(tuples) {
+		resp.NextPageToken = strconv.Itoa(end)
+	}
+	return resp, nil
+}
+
+type checkService struct {
+	rts.UnimplementedCheckServiceServer
+	s *Server
+}
+
+func (c *checkService) Check(ctx context.Context, req *rts.CheckRequest) (*rts.CheckResponse, error) {
+	c.s.mu.RLock()
+	defer c.s.mu.RUnlock()
+
+	t := req.GetTuple()
+	if t == nil {
+		t = &rts.RelationTuple{
+			Namespace: req.Namespace,
+			Object:    req.Object,
+			Relation:  req.Relation,
+			Subject:   req.Subject,
+		}
+	}
+	if t.Subject == nil {
+		return nil, status.Error(codes.InvalidArgument, "subject is required")
+	}
+
+	return &rts.CheckResponse{
+		Allowed: c.s.check(t.Namespace, t.Object, t.Relation, t.Subject, maxDepth(req.MaxDepth)),
+	}, nil
+}
+
+type expandService struct {
+	rts.UnimplementedExpandServiceServer
+	s *Server
+}
+
+func (e *expandService) Expand(ctx context.Context, req *rts.ExpandRequest) (*rts.ExpandResponse, error) {
+	e.s.mu.RLock()
+	defer e.s.mu.RUnlock()
+
+	set := req.GetSubject().GetSet()
+	if set == nil {
+		return nil, status.Error(codes.InvalidArgument, "expand requires a subject set")
+	}
+
+	return &rts.ExpandResponse{
+		Tree: e.s.expand(set, maxDepth(req.MaxDepth)),
+	}, nil
+}
+
+type namespacesService struct {
+	rts.UnimplementedNamespacesServiceServer
+	s *Server
+}
+
+func (n *namespacesService) ListNamespaces(ctx context.Context, req *rts.ListNamespacesRequest) (*rts.ListNamespacesResponse, error) {
+	n.s.mu.RLock()
+	defer n.s.mu.RUnlock()
+
+	resp := &rts.ListNamespacesResponse{}
+	for _, ns := range n.s.namespaces {
+		resp.Namespaces = append(resp.Namespaces, &rts.Namespace{Name: ns.Name})
+	}
+	return resp, nil
+}
+
+// syntaxService accepts any non-empty OPL document.
+type syntaxService struct {
+	opl.UnimplementedSyntaxServiceServer
+}
+
+func (syntaxService) Check(ctx context.Context, req *opl.CheckRequest) (*opl.CheckResponse, error) {
+	resp := &opl.CheckResponse{}
+	if len(req.Content) == 0 {
+		resp.ParseErrors = append(resp.ParseErrors, &opl.ParseError{
+			Message: "empty namespace configuration",
+			Start:   &opl.SourcePosition{},
+			End:     &opl.SourcePosition{},
+		})
+	}
+	return resp, nil
+}
+
+type writeService struct {
+	rts.UnimplementedWriteServiceServer
+	s *Server
+}
+
+func (w *writeService) TransactRelationTuples(ctx context.Context, req *rts.TransactRelationTuplesRequest) (*rts.TransactRelationTuplesResponse, error) {
+	w.s.mu.Lock()
+	defer w.s.mu.Unlock()
+
+	for _, d := range req.RelationTupleDeltas {
+		if d.RelationTuple == nil {
+			return nil, status.Error(codes.InvalidArgument, "relation tuple is required")
+		}
+		switch d.Action {
+		case rts.RelationTupleDelta_ACTION_INSERT:
+			w.s.insert(d.RelationTuple)
+		case rts.RelationTupleDelta_ACTION_DELETE:
+			w.s.delete(d.RelationTuple)
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "unknown action %s", d.Action)
+		}
+	}
+	return &rts.TransactRelationTuplesResponse{}, nil
+}
+
+func (w *writeService) DeleteRelationTuples(ctx context.Context, req *rts.DeleteRelationTuplesRequest) (*rts.DeleteRelationTuplesResponse, error) {
+	w.s.mu.Lock()
+	defer w.s.mu.Unlock()
+
+	q := req.GetRelationQuery()
+	if q == nil && req.GetQuery() != nil {
+		q = &rts.RelationQuery{
+			Namespace: optional(req.Query.Namespace),
+			Object:    optional(req.Query.Object),
+			Relation:  optional(req.Query.Relation),
+			Subject:   req.Query.Subject,
+		}
+	}
+	for _, t := range w.s.query(q) {
+		w.s.delete(t)
+	}
+	return &rts.DeleteRelationTuplesResponse{}, nil
+}
+
+// optional converts the empty string of a deprecated query field into an unset filter.
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,155 @@
+package keto
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strings"
+)
+
+const (
+	UserNamespace  = "User"
+	GroupNamespace = "Group"
+
+	// GroupMembersRelation relates a group to its members.
+	GroupMembersRelation = "members"
+	// OrganizationAdminsRelation relates an organization to the subjects that manage it.
+	OrganizationAdminsRelation = "admins"
+	// OrganizationMembersRelation relates an organization to its members.
+	OrganizationMembersRelation = "members"
+	// TenantAdminsRelation, TenantEditorsRelation and TenantViewersRelation grant
+	// subjects access to an observability tenant.
+	TenantAdminsRelation  = "admins"
+	TenantEditorsRelation = "editors"
+	TenantViewersRelation = "viewers"
+	// TenantFederatedReadersRelation relates an observability tenant to the
+	// tenants whose viewers may read it through cross-tenant queries.
+	TenantFederatedReadersRelation = "federatedReaders"
+	// TenantParentRelation relates an observability tenant to its parent
+	// tenant, whose direct grants it inherits.
+	TenantParentRelation = "parent"
+
+	// TenantViewPermission and TenantEditPermission are the permits declared
+	// on observability tenants.
+	TenantViewPermission = "view"
+	TenantEditPermission = "edit"
+)
+
+// OPL is the Ory Permission Language definition of the namespaces used by the controller.
+//
+//go:embed namespaces.keto.ts
+var OPL string
+
+// Namespace is a Keto namespace and the relations the controller relies on.
+type Namespace struct {
+	Name      string
+	Relations []string
+}
+
+// Namespaces is the namespace model declared in OPL.
+var Namespaces = []Namespace{
+	{Name: UserNamespace},
+	{Name: GroupNamespace, Relations: []string{GroupMembersRelation}},
+	{Name: OrganizationNamespace, Relations: []string{OrganizationAdminsRelation, OrganizationMembersRelation}},
+	{Name: ObservabilityTenantNamespace, Relations: []string{
+		TenantOrganizationsRelation,
+		TenantAdminsRelation,
+		TenantEditorsRelation,
+		TenantViewersRelation,
+		TenantFederatedReadersRelation,
+		TenantParentRelation,
+	}},
+}
+
+// NamespaceVerifier is implemented by TenantAuthorizers that can verify the
+// namespace model deployed to Keto.
+type NamespaceVerifier interface {
+	// VerifyNamespaces returns a *ModelMismatchError if Keto does not declare
+	// every namespace and relation in Namespaces.
+	VerifyNamespaces(ctx context.Context) error
+}
+
+// ModelMismatchError describes how the namespace model deployed to Keto
+// differs from the one the controller relies on.
+type ModelMismatchError struct {
+	// MissingNamespaces are the namespaces Keto does not know about.
+	MissingNamespaces []string
+	// MissingRelations are the relations, as namespace#relation, Keto rejects.
+	MissingRelations []string
+	// SyntaxErrors are the errors Keto reported when parsing OPL.
+	SyntaxErrors []string
+}
+
+func (e *ModelMismatchError) Error() string {
+	var problems []string
+	if len(e.MissingNamespaces) > 0 {
+		problems = append(problems, "missing namespaces "+strings.Join(e.MissingNamespaces, ", "))
+	}
+	if len(e.MissingRelations) > 0 {
+		problems = append(problems, "missing relations "+strings.Join(e.MissingRelations, ", "))
+	}
+	if len(e.SyntaxErrors) > 0 {
+		problems = append(problems, "the controller OPL does not parse: "+strings.Join(e.SyntaxErrors, "; "))
+	}
+	return fmt.Sprintf("keto namespace model does not match the controller (%s), configure Keto with the OPL printed by --print-keto-opl",
+		strings.Join(problems, "; "))
+}
+
+// modelReader is the part of the Keto API needed to verify the namespace model.
+type modelReader interface {
+	listNamespaces(ctx context.Context) ([]string, error)
+	// relationDeclared reports whether Keto accepts queries for the relation.
+	relationDeclared(ctx context.Context, namespace, relation string) (bool, error)
+	// checkOPL returns the parse errors Keto reports for content, or
+	// errOPLCheckUnsupported if Keto cannot check OPL syntax.
+	checkOPL(ctx context.Context, content []byte) ([]string, error)
+}
+
+var errOPLCheckUnsupported = fmt.Errorf("keto does not support OPL syntax checks")
+
+func verifyNamespaces(ctx context.Context, r modelReader) error {
+	deployed, err := r.listNamespaces(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list keto namespaces: %w", err)
+	}
+
+	mismatch := &ModelMismatchError{}
+	for _, ns := range Namespaces {
+		if !containsString(deployed, ns.Name) {
+			mismatch.MissingNamespaces = append(mismatch.MissingNamespaces, ns.Name)
+			continue
+		}
+		for _, rel := range ns.Relations {
+			ok, err := r.relationDeclared(ctx, ns.Name, rel)
+			if err != nil {
+				return fmt.Errorf("failed to verify relation %s#%s: %w", ns.Name, rel, err)
+			}
+			if !ok {
+				mismatch.MissingRelations = append(mismatch.MissingRelations, ns.Name+"#"+rel)
+			}
+		}
+	}
+
+	syntaxErrors, err := r.checkOPL(ctx, []byte(OPL))
+	switch {
+	case err == errOPLCheckUnsupported:
+	case err != nil:
+		return fmt.Errorf("failed to check OPL syntax: %w", err)
+	default:
+		mismatch.SyntaxErrors = syntaxErrors
+	}
+
+	if len(mismatch.MissingNamespaces) > 0 || len(mismatch.MissingRelations) > 0 || len(mismatch.SyntaxErrors) > 0 {
+		return mismatch
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+// Ory Permission Language definitions for the namespaces used by the
+// trace-shield controller. Keto must be configured with this model, e.g.
+//
+//   namespaces:
+//     location: file:///etc/keto/namespaces.keto.ts
+//
+// The controller verifies at startup that the deployed model declares every
+// namespace and relation below. Keep this file in sync with Namespaces in
+// namespaces.go.
+import { Namespace, SubjectSet, Context } from "@ory/keto-namespace-types"
+
+class User implements Namespace {}
+
+class Group implements Namespace {
+  related: {
+    members: (User | SubjectSet<Group, "members">)[]
+  }
+}
+
+class Organization implements Namespace {
+  related: {
+    admins: (User | SubjectSet<Group, "members">)[]
+    members: (User | SubjectSet<Group, "members">)[]
+  }
+
+  permits = {
+    edit: (ctx: Context): boolean =>
+      this.related.admins.includes(ctx.subject),
+
+    view: (ctx: Context): boolean =>
+      this.related.members.includes(ctx.subject) ||
+      this.permits.edit(ctx),
+  }
+}
+
+class ObservabilityTenant implements Namespace {
+  related: {
+    organizations: Organization[]
+    admins: (User | SubjectSet<Group, "members">)[]
+    editors: (User | SubjectSet<Group, "members">)[]
+    viewers: (User | SubjectSet<Group, "members">)[]
+    federatedReaders: ObservabilityTenant[]
+    parent: ObservabilityTenant[]
+  }
+
+  permits = {
+    edit: (ctx: Context): boolean =>
+      this.permits.directEdit(ctx) ||
+      this.related.parent.traverse((parent) => parent.permits.directEdit(ctx)),
+
+    view: (ctx: Context): boolean =>
+      this.permits.directView(ctx) ||
+      this.permits.edit(ctx) ||
+      this.related.parent.traverse((parent) => parent.permits.directView(ctx)) ||
+      this.related.federatedReaders.traverse((tenant) => tenant.permits.directView(ctx)),
+
+    // directEdit and directView are granted on the tenant itself, without
+    // following its parent or the tenants it reads, so that neither the
+    // hierarchy nor federation is transitive.
+    directEdit: (ctx: Context): boolean =>
+      this.related.admins.includes(ctx.subject) ||
+      this.related.editors.includes(ctx.subject) ||
+      this.related.organizations.traverse((org) => org.permits.edit(ctx)),
+
+    directView: (ctx: Context): boolean =>
+      this.related.viewers.includes(ctx.subject) ||
+      this.permits.directEdit(ctx) ||
+      this.related.organizations.traverse((org) => org.permits.view(ctx)),
+  }
+}
@@ -0,0 +1,220 @@
+package keto
+
+import (
+	"regexp"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Namespaces", func() {
+	It("matches the shipped OPL", func() {
+		declared := map[string][]string{}
+		related := regexp.MustCompile(`related: \{([^}]*)\}`)
+		relation := regexp.MustCompile(`(?m)^\s+(\w+): `)
+		for name, body := range oplClasses() {
+			declared[name] = []string{}
+			if block := related.FindStringSubmatch(body); block != nil {
+				for _, m := range relation.FindAllStringSubmatch(block[1], -1) {
+					declared[name] = append(declared[name], m[1])
+				}
+			}
+		}
+
+		Expect(declared).To(HaveLen(len(Namespaces)))
+		for _, ns := range Namespaces {
+			Expect(declared).To(HaveKey(ns.Name))
+			Expect(declared[ns.Name]).To(ConsistOf(ns.Relations), "relations of %s", ns.Name)
+		}
+	})
+})
+
+var _ = Describe("OPL", func() {
+	var model oplModel
+
+	BeforeEach(func() {
+		model = parseOPL()
+	})
+
+	tenant := func(name string) Subject {
+		return NewSubjectSet(ObservabilityTenantNamespace, name, "")
+	}
+	user := func(name string) Subject {
+		return NewSubjectSet(UserNamespace, name, "")
+	}
+
+	It("does not make query federation transitive", func() {
+		// team-a reads team-b, which reads team-c.
+		tuples := []oplTuple{
+			{ObservabilityTenantNamespace, "team-b", TenantFederatedReadersRelation, tenant("team-a")},
+			{ObservabilityTenantNamespace, "team-c", TenantFederatedReadersRelation, tenant("team-b")},
+			{ObservabilityTenantNamespace, "team-a", TenantViewersRelation, user("alice")},
+			{ObservabilityTenantNamespace, "team-b", TenantEditorsRelation, user("bob")},
+		}
+
+		Expect(model.check(tuples, ObservabilityTenantNamespace, "team-b", TenantViewPermission, user("alice"))).To(BeTrue())
+		Expect(model.check(tuples, ObservabilityTenantNamespace, "team-c", TenantViewPermission, user("bob"))).To(BeTrue())
+		Expect(model.check(tuples, ObservabilityTenantNamespace, "team-c", TenantViewPermission, user("alice"))).To(BeFalse())
+		Expect(model.check(tuples, ObservabilityTenantNamespace, "team-b", TenantEditPermission, user("alice"))).To(BeFalse())
+	})
+
+	It("inherits only the direct grants of the parent", func() {
+		// team-c is a child of team-b, which is a child of team-a and reads
+		// team-d.
+		tuples := []oplTuple{
+			{ObservabilityTenantNamespace, "team-c", TenantParentRelation, tenant("team-b")},
+			{ObservabilityTenantNamespace, "team-b", TenantParentRelation, tenant("team-a")},
+			{ObservabilityTenantNamespace, "team-d", TenantFederatedReadersRelation, tenant("team-b")},
+			{ObservabilityTenantNamespace, "team-a", TenantAdminsRelation, user("alice")},
+			{ObservabilityTenantNamespace, "team-b", TenantEditorsRelation, user("bob")},
+			{ObservabilityTenantNamespace, "team-c", TenantFederatedReadersRelation, tenant("team-d")},
+			{ObservabilityTenantNamespace, "team-d", TenantViewersRelation, user("carol")},
+		}
+
+		Expect(model.check(tuples, ObservabilityTenantNamespace, "team-b", TenantEditPermission, user("alice"))).To(BeTrue())
+		Expect(model.check(tuples, ObservabilityTenantNamespace, "team-c", TenantEditPermission, user("bob"))).To(BeTrue())
+		Expect(model.check(tuples, ObservabilityTenantNamespace, "team-c", TenantViewPermission, user("alice"))).To(BeFalse())
+		Expect(model.check(tuples, ObservabilityTenantNamespace, "team-d", TenantViewPermission, user("alice"))).To(BeFalse())
+		Expect(model.check(tuples, ObservabilityTenantNamespace, "team-c", TenantViewPermission, user("carol"))).To(BeTrue())
+		Expect(model.check(tuples, ObservabilityTenantNamespace, "team-c", TenantEditPermission, user("carol"))).To(BeFalse())
+	})
+})
+
+// oplClasses returns the body of every class declared in OPL, by name.
+func oplClasses() map[string]string {
+	classes := regexp.MustCompile(`(?m)^class (\w+) implements Namespace`).FindAllStringSubmatchIndex(OPL, -1)
+	bodies := map[string]string{}
+	for i, c := range classes {
+		end := len(OPL)
+		if i+1 < len(classes) {
+			end = classes[i+1][0]
+		}
+		bodies[OPL[c[2]:c[3]]] = OPL[c[1]:end]
+	}
+	return bodies
+}
+
+// oplTerm is one alternative of a permit: a relation that includes the
+// subject, another permit, or a permit or relation of the objects related
+// through traverse.
+type oplTerm struct {
+	relation string
+	permit   string
+	traverse bool
+}
+
+// oplModel holds the terms of the permits of every namespace.
+type oplModel map[string]map[string][]oplTerm
+
+// oplTuple is a relation tuple checked against an oplModel.
+type oplTuple struct {
+	namespace, object, relation string
+	subject                     Subject
+}
+
+var (
+	oplPermits  = regexp.MustCompile(`(?s)permits = \{(.*?)\n  \}`)
+	oplPermit   = regexp.MustCompile(`(?m)^\s+(\w+): \(ctx: Context\): boolean =>`)
+	oplComment  = regexp.MustCompile(`(?m)^\s*//.*$`)
+	oplIncludes = regexp.MustCompile(`^this\.related\.(\w+)\.includes\(ctx\.subject\)$`)
+	oplThis     = regexp.MustCompile(`^this\.permits\.(\w+)\(ctx\)$`)
+	oplTraverse = regexp.MustCompile(`^this\.related\.(\w+)\.traverse\(\((\w+)\) => (\w+)\.(?:permits\.(\w+)\(ctx\)|related\.(\w+)\.includes\(ctx\.subject\))\)$`)
+)
+
+// parseOPL parses the permits of OPL, failing on expressions it does not
+// understand.
+func parseOPL() oplModel {
+	model := oplModel{}
+	for name, body := range oplClasses() {
+		model[name] = map[string][]oplTerm{}
+		block := oplPermits.FindStringSubmatch(body)
+		if block == nil {
+			continue
+		}
+		permits := oplPermit.FindAllStringSubmatchIndex(block[1], -1)
+		for i, p := range permits {
+			end := len(block[1])
+			if i+1 < len(permits) {
+				end = permits[i+1][0]
+			}
+			expr := oplComment.ReplaceAllString(block[1][p[1]:end], "")
+			expr = strings.TrimSuffix(strings.TrimSpace(expr), ",")
+			permit := block[1][p[2]:p[3]]
+			for _, alt := range strings.Split(expr, "||") {
+				term, ok := parseOPLTerm(strings.TrimSpace(alt))
+				if !ok {
+					Fail("unsupported OPL expression in " + name + "#" + permit + ": " + alt)
+				}
+				model[name][permit] = append(model[name][permit], term)
+			}
+		}
+	}
+	return model
+}
+
+func parseOPLTerm(alt string) (oplTerm, bool) {
+	if m := oplIncludes.FindStringSubmatch(alt); m != nil {
+		return oplTerm{relation: m[1]}, true
+	}
+	if m := oplThis.FindStringSubmatch(alt); m != nil {
+		return oplTerm{permit: m[1]}, true
+	}
+	if m := oplTraverse.FindStringSubmatch(alt); m != nil && m[2] == m[3] {
+		if m[4] != "" {
+			return oplTerm{traverse: true, relation: m[1], permit: m[4]}, true
+		}
+		return oplTerm{traverse: true, relation: m[1], permit: m[5]}, true
+	}
+	return oplTerm{}, false
+}
+
+// check reports whether subject holds the relation or permit on the object,
+// evaluating permits the way Keto does for the subset of OPL parseOPL
+// supports.
+func (m oplModel) check(tuples []oplTuple, namespace, object, relation string, subject Subject) bool {
+	var check func(namespace, object, relation string, depth int) bool
+	check = func(namespace, object, relation string, depth int) bool {
+		if depth == 0 {
+			return false
+		}
+		terms, ok := m[namespace][relation]
+		if !ok {
+			for _, t := range tuples {
+				if t.namespace != namespace || t.object != object || t.relation != relation {
+					continue
+				}
+				if t.subject.equal(subject) {
+					return true
+				}
+				if set := t.subject.Set; set != nil && set.Relation != "" && check(set.Namespace, set.Object, set.Relation, depth-1) {
+					return true
+				}
+			}
+			return false
+		}
+		for _, term := range terms {
+			switch {
+			case term.traverse:
+				for _, t := range tuples {
+					if t.namespace != namespace || t.object != object || t.relation != term.relation || t.subject.Set == nil {
+						continue
+					}
+					if check(t.subject.Set.Namespace, t.subject.Set.Object, term.permit, depth-1) {
+						return true
+					}
+				}
+			case term.permit != "":
+				if check(namespace, object, term.permit, depth-1) {
+					return true
+				}
+			default:
+				if check(namespace, object, term.relation, depth-1) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return check(namespace, object, relation, 10)
+}
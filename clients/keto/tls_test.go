@@ -0,0 +1,105 @@
+package keto
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TLSOptions", func() {
+	DescribeTable("validation",
+		func(opts TLSOptions, valid bool) {
+			if valid {
+				Expect(opts.Validate()).To(Succeed())
+			} else {
+				Expect(opts.Validate()).NotTo(Succeed())
+			}
+		},
+		Entry("disabled", TLSOptions{Mode: TLSModeDisabled}, true),
+		Entry("enabled with mTLS", TLSOptions{Mode: TLSModeEnabled, CertFile: "tls.crt", KeyFile: "tls.key"}, true),
+		Entry("unknown mode", TLSOptions{Mode: "strict"}, false),
+		Entry("certificate without key", TLSOptions{Mode: TLSModeEnabled, CertFile: "tls.crt"}, false),
+		Entry("CA bundle without TLS", TLSOptions{Mode: TLSModeDisabled, CAFile: "ca.crt"}, false),
+	)
+
+	It("does not configure TLS when disabled", func() {
+		cfg, err := TLSOptions{Mode: TLSModeDisabled}.Config()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg).To(BeNil())
+	})
+
+	It("rejects a CA bundle without certificates", func() {
+		caFile := filepath.Join(GinkgoT().TempDir(), "ca.crt")
+		Expect(os.WriteFile(caFile, []byte("not a certificate"), 0o600)).To(Succeed())
+
+		_, err := TLSOptions{Mode: TLSModeEnabled, CAFile: caFile}.Config()
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("KetoConnectionDetails", func() {
+	DescribeTable("rejects bearer tokens without TLS",
+		func(cd KetoConnectionDetails) {
+			_, err := cd.dialOptions()
+			Expect(err).To(MatchError(ContainSubstring("TLS is disabled")))
+		},
+		Entry("token", KetoConnectionDetails{token: "secret", tls: TLSOptions{Mode: TLSModeDisabled}}),
+		Entry("token file", KetoConnectionDetails{tokenFile: "token", tls: TLSOptions{Mode: TLSModeDisabled}}),
+	)
+
+	It("accepts a bearer token over TLS", func() {
+		cd := KetoConnectionDetails{token: "secret", tls: TLSOptions{Mode: TLSModeSkipVerify}}
+		_, err := cd.dialOptions()
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
+
+var _ = Describe("KetoHttpClient", func() {
+	DescribeTable("rejects bearer tokens without https",
+		func(readURL, writeURL string) {
+			c := NewKetoHttpClient(readURL, writeURL)
+			Expect(c.SetTokenFile("token")).To(MatchError(ContainSubstring("does not use https")))
+			Expect(c.tokenFile).To(BeNil())
+		},
+		Entry("read URL", "http://keto-read:4466", "https://keto-write:4467"),
+		Entry("write URL", "https://keto-read:4466", "http://keto-write:4467"),
+	)
+
+	It("rejects a bearer token from the environment without https", func() {
+		GinkgoT().Setenv(KetoEnvReadURL, "http://keto-read:4466")
+		GinkgoT().Setenv(KetoEnvWriteURL, "https://keto-write:4467")
+		GinkgoT().Setenv(KetoEnvAuthToken, "secret")
+
+		_, err := NewKetoHttpClientFromEnv()
+		Expect(err).To(MatchError(ContainSubstring("does not use https")))
+	})
+
+	It("accepts a bearer token over https", func() {
+		c := NewKetoHttpClient("https://keto-read:4466", "https://keto-write:4467")
+		Expect(c.SetTokenFile("token")).To(Succeed())
+	})
+})
+
+var _ = Describe("fileToken", func() {
+	It("rereads the token when the file changes", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "token")
+		Expect(os.WriteFile(path, []byte("first\n"), 0o600)).To(Succeed())
+
+		token := &fileToken{path: path}
+		md, err := token.GetRequestMetadata(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(HaveKeyWithValue("authorization", "Bearer first"))
+
+		Expect(os.WriteFile(path, []byte("second\n"), 0o600)).To(Succeed())
+		later := time.Now().Add(time.Minute)
+		Expect(os.Chtimes(path, later, later)).To(Succeed())
+
+		md, err = token.GetRequestMetadata(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md).To(HaveKeyWithValue("authorization", "Bearer second"))
+	})
+})
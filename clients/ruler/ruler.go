@@ -0,0 +1,220 @@
+// Package ruler manages the rule groups of tenants through the configuration
+// API of the Mimir and Loki rulers.
+package ruler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"sigs.k8s.io/yaml"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+)
+
+// mimirRulesPath is the path of the rule configuration API below the
+// Prometheus HTTP prefix of Mimir.
+const mimirRulesPath = "/config/v1/rules"
+
+// lokiRulesPath is the path of the rule configuration API of Loki.
+const lokiRulesPath = "/loki/api/v1/rules"
+
+// Client manages rule groups in a ruler.
+type Client struct {
+	rulesURL   string
+	httpClient *http.Client
+}
+
+// NewMimirClient returns a Client for the Mimir ruler at url, which includes
+// the Prometheus HTTP prefix.
+func NewMimirClient(url string) *Client {
+	return newClient(url, mimirRulesPath)
+}
+
+// NewLokiClient returns a Client for the Loki ruler at url.
+func NewLokiClient(url string) *Client {
+	return newClient(url, lokiRulesPath)
+}
+
+func newClient(url, path string) *Client {
+	return &Client{
+		rulesURL:   strings.TrimSuffix(url, "/") + path,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// StatusError is returned for responses outside the 2xx range.
+type StatusError struct {
+	Method, Path string
+	Code         int
+	Message      string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s %s: unexpected status %d: %s", e.Method, e.Path, e.Code, e.Message)
+}
+
+// IsInvalid reports whether the ruler rejected a rule group as invalid, e.g.
+// because a query does not parse or a ruler limit is exceeded.
+func IsInvalid(err error) bool {
+	var se *StatusError
+	return errors.As(err, &se) && se.Code == http.StatusBadRequest
+}
+
+// RuleGroups returns the rule groups of the tenant in the namespace, which
+// are none if the namespace does not exist.
+func (c *Client) RuleGroups(ctx context.Context, tenantID, namespace string) ([]observabilityv1alpha1.RuleGroup, error) {
+	data, err := c.do(ctx, http.MethodGet, tenantID, c.rulesURL+"/"+url.PathEscape(namespace), nil)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	namespaces := map[string][]observabilityv1alpha1.RuleGroup{}
+	if err := yaml.Unmarshal(data, &namespaces); err != nil {
+		return nil, err
+	}
+	return namespaces[namespace], nil
+}
+
+// SetRuleGroup creates or replaces the rule group of the tenant in the namespace.
+func (c *Client) SetRuleGroup(ctx context.Context, tenantID, namespace string, group observabilityv1alpha1.RuleGroup) error {
+	data, err := yaml.Marshal(group)
+	if err != nil {
+		return err
+	}
+	_, err = c.do(ctx, http.MethodPost, tenantID, c.rulesURL+"/"+url.PathEscape(namespace), data)
+	return err
+}
+
+// DeleteRuleGroup deletes the rule group of the tenant in the namespace, if it exists.
+func (c *Client) DeleteRuleGroup(ctx context.Context, tenantID, namespace, name string) error {
+	_, err := c.do(ctx, http.MethodDelete, tenantID, c.rulesURL+"/"+url.PathEscape(namespace)+"/"+url.PathEscape(name), nil)
+	return err
+}
+
+// DeleteNamespace deletes every rule group of the tenant in the namespace.
+func (c *Client) DeleteNamespace(ctx context.Context, tenantID, namespace string) error {
+	_, err := c.do(ctx, http.MethodDelete, tenantID, c.rulesURL+"/"+url.PathEscape(namespace), nil)
+	return err
+}
+
+// Sync makes the rule groups of the tenant in the namespace match groups,
+// uploading the groups that differ and deleting the ones not in groups.
+func (c *Client) Sync(ctx context.Context, tenantID, namespace string, groups []observabilityv1alpha1.RuleGroup) error {
+	current, err := c.RuleGroups(ctx, tenantID, namespace)
+	if err != nil {
+		return err
+	}
+	existing := map[string]observabilityv1alpha1.RuleGroup{}
+	for _, g := range current {
+		existing[g.Name] = g
+	}
+
+	for _, g := range groups {
+		if e, ok := existing[g.Name]; ok && Equal(e, g) {
+			continue
+		}
+		if err := c.SetRuleGroup(ctx, tenantID, namespace, g); err != nil {
+			return fmt.Errorf("unable to set rule group %s: %w", g.Name, err)
+		}
+	}
+	for _, e := range current {
+		if !containsGroup(groups, e.Name) {
+			if err := c.DeleteRuleGroup(ctx, tenantID, namespace, e.Name); err != nil {
+				return fmt.Errorf("unable to delete rule group %s: %w", e.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Equal reports whether the rule groups are the same once durations and
+// empty maps are normalized, as the ruler returns them.
+func Equal(a, b observabilityv1alpha1.RuleGroup) bool {
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
+
+func normalize(g observabilityv1alpha1.RuleGroup) observabilityv1alpha1.RuleGroup {
+	out := *g.DeepCopy()
+	out.Interval = normalizeDuration(out.Interval)
+	if out.Limit != nil && *out.Limit == 0 {
+		out.Limit = nil
+	}
+	for i := range out.Rules {
+		r := &out.Rules[i]
+		r.For = normalizeDuration(r.For)
+		if len(r.Labels) == 0 {
+			r.Labels = nil
+		}
+		if len(r.Annotations) == 0 {
+			r.Annotations = nil
+		}
+	}
+	return out
+}
+
+func normalizeDuration(d *observabilityv1alpha1.RuleDuration) *observabilityv1alpha1.RuleDuration {
+	if d == nil {
+		return nil
+	}
+	parsed, err := model.ParseDuration(string(*d))
+	if err != nil {
+		return d
+	}
+	if parsed == 0 {
+		return nil
+	}
+	out := observabilityv1alpha1.RuleDuration(parsed.String())
+	return &out
+}
+
+func containsGroup(groups []observabilityv1alpha1.RuleGroup, name string) bool {
+	for _, g := range groups {
+		if g.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// do sends the request as the tenant and returns the response body, which is
+// nil if the resource was not found.
+func (c *Client) do(ctx context.Context, method, tenantID, u string, body []byte) ([]byte, error) {
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Scope-OrgID", tenantID)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/yaml")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, nil
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return nil, &StatusError{Method: method, Path: req.URL.Path, Code: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+	}
+	return data, nil
+}
@@ -0,0 +1,14 @@
+package ruler_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestRuler(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Ruler Client Suite")
+}
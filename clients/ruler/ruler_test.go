@@ -0,0 +1,131 @@
+package ruler_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/clients/ruler"
+	"github.com/traceshield/trace-shield-controller/clients/ruler/rulertest"
+)
+
+var _ = Describe("Client", func() {
+	var server *rulertest.Server
+	var client *ruler.Client
+	ctx := context.Background()
+	duration := func(d string) *observabilityv1alpha1.RuleDuration {
+		out := observabilityv1alpha1.RuleDuration(d)
+		return &out
+	}
+
+	recording := observabilityv1alpha1.RuleGroup{
+		Name:     "recording",
+		Interval: duration("60s"),
+		Rules: []observabilityv1alpha1.Rule{{
+			Record: "job:up:sum",
+			Expr:   "sum by (job) (up)",
+		}},
+	}
+	alerting := observabilityv1alpha1.RuleGroup{
+		Name: "alerting",
+		Rules: []observabilityv1alpha1.Rule{{
+			Alert:       "Down",
+			Expr:        "up == 0",
+			For:         duration("5m"),
+			Labels:      map[string]string{"severity": "critical"},
+			Annotations: map[string]string{"summary": "{{ $labels.instance }} is down"},
+		}},
+	}
+
+	BeforeEach(func() {
+		server = rulertest.NewMimirServer()
+		DeferCleanup(server.Close)
+		client = ruler.NewMimirClient(server.URL())
+	})
+
+	It("syncs rule groups of a tenant", func() {
+		Expect(client.Sync(ctx, "team-a", "default.rules", []observabilityv1alpha1.RuleGroup{recording, alerting})).To(Succeed())
+		Expect(server.Groups("team-a", "default.rules")).To(Equal([]observabilityv1alpha1.RuleGroup{recording, alerting}))
+		Expect(server.Namespaces("team-b")).To(BeEmpty())
+
+		groups, err := client.RuleGroups(ctx, "team-a", "default.rules")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(groups).To(HaveLen(2))
+
+		By("removing a group")
+		Expect(client.Sync(ctx, "team-a", "default.rules", []observabilityv1alpha1.RuleGroup{alerting})).To(Succeed())
+		Expect(server.Groups("team-a", "default.rules")).To(Equal([]observabilityv1alpha1.RuleGroup{alerting}))
+
+		By("deleting the namespace")
+		Expect(client.DeleteNamespace(ctx, "team-a", "default.rules")).To(Succeed())
+		Expect(server.Namespaces("team-a")).To(BeEmpty())
+		Expect(client.DeleteNamespace(ctx, "team-a", "default.rules")).To(Succeed())
+	})
+
+	It("only uploads rule groups that changed", func() {
+		Expect(client.Sync(ctx, "team-a", "default.rules", []observabilityv1alpha1.RuleGroup{recording, alerting})).To(Succeed())
+		writes := server.Writes()
+
+		normalized := *recording.DeepCopy()
+		normalized.Interval = duration("1m")
+		server.SetGroup("team-a", "default.rules", normalized)
+		Expect(client.Sync(ctx, "team-a", "default.rules", []observabilityv1alpha1.RuleGroup{recording, alerting})).To(Succeed())
+		Expect(server.Writes()).To(Equal(writes))
+
+		By("correcting drift")
+		edited := *alerting.DeepCopy()
+		edited.Rules[0].Expr = "up < 1"
+		server.SetGroup("team-a", "default.rules", edited)
+		server.SetGroup("team-a", "default.rules", observabilityv1alpha1.RuleGroup{Name: "manual", Rules: []observabilityv1alpha1.Rule{{Record: "x", Expr: "vector(1)"}}})
+		Expect(client.Sync(ctx, "team-a", "default.rules", []observabilityv1alpha1.RuleGroup{recording, alerting})).To(Succeed())
+		Expect(server.Groups("team-a", "default.rules")).To(ConsistOf(normalized, alerting))
+	})
+
+	It("reports rule groups rejected by the ruler", func() {
+		server.Validate = func(group observabilityv1alpha1.RuleGroup) error {
+			return errors.New("parse error")
+		}
+		err := client.Sync(ctx, "team-a", "default.rules", []observabilityv1alpha1.RuleGroup{recording})
+		Expect(err).To(MatchError(ContainSubstring("parse error")))
+		Expect(ruler.IsInvalid(err)).To(BeTrue())
+	})
+
+	It("compares rule groups as the ruler returns them", func() {
+		a := *alerting.DeepCopy()
+		a.Rules[0].For = duration("300s")
+		a.Rules[0].Labels = map[string]string{"severity": "critical"}
+		Expect(ruler.Equal(alerting, a)).To(BeTrue())
+
+		a.Rules[0].Labels = map[string]string{}
+		b := *a.DeepCopy()
+		b.Rules[0].Labels = nil
+		Expect(ruler.Equal(a, b)).To(BeTrue())
+		Expect(ruler.Equal(alerting, b)).To(BeFalse())
+	})
+})
+
+var _ = Describe("Loki client", func() {
+	ctx := context.Background()
+
+	It("syncs rule groups to the Loki ruler", func() {
+		server := rulertest.NewLokiServer()
+		DeferCleanup(server.Close)
+		client := ruler.NewLokiClient(server.URL())
+
+		group := observabilityv1alpha1.RuleGroup{
+			Name: "errors",
+			Rules: []observabilityv1alpha1.Rule{{
+				Alert: "HighErrorRate",
+				Expr:  `sum by (app) (rate({namespace="default"} |= "error" [5m])) > 10`,
+			}},
+		}
+		Expect(client.Sync(ctx, "team-a", "default.logs", []observabilityv1alpha1.RuleGroup{group})).To(Succeed())
+		Expect(server.Groups("team-a", "default.logs")).To(Equal([]observabilityv1alpha1.RuleGroup{group}))
+
+		Expect(client.DeleteNamespace(ctx, "team-a", "default.logs")).To(Succeed())
+		Expect(server.Namespaces("team-a")).To(BeEmpty())
+	})
+})
@@ -0,0 +1,199 @@
+// Package rulertest provides an in-memory ruler configuration API for tests.
+//
+// The server stores rule groups per tenant and namespace as the Mimir and
+// Loki rulers do, and rejects rule groups for which a configured validation function
+// returns an error with a 400 status.
+package rulertest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/yaml"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+)
+
+// Server is an in-memory ruler listening on a local port.
+type Server struct {
+	// Validate, if set, is called for every uploaded rule group and rejects
+	// it when it returns an error.
+	Validate func(group observabilityv1alpha1.RuleGroup) error
+
+	mu     sync.Mutex
+	groups map[string]map[string][]observabilityv1alpha1.RuleGroup
+	writes int
+
+	prefix, rulesPath string
+	srv               *httptest.Server
+}
+
+// NewMimirServer starts an in-memory Mimir ruler. Call Close to shut it down.
+func NewMimirServer() *Server {
+	return newServer("/prometheus", "/prometheus/config/v1/rules")
+}
+
+// NewLokiServer starts an in-memory Loki ruler. Call Close to shut it down.
+func NewLokiServer() *Server {
+	return newServer("", "/loki/api/v1/rules")
+}
+
+func newServer(prefix, rulesPath string) *Server {
+	s := &Server{
+		groups:    map[string]map[string][]observabilityv1alpha1.RuleGroup{},
+		prefix:    prefix,
+		rulesPath: rulesPath,
+	}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// URL returns the URL clients are configured with.
+func (s *Server) URL() string {
+	return s.srv.URL + s.prefix
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Groups returns the rule groups of the tenant in the namespace.
+func (s *Server) Groups(tenantID, namespace string) []observabilityv1alpha1.RuleGroup {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]observabilityv1alpha1.RuleGroup(nil), s.groups[tenantID][namespace]...)
+}
+
+// Namespaces returns the namespaces of the tenant that hold rule groups.
+func (s *Server) Namespaces(tenantID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []string
+	for ns := range s.groups[tenantID] {
+		out = append(out, ns)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// SetGroup stores the rule group as if it was uploaded by someone else.
+func (s *Server) SetGroup(tenantID, namespace string, group observabilityv1alpha1.RuleGroup) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setGroup(tenantID, namespace, group)
+}
+
+// Writes returns the number of rule groups uploaded or deleted through the API.
+func (s *Server) Writes() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.writes
+}
+
+func (s *Server) setGroup(tenantID, namespace string, group observabilityv1alpha1.RuleGroup) {
+	if s.groups[tenantID] == nil {
+		s.groups[tenantID] = map[string][]observabilityv1alpha1.RuleGroup{}
+	}
+	groups := s.groups[tenantID][namespace]
+	for i := range groups {
+		if groups[i].Name == group.Name {
+			groups[i] = group
+			return
+		}
+	}
+	s.groups[tenantID][namespace] = append(groups, group)
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Header.Get("X-Scope-OrgID")
+	if tenantID == "" {
+		http.Error(w, "no org id", http.StatusUnauthorized)
+		return
+	}
+	rest, ok := strings.CutPrefix(r.URL.EscapedPath(), s.rulesPath+"/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	parts := strings.Split(rest, "/")
+	for i := range parts {
+		p, err := url.PathUnescape(parts[i])
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		parts[i] = p
+	}
+	namespace := parts[0]
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && len(parts) == 1:
+		groups := s.groups[tenantID][namespace]
+		if len(groups) == 0 {
+			http.Error(w, "no rule groups found", http.StatusNotFound)
+			return
+		}
+		data, err := yaml.Marshal(map[string][]observabilityv1alpha1.RuleGroup{namespace: groups})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml")
+		_, _ = w.Write(data)
+	case r.Method == http.MethodPost && len(parts) == 1:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		group := observabilityv1alpha1.RuleGroup{}
+		if err := yaml.UnmarshalStrict(body, &group); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if s.Validate != nil {
+			if err := s.Validate(group); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		s.writes++
+		s.setGroup(tenantID, namespace, group)
+		w.WriteHeader(http.StatusAccepted)
+	case r.Method == http.MethodDelete && len(parts) == 1:
+		if _, ok := s.groups[tenantID][namespace]; !ok {
+			http.Error(w, "no rule groups found", http.StatusNotFound)
+			return
+		}
+		s.writes++
+		delete(s.groups[tenantID], namespace)
+		w.WriteHeader(http.StatusAccepted)
+	case r.Method == http.MethodDelete && len(parts) == 2:
+		groups := s.groups[tenantID][namespace]
+		for i := range groups {
+			if groups[i].Name == parts[1] {
+				s.writes++
+				groups = append(groups[:i], groups[i+1:]...)
+				if len(groups) == 0 {
+					delete(s.groups[tenantID], namespace)
+				} else {
+					s.groups[tenantID][namespace] = groups
+				}
+				w.WriteHeader(http.StatusAccepted)
+				return
+			}
+		}
+		http.Error(w, "no rule group found", http.StatusNotFound)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
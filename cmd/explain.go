@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/traceshield/trace-shield-controller/clients/keto"
+)
+
+// explainAccess implements the explain-access subcommand, which prints the
+// Keto relations granting access to a tenant and the paths through which a
+// subject holds a permission.
+func explainAccess(args []string) int {
+	fs := flag.NewFlagSet("explain-access", flag.ContinueOnError)
+	ketoAPI := fs.String("keto-api", "grpc", "The Keto API to query. One of grpc or http.")
+	tenant := fs.String("tenant", "", "The tenant to explain access to.")
+	subject := fs.String("subject", "", "The subject to explain access for, e.g. User:alice or Group:sre#members.")
+	depth := fs.Int("depth", 5, "How many levels of subject sets to expand.")
+	timeout := fs.Duration("timeout", 30*time.Second, "How long to wait for Keto.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s explain-access -tenant NAME [-subject SUBJECT]\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *tenant == "" {
+		fs.Usage()
+		return 2
+	}
+
+	var explainer keto.AccessExplainer
+	switch *ketoAPI {
+	case "grpc":
+		client, err := keto.NewKetoGrpcClient(context.Background(), keto.NewKetoConnectionDetailsFromEnv())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to set up Keto gRPC client: %v\n", err)
+			return 1
+		}
+		explainer = client
+	case "http":
+		client, err := keto.NewKetoHttpClientFromEnv()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to set up Keto HTTP client: %v\n", err)
+			return 1
+		}
+		explainer = client
+	default:
+		fmt.Fprintf(os.Stderr, "unknown Keto API %q\n", *ketoAPI)
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	access, err := keto.ExplainTenantAccess(ctx, explainer, *tenant, *depth)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	var highlight *keto.Subject
+	if *subject != "" {
+		s := keto.ParseSubject(*subject)
+		highlight = &s
+	}
+	if err := access.Render(os.Stdout, highlight); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	if highlight == nil {
+		return 0
+	}
+
+	fmt.Println()
+	grants := access.GrantsFor(*highlight)
+	if len(grants) == 0 {
+		fmt.Printf("%s has no access to %s\n", highlight, *tenant)
+		return 0
+	}
+	fmt.Printf("%s has access to %s through:\n", highlight, *tenant)
+	for _, g := range grants {
+		fmt.Printf("  %s\n", g)
+	}
+	return 0
+}
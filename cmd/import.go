@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/internal/overrides"
+)
+
+// importOverrides implements the import-overrides subcommand, which creates a
+// Tenant for every tenant in the runtime overrides of the Mimir, Loki and
+// Tempo ConfigMaps referenced by the Config, so that existing deployments can
+// be adopted without writing the Tenants by hand.
+func importOverrides(args []string) int {
+	fs := flag.NewFlagSet("import-overrides", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "Print the Tenants that would be created instead of creating them.")
+	timeout := fs.Duration("timeout", 30*time.Second, "How long to wait for the Kubernetes API.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s import-overrides [-dry-run]\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	restConfig, err := ctrl.GetConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load kubeconfig: %v\n", err)
+		return 1
+	}
+	c, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to set up Kubernetes client: %v\n", err)
+		return 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	config := &observabilityv1alpha1.Config{}
+	if err := c.Get(ctx, types.NamespacedName{Name: "config"}, config); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to get config: %v\n", err)
+		return 1
+	}
+
+	var mimir, loki, tempo string
+	if config.Spec.Mimir != nil {
+		if mimir, err = configMapData(ctx, c, config.Spec.Mimir.ConfigMap); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read Mimir overrides: %v\n", err)
+			return 1
+		}
+	}
+	if config.Spec.Loki != nil {
+		if loki, err = configMapData(ctx, c, config.Spec.Loki.ConfigMap); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read Loki overrides: %v\n", err)
+			return 1
+		}
+	}
+	if config.Spec.Tempo != nil {
+		if tempo, err = configMapData(ctx, c, config.Spec.Tempo.ConfigMap); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read Tempo overrides: %v\n", err)
+			return 1
+		}
+	}
+
+	result, err := overrides.Import(mimir, loki, tempo)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	code := 0
+	for i, tenant := range result.Tenants() {
+		tenant := tenant
+		if *dryRun {
+			out, err := yaml.Marshal(&tenant)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "failed to print tenant %s: %v\n", tenant.Name, err)
+				return 1
+			}
+			if i > 0 {
+				fmt.Println("---")
+			}
+			fmt.Print(string(out))
+			continue
+		}
+
+		err := c.Create(ctx, &tenant)
+		switch {
+		case apierrors.IsAlreadyExists(err):
+			// The name of a Tenant is derived from its tenant ID, so an
+			// existing Tenant of the same name may belong to another tenant.
+			existing := &observabilityv1alpha1.Tenant{}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(&tenant), existing); err != nil {
+				fmt.Fprintf(os.Stderr, "failed to get existing tenant %s: %v\n", tenant.Name, err)
+				code = 1
+				continue
+			}
+			if existing.GetTenantID() != tenant.GetTenantID() {
+				fmt.Fprintf(os.Stderr, "tenant %s already exists for tenant ID %q, not importing tenant ID %q\n", tenant.Name, existing.GetTenantID(), tenant.GetTenantID())
+				code = 1
+				continue
+			}
+			fmt.Printf("tenant %s already exists, skipping\n", tenant.Name)
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "failed to create tenant %s: %v\n", tenant.Name, err)
+			code = 1
+		default:
+			fmt.Printf("created tenant %s\n", tenant.Name)
+		}
+	}
+
+	if len(result.Unmapped) > 0 {
+		fmt.Fprintln(os.Stderr, "the following overrides could not be imported:")
+		for _, f := range result.Unmapped {
+			fmt.Fprintf(os.Stderr, "  %s\n", f)
+		}
+	}
+	return code
+}
+
+// configMapData returns the runtime configuration held by the ConfigMap, or
+// an empty string if it does not exist.
+func configMapData(ctx context.Context, c client.Client, selector observabilityv1alpha1.ConfigMapSelector) (string, error) {
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Name: selector.Name, Namespace: selector.Namespace}, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return cm.Data[selector.Key], nil
+}
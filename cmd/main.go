@@ -18,8 +18,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"os"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -34,7 +37,9 @@ import (
 
 	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
 	"github.com/traceshield/trace-shield-controller/clients/keto"
+	"github.com/traceshield/trace-shield-controller/internal/authz"
 	observabilitycontroller "github.com/traceshield/trace-shield-controller/internal/controller/observability"
+	observabilitywebhook "github.com/traceshield/trace-shield-controller/internal/webhook/observability"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -51,18 +56,59 @@ func init() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "explain-access" {
+		os.Exit(explainAccess(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "import-overrides" {
+		os.Exit(importOverrides(os.Args[2:]))
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var ketoAPI string
+	var ketoTokenFile string
+	var printKetoOPL bool
+	var enableWebhooks bool
+	var authzAddr string
+	var authzCacheTTL time.Duration
+	var authzSubjectHeader, authzTenantHeader, authzActionHeader string
+	ketoTLS := keto.TLSOptionsFromEnv()
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&ketoAPI, "keto-api", "grpc", "The Keto API used to manage tenant permissions. One of grpc, http or none.")
+	flag.StringVar(&ketoTLS.Mode, "keto-tls-mode", ketoTLS.Mode, "The transport security used to connect to Keto. One of disabled, skip-verify or enabled.")
+	flag.StringVar(&ketoTLS.CAFile, "keto-tls-ca-file", ketoTLS.CAFile, "Path to a PEM CA bundle used to verify the Keto server certificate.")
+	flag.StringVar(&ketoTLS.CertFile, "keto-tls-cert-file", ketoTLS.CertFile, "Path to the client certificate used for mTLS with Keto.")
+	flag.StringVar(&ketoTLS.KeyFile, "keto-tls-key-file", ketoTLS.KeyFile, "Path to the client key used for mTLS with Keto.")
+	flag.StringVar(&ketoTLS.ServerName, "keto-tls-server-name", ketoTLS.ServerName, "Server name used to verify the Keto server certificate.")
+	flag.StringVar(&ketoTokenFile, "keto-bearer-token-file", os.Getenv(keto.KetoEnvAuthTokenFile),
+		"Path to a file containing the bearer token for Keto, e.g. mounted from a Secret. "+
+			"The file is reread when it changes.")
+	flag.StringVar(&authzAddr, "authz-bind-address", "",
+		"The address the gateway permission check endpoint binds to. The endpoint is disabled when empty.")
+	flag.DurationVar(&authzCacheTTL, "authz-cache-ttl", 10*time.Second, "How long permission check decisions are cached.")
+	flag.StringVar(&authzSubjectHeader, "authz-subject-header", authz.DefaultSubjectHeader,
+		"The header holding the user a permission check is for.")
+	flag.StringVar(&authzTenantHeader, "authz-tenant-header", authz.DefaultTenantHeader,
+		"The header holding the tenant, or |-separated tenants, a permission check is for.")
+	flag.StringVar(&authzActionHeader, "authz-action-header", authz.DefaultActionHeader,
+		"The header holding the action, read or write, a permission check is for.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", os.Getenv("ENABLE_WEBHOOKS") == "true",
+		"Serve the admission webhooks. Requires a serving certificate in the webhook server certificate directory.")
+	flag.BoolVar(&printKetoOPL, "print-keto-opl", false, "Print the Keto namespace configuration the controller relies on and exit.")
 	opts := zap.Options{}
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
+	if printKetoOPL {
+		fmt.Print(keto.OPL)
+		return
+	}
+
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
@@ -89,23 +135,115 @@ func main() {
 		os.Exit(1)
 	}
 
-	conndetails := keto.NewKetoConnectionDetailsFromEnv()
-	ketoClient, err := keto.NewKetoGrpcClient(context.Background(), conndetails)
-	if err != nil {
-		setupLog.Error(err, "Failed to setup Keto gRPC client")
+	var authorizer keto.TenantAuthorizer
+	switch ketoAPI {
+	case "grpc":
+		conndetails := keto.NewKetoConnectionDetailsFromEnv()
+		conndetails.SetTLSOptions(ketoTLS)
+		if ketoTokenFile != "" {
+			conndetails.SetTokenFile(ketoTokenFile)
+		}
+		ketoClient, err := keto.NewKetoGrpcClient(context.Background(), conndetails)
+		if err != nil {
+			setupLog.Error(err, "Failed to setup Keto gRPC client")
+			os.Exit(1)
+		}
+		authorizer = ketoClient
+	case "http":
+		ketoClient, err := keto.NewKetoHttpClientFromEnv()
+		if err == nil {
+			err = ketoClient.SetTLSOptions(ketoTLS)
+		}
+		if err != nil {
+			setupLog.Error(err, "Failed to setup Keto HTTP client")
+			os.Exit(1)
+		}
+		if ketoTokenFile != "" {
+			if err := ketoClient.SetTokenFile(ketoTokenFile); err != nil {
+				setupLog.Error(err, "Failed to setup Keto HTTP client")
+				os.Exit(1)
+			}
+		}
+		authorizer = ketoClient
+	case "none":
+		setupLog.Info("Keto integration is disabled, tenant permissions will not be managed")
+		authorizer = keto.NoopAuthorizer{}
+	default:
+		setupLog.Error(nil, "unknown Keto API", "keto-api", ketoAPI)
 		os.Exit(1)
 	}
 
+	if verifier, ok := authorizer.(keto.NamespaceVerifier); ok {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		// A Keto the Config disables is never written to, so its model does
+		// not need to match.
+		enabled, err := observabilitycontroller.KetoEnabled(ctx, mgr.GetAPIReader())
+		if err != nil {
+			setupLog.Error(err, "Unable to read the Config, verifying the Keto namespace model")
+		}
+		if err != nil || enabled {
+			err = verifier.VerifyNamespaces(ctx)
+		} else {
+			setupLog.Info("Keto is disabled in the Config, skipping verification of the Keto namespace model")
+		}
+		cancel()
+		var mismatch *keto.ModelMismatchError
+		switch {
+		case errors.As(err, &mismatch):
+			setupLog.Error(err, "Keto namespace model is incompatible")
+			os.Exit(1)
+		case err != nil:
+			setupLog.Error(err, "Unable to verify Keto namespace model, continuing without verification")
+		}
+	}
+
 	if err = (&observabilitycontroller.TenantReconciler{
 		Client:     mgr.GetClient(),
-		KetoClient: ketoClient,
+		Authorizer: authorizer,
 		Scheme:     mgr.GetScheme(),
+		Recorder:   mgr.GetEventRecorderFor("tenant-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Tenant")
 		os.Exit(1)
 	}
+	if err = (&observabilitycontroller.TenantRuleGroupReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TenantRuleGroup")
+		os.Exit(1)
+	}
+	if err = (&observabilitycontroller.TenantAlertmanagerConfigReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TenantAlertmanagerConfig")
+		os.Exit(1)
+	}
+	if enableWebhooks {
+		if err = (&observabilitywebhook.TenantValidator{Client: mgr.GetClient()}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Tenant")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
+	if authzAddr != "" {
+		checker, ok := authorizer.(keto.PermissionChecker)
+		if !ok {
+			setupLog.Error(nil, "the permission check endpoint requires Keto", "keto-api", ketoAPI)
+			os.Exit(1)
+		}
+		handler := authz.NewHandler(checker, authzCacheTTL, ctrl.Log.WithName("authz"))
+		handler.SubjectHeader = authzSubjectHeader
+		handler.TenantHeader = authzTenantHeader
+		handler.ActionHeader = authzActionHeader
+		if err := mgr.Add(&authz.Server{Addr: authzAddr, Handler: handler}); err != nil {
+			setupLog.Error(err, "unable to set up permission check endpoint")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -114,6 +252,12 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if checker, ok := authorizer.(keto.ReadinessChecker); ok {
+		if err := mgr.AddReadyzCheck("keto", observabilitycontroller.KetoReadyzCheck(mgr.GetAPIReader(), checker)); err != nil {
+			setupLog.Error(err, "unable to set up keto ready check")
+			os.Exit(1)
+		}
+	}
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
@@ -35,6 +35,14 @@ func (c *FakeObservabilityV1alpha1) Tenants() v1alpha1.TenantInterface {
 	return &FakeTenants{c}
 }
 
+func (c *FakeObservabilityV1alpha1) TenantRuleGroups(namespace string) v1alpha1.TenantRuleGroupInterface {
+	return &FakeTenantRuleGroups{c, namespace}
+}
+
+func (c *FakeObservabilityV1alpha1) TenantAlertmanagerConfigs(namespace string) v1alpha1.TenantAlertmanagerConfigInterface {
+	return &FakeTenantAlertmanagerConfigs{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeObservabilityV1alpha1) RESTClient() rest.Interface {
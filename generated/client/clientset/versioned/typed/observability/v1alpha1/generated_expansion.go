@@ -20,3 +20,7 @@ package v1alpha1
 type ConfigExpansion interface{}
 
 type TenantExpansion interface{}
+
+type TenantRuleGroupExpansion interface{}
+
+type TenantAlertmanagerConfigExpansion interface{}
@@ -29,6 +29,8 @@ type ObservabilityV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	ConfigsGetter
 	TenantsGetter
+	TenantRuleGroupsGetter
+	TenantAlertmanagerConfigsGetter
 }
 
 // ObservabilityV1alpha1Client is used to interact with features provided by the observability.traceshield.io group.
@@ -44,6 +46,14 @@ func (c *ObservabilityV1alpha1Client) Tenants() TenantInterface {
 	return newTenants(c)
 }
 
+func (c *ObservabilityV1alpha1Client) TenantRuleGroups(namespace string) TenantRuleGroupInterface {
+	return newTenantRuleGroups(c, namespace)
+}
+
+func (c *ObservabilityV1alpha1Client) TenantAlertmanagerConfigs(namespace string) TenantAlertmanagerConfigInterface {
+	return newTenantAlertmanagerConfigs(c, namespace)
+}
+
 // NewForConfig creates a new ObservabilityV1alpha1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
@@ -54,6 +54,10 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=observability.traceshield.io, Version=v1alpha1
 	case v1alpha1.SchemeGroupVersion.WithResource("configs"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Observability().V1alpha1().Configs().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("tenantalertmanagerconfigs"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Observability().V1alpha1().TenantAlertmanagerConfigs().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("tenantrulegroups"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Observability().V1alpha1().TenantRuleGroups().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("tenants"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Observability().V1alpha1().Tenants().Informer()}, nil
 
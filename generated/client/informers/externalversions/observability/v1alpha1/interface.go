@@ -27,6 +27,10 @@ type Interface interface {
 	Configs() ConfigInformer
 	// Tenants returns a TenantInformer.
 	Tenants() TenantInformer
+	// TenantRuleGroups returns a TenantRuleGroupInformer.
+	TenantRuleGroups() TenantRuleGroupInformer
+	// TenantAlertmanagerConfigs returns a TenantAlertmanagerConfigInformer.
+	TenantAlertmanagerConfigs() TenantAlertmanagerConfigInformer
 }
 
 type version struct {
@@ -49,3 +53,13 @@ func (v *version) Configs() ConfigInformer {
 func (v *version) Tenants() TenantInformer {
 	return &tenantInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
+
+// TenantRuleGroups returns a TenantRuleGroupInformer.
+func (v *version) TenantRuleGroups() TenantRuleGroupInformer {
+	return &tenantRuleGroupInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// TenantAlertmanagerConfigs returns a TenantAlertmanagerConfigInformer.
+func (v *version) TenantAlertmanagerConfigs() TenantAlertmanagerConfigInformer {
+	return &tenantAlertmanagerConfigInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -24,3 +24,19 @@ type ConfigListerExpansion interface{}
 // TenantListerExpansion allows custom methods to be added to
 // TenantLister.
 type TenantListerExpansion interface{}
+
+// TenantRuleGroupListerExpansion allows custom methods to be added to
+// TenantRuleGroupLister.
+type TenantRuleGroupListerExpansion interface{}
+
+// TenantRuleGroupNamespaceListerExpansion allows custom methods to be added to
+// TenantRuleGroupNamespaceLister.
+type TenantRuleGroupNamespaceListerExpansion interface{}
+
+// TenantAlertmanagerConfigListerExpansion allows custom methods to be added to
+// TenantAlertmanagerConfigLister.
+type TenantAlertmanagerConfigListerExpansion interface{}
+
+// TenantAlertmanagerConfigNamespaceListerExpansion allows custom methods to be added to
+// TenantAlertmanagerConfigNamespaceLister.
+type TenantAlertmanagerConfigNamespaceListerExpansion interface{}
@@ -0,0 +1,321 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+// backend describes how the limits of a backend are generated from its
+// schema.
+type backend struct {
+	// name is the name of the backend, which is also the name of its schema
+	// file.
+	name string
+	// title is how the backend is named in comments.
+	title string
+	// typeName is the name of the generated limits type.
+	typeName string
+	// fields are the YAML names of the limits exposed in the Tenant API, in
+	// the order they are generated. Limits of the schema that are not listed
+	// are left out, so that updating a schema does not change the API.
+	fields []string
+	// names are the Go field names of limits that do not follow from their
+	// YAML names, kept so that the Go API does not change.
+	names map[string]string
+	// jsonNames are the JSON names of limits that differ from their YAML
+	// names, kept so that the Tenant API does not change.
+	jsonNames map[string]string
+	// types are the Go types of limits whose schema type is not a plain
+	// scalar, list or map, or that need a wider integer type.
+	types map[string]limitType
+}
+
+// limitType is the Go type of a limit along with the schema type it was
+// written for, so that a limit whose type changes upstream is not generated
+// with a Go type that no longer fits it.
+type limitType struct {
+	schemaType string
+	goType     string
+}
+
+var backends = []backend{
+	{
+		name:     "mimir",
+		title:    "Mimir",
+		typeName: "MimirLimits",
+		fields: []string{
+			"request_rate",
+			"request_burst_size",
+			"ingestion_rate",
+			"ingestion_burst_size",
+			"accept_ha_samples",
+			"ha_cluster_label",
+			"ha_replica_label",
+			"ha_max_clusters",
+			"drop_labels",
+			"max_label_name_length",
+			"max_label_value_length",
+			"max_label_names_per_series",
+			"max_metadata_length",
+			"max_native_histogram_buckets",
+			"creation_grace_period",
+			"enforce_metadata_metric_name",
+			"ingestion_tenant_shard_size",
+			"metric_relabel_configs",
+			"max_global_series_per_user",
+			"max_global_series_per_metric",
+			"max_global_metadata_per_user",
+			"max_global_metadata_per_metric",
+			"max_global_exemplars_per_user",
+			"native_histograms_ingestion_enabled",
+			"active_series_custom_trackers",
+			"out_of_order_time_window",
+			"out_of_order_blocks_external_label_enabled",
+			"separate_metrics_group_label",
+			"max_fetched_chunks_per_query",
+			"max_fetched_series_per_query",
+			"max_fetched_chunk_bytes_per_query",
+			"max_query_lookback",
+			"max_partial_query_length",
+			"max_query_parallelism",
+			"max_labels_query_length",
+			"max_cache_freshness",
+			"max_queriers_per_tenant",
+			"query_sharding_total_shards",
+			"query_sharding_max_sharded_queries",
+			"query_sharding_max_regexp_size_bytes",
+			"split_instant_queries_by_interval",
+			"query_ingesters_within",
+			"max_total_query_length",
+			"results_cache_ttl",
+			"results_cache_ttl_for_out_of_order_time_window",
+			"results_cache_ttl_for_cardinality_query",
+			"results_cache_ttl_for_labels_query",
+			"cache_unaligned_requests",
+			"max_query_expression_size_bytes",
+			"blocked_queries",
+			"cardinality_analysis_enabled",
+			"label_names_and_values_results_max_size_bytes",
+			"label_values_max_cardinality_label_names_per_request",
+			"ruler_evaluation_delay_duration",
+			"ruler_tenant_shard_size",
+			"ruler_max_rules_per_rule_group",
+			"ruler_max_rule_groups_per_tenant",
+			"ruler_recording_rules_evaluation_enabled",
+			"ruler_alerting_rules_evaluation_enabled",
+			"ruler_sync_rules_on_changes_enabled",
+			"store_gateway_tenant_shard_size",
+			"compactor_blocks_retention_period",
+			"compactor_split_and_merge_shards",
+			"compactor_split_groups",
+			"compactor_tenant_shard_size",
+			"compactor_partial_block_deletion_delay",
+			"compactor_block_upload_enabled",
+			"compactor_block_upload_validation_enabled",
+			"compactor_block_upload_verify_chunks",
+			"compactor_block_upload_max_block_size_bytes",
+			"s3_sse_type",
+			"s3_sse_kms_key_id",
+			"s3_sse_kms_encryption_context",
+			"alertmanager_receivers_firewall_block_cidr_networks",
+			"alertmanager_receivers_firewall_block_private_addresses",
+			"alertmanager_notification_rate_limit",
+			"alertmanager_notification_rate_limit_per_integration",
+			"alertmanager_max_config_size_bytes",
+			"alertmanager_max_templates_count",
+			"alertmanager_max_template_size_bytes",
+			"alertmanager_max_dispatcher_aggregation_groups",
+			"alertmanager_max_alerts_count",
+			"alertmanager_max_alerts_size_bytes",
+		},
+		names: map[string]string{
+			"max_global_metadata_per_user":                            "MaxGlobalMetricsWithMetadataPerUser",
+			"active_series_custom_trackers":                           "ActiveSeriesCustomTrackersConfig",
+			"max_fetched_chunks_per_query":                            "MaxChunksPerQuery",
+			"cache_unaligned_requests":                                "ResultsCacheForUnalignedQueryEnabled",
+			"ruler_evaluation_delay_duration":                         "RulerEvaluationDelay",
+			"alertmanager_receivers_firewall_block_cidr_networks":     "AlertmanagerReceiversBlockCIDRNetworks",
+			"alertmanager_receivers_firewall_block_private_addresses": "AlertmanagerReceiversBlockPrivateAddresses",
+			"alertmanager_notification_rate_limit":                    "NotificationRateLimit",
+			"alertmanager_notification_rate_limit_per_integration":    "NotificationRateLimitPerIntegration",
+		},
+		types: map[string]limitType{
+			"metric_relabel_configs":                      {"list of objects", "[]RelabelConfig"},
+			"blocked_queries":                             {"list of objects", "[]MimirBlockedQuery"},
+			"compactor_block_upload_max_block_size_bytes": {"int", "*int64"},
+		},
+	},
+	{
+		name:     "loki",
+		title:    "Loki",
+		typeName: "LokiLimits",
+		fields: []string{
+			"ingestion_rate_strategy",
+			"ingestion_rate_mb",
+			"ingestion_burst_size_mb",
+			"max_label_name_length",
+			"max_label_value_length",
+			"max_label_names_per_series",
+			"reject_old_samples",
+			"reject_old_samples_max_age",
+			"creation_grace_period",
+			"enforce_metric_name",
+			"max_line_size",
+			"max_line_size_truncate",
+			"increment_duplicate_timestamp",
+			"max_streams_per_user",
+			"max_global_streams_per_user",
+			"unordered_writes",
+			"per_stream_rate_limit",
+			"per_stream_rate_limit_burst",
+			"max_chunks_per_query",
+			"max_query_series",
+			"max_query_lookback",
+			"max_query_length",
+			"max_query_range",
+			"max_query_parallelism",
+			"tsdb_max_query_parallelism",
+			"tsdb_max_bytes_per_shard",
+			"cardinality_limit",
+			"max_streams_matchers_per_query",
+			"max_concurrent_tail_requests",
+			"max_entries_limit_per_query",
+			"max_cache_freshness_per_query",
+			"max_stats_cache_freshness",
+			"max_queriers_per_tenant",
+			"query_ready_index_num_days",
+			"query_timeout",
+			"split_queries_by_interval",
+			"min_sharding_lookback",
+			"max_query_bytes_read",
+			"max_querier_bytes_read",
+			"volume_enabled",
+			"volume_max_series",
+			"ruler_evaluation_delay_duration",
+			"ruler_max_rules_per_rule_group",
+			"ruler_max_rule_groups_per_tenant",
+			"ruler_alertmanager_config",
+			"ruler_tenant_shard_size",
+			"ruler_remote_write_disabled",
+			"ruler_remote_write_config",
+			"ruler_remote_evaluation_timeout",
+			"ruler_remote_evaluation_max_response_size",
+			"deletion_mode",
+			"retention_period",
+			"retention_stream",
+			"shard_streams",
+			"blocked_queries",
+			"required_labels",
+			"minimum_labels_number",
+			"index_gateway_shard_size",
+		},
+		names: map[string]string{
+			"max_streams_per_user":            "MaxLocalStreamsPerUser",
+			"max_cache_freshness_per_query":   "MaxCacheFreshness",
+			"split_queries_by_interval":       "QuerySplitDuration",
+			"ruler_evaluation_delay_duration": "RulerEvaluationDelay",
+			"ruler_alertmanager_config":       "RulerAlertManagerConfig",
+			"retention_stream":                "StreamRetention",
+			"minimum_labels_number":           "RequiredNumberLabels",
+		},
+		types: map[string]limitType{
+			"max_line_size":                             {"int", "*uint64"},
+			"per_stream_rate_limit":                     {"int", "*uint64"},
+			"per_stream_rate_limit_burst":               {"int", "*uint64"},
+			"tsdb_max_bytes_per_shard":                  {"int", "*uint64"},
+			"max_query_bytes_read":                      {"int", "*uint64"},
+			"max_querier_bytes_read":                    {"int", "*uint64"},
+			"ruler_alertmanager_config":                 {"object", "*RulerAlertManagerConfig"},
+			"ruler_remote_write_config":                 {"map of string to objects", "map[string]RemoteWriteSpec"},
+			"ruler_remote_evaluation_max_response_size": {"int", "*int64"},
+			"retention_stream":                          {"list of objects", "[]StreamRetention"},
+			"shard_streams":                             {"object", "*ShardstreamsConfig"},
+			"blocked_queries":                           {"list of objects", "[]BlockedQuery"},
+		},
+	},
+	{
+		name:     "tempo",
+		title:    "Tempo",
+		typeName: "TempoLimits",
+		fields: []string{
+			"ingestion_rate_strategy",
+			"ingestion_rate_limit_bytes",
+			"ingestion_burst_size_bytes",
+			"max_traces_per_user",
+			"max_global_traces_per_user",
+			"forwarders",
+			"metrics_generator_ring_size",
+			"metrics_generator_processors",
+			"metrics_generator_max_active_series",
+			"metrics_generator_collection_interval",
+			"metrics_generator_disable_collection",
+			"metrics_generator_forwarder_queue_size",
+			"metrics_generator_forwarder_workers",
+			"metrics_generator_processor_service_graphs_histogram_buckets",
+			"metrics_generator_processor_service_graphs_dimensions",
+			"metrics_generator_processor_service_graphs_peer_attributes",
+			"metrics_generator_processor_service_graphs_enable_client_server_prefix",
+			"metrics_generator_processor_span_metrics_histogram_buckets",
+			"metrics_generator_processor_span_metrics_dimensions",
+			"metrics_generator_processor_span_metrics_intrinsic_dimensions",
+			"metrics_generator_processor_span_metrics_filter_policies",
+			"metrics_generator_processor_span_metrics_dimension_mappings",
+			"metrics_generator_processor_span_metrics_enable_target_info",
+			"metrics_generator_processor_local_blocks_max_live_traces",
+			"metrics_generator_processor_local_blocks_max_block_duration",
+			"metrics_generator_processor_local_blocks_max_block_bytes",
+			"metrics_generator_processor_local_blocks_flush_check_period",
+			"metrics_generator_processor_local_blocks_trace_idle_period",
+			"metrics_generator_processor_local_blocks_complete_block_timeout",
+			"block_retention",
+			"max_bytes_per_tag_values_query",
+			"max_blocks_per_tag_values_query",
+			"max_search_duration",
+			"max_bytes_per_trace",
+		},
+		names: map[string]string{
+			"max_traces_per_user": "MaxLocalTracesPerUser",
+		},
+		jsonNames: map[string]string{
+			"metrics_generator_processor_span_metrics_dimension_mappings": "metrics_generator_processor_span_metrics_dimension_mapings",
+		},
+		types: map[string]limitType{
+			"metrics_generator_max_active_series":                         {"int", "*uint32"},
+			"metrics_generator_processor_span_metrics_filter_policies":    {"list of objects", "[]FilterPolicy"},
+			"metrics_generator_processor_span_metrics_dimension_mappings": {"list of objects", "[]DimensionMappings"},
+			"metrics_generator_processor_local_blocks_max_live_traces":    {"int", "*uint64"},
+			"metrics_generator_processor_local_blocks_max_block_bytes":    {"int", "*uint64"},
+		},
+	},
+}
+
+// fieldTypes are the Go types of the schema types of limits.
+var fieldTypes = map[string]string{
+	"int":                      "*int",
+	"float":                    "*float64",
+	"boolean":                  "*bool",
+	"string":                   "*string",
+	"duration":                 "*metav1.Duration",
+	"list of strings":          "[]string",
+	"list of floats":           "[]float64",
+	"map of string to string":  "map[string]string",
+	"map of string to float64": "map[string]float64",
+	"map of string to bool":    "map[string]bool",
+}
+
+// initialisms are written in upper case in Go field names.
+var initialisms = map[string]bool{
+	"api": true, "cidr": true, "ha": true, "http": true, "id": true, "kms": true,
+	"mb": true, "s3": true, "sse": true, "tsdb": true, "ttl": true, "url": true,
+}
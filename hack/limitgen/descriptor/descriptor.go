@@ -0,0 +1,196 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package descriptor describes the Limits types of Mimir, Loki and Tempo in
+// the format of the config-descriptor.json published by Mimir, which is the
+// format of the schemas limitgen generates the limits types from.
+package descriptor
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Entry is an entry of a configuration descriptor.
+type Entry struct {
+	Kind              string      `json:"kind"`
+	Name              string      `json:"name"`
+	Required          bool        `json:"required"`
+	Desc              string      `json:"desc"`
+	BlockEntries      []*Entry    `json:"blockEntries,omitempty"`
+	FieldValue        interface{} `json:"fieldValue"`
+	FieldDefaultValue interface{} `json:"fieldDefaultValue"`
+	FieldType         string      `json:"fieldType,omitempty"`
+	FieldCategory     string      `json:"fieldCategory,omitempty"`
+}
+
+// LimitsBlock is the name of the block holding the per-tenant limits.
+const LimitsBlock = "limits"
+
+// Limits is the Limits type of a backend, which registers the flags of its
+// limits.
+type Limits interface {
+	RegisterFlags(f *flag.FlagSet)
+}
+
+// namedTypes are the schema types of types that are written in YAML in
+// another form than their kind suggests, keyed by their Go name.
+var namedTypes = map[string]string{
+	"Duration":             "duration",
+	"CIDRSliceCSV":         "list of strings",
+	"StringSliceCSV":       "list of strings",
+	"CustomTrackersConfig": "map of string to string",
+	"Secret":               "string",
+}
+
+// Describe returns the descriptor of limits, a pointer to the Limits type of
+// a backend, with the limits in a block named LimitsBlock. The limits are
+// described by the usage of their flags.
+func Describe(limits Limits) (*Entry, error) {
+	v := reflect.ValueOf(limits)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("limits must be a pointer to a struct, not %T", limits)
+	}
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	limits.RegisterFlags(fs)
+	usage := map[uintptr]string{}
+	fs.VisitAll(func(f *flag.Flag) {
+		if fv := reflect.ValueOf(f.Value); fv.Kind() == reflect.Pointer {
+			usage[fv.Pointer()] = f.Usage
+		}
+	})
+
+	block := &Entry{Kind: "block", Name: LimitsBlock}
+	if err := describeFields(block, v.Elem(), usage); err != nil {
+		return nil, err
+	}
+	return &Entry{Kind: "block", BlockEntries: []*Entry{block}}, nil
+}
+
+func describeFields(block *Entry, v reflect.Value, usage map[uintptr]string) error {
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if opts == "inline" {
+			if err := describeFields(block, v.Field(i), usage); err != nil {
+				return err
+			}
+			continue
+		}
+		if name == "" {
+			// The YAML decoders of the backends default to the lowercased
+			// field name.
+			name = strings.ToLower(f.Name)
+		}
+		block.BlockEntries = append(block.BlockEntries, &Entry{
+			Kind:          "field",
+			Name:          name,
+			Desc:          usage[v.Field(i).Addr().Pointer()],
+			FieldType:     fieldType(f.Type),
+			FieldCategory: f.Tag.Get("category"),
+		})
+	}
+	return nil
+}
+
+// fieldType returns the schema type of a Go type.
+func fieldType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if s, ok := namedTypes[t.Name()]; ok {
+		return s
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "int"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		if s := fieldType(t.Elem()); isScalar(s) {
+			return "list of " + s + "s"
+		}
+		return "list of objects"
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return "map"
+		}
+		switch elem := t.Elem(); elem.Kind() {
+		case reflect.String, reflect.Bool:
+			return "map of string to " + elem.Kind().String()
+		case reflect.Float64:
+			return "map of string to float64"
+		}
+		return "map of string to objects"
+	}
+	return "object"
+}
+
+func isScalar(s string) bool {
+	return s == "boolean" || s == "int" || s == "float" || s == "string" || s == "duration"
+}
+
+// Diff returns the differences between the limits of the descriptors old and
+// new, e.g. limits that were removed or whose type changed, sorted by limit.
+func Diff(old, new *Entry) []string {
+	oldLimits, newLimits := limitsOf(old), limitsOf(new)
+	var out []string
+	for name, o := range oldLimits {
+		n, ok := newLimits[name]
+		switch {
+		case !ok:
+			out = append(out, fmt.Sprintf("%s: removed", name))
+		case o.FieldType != n.FieldType:
+			out = append(out, fmt.Sprintf("%s: type changed from %q to %q", name, o.FieldType, n.FieldType))
+		case o.FieldCategory != n.FieldCategory:
+			out = append(out, fmt.Sprintf("%s: category changed from %q to %q", name, o.FieldCategory, n.FieldCategory))
+		case o.Desc != n.Desc:
+			out = append(out, fmt.Sprintf("%s: description changed", name))
+		}
+	}
+	for name := range newLimits {
+		if _, ok := oldLimits[name]; !ok {
+			out = append(out, fmt.Sprintf("%s: added", name))
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func limitsOf(root *Entry) map[string]*Entry {
+	out := map[string]*Entry{}
+	for _, block := range root.BlockEntries {
+		if block.Kind != "block" || block.Name != LimitsBlock {
+			continue
+		}
+		for _, e := range block.BlockEntries {
+			out[e.Name] = e
+		}
+	}
+	return out
+}
@@ -0,0 +1,118 @@
+package descriptor_test
+
+import (
+	"flag"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/traceshield/trace-shield-controller/hack/limitgen/descriptor"
+)
+
+// Duration stands in for the model.Duration of Prometheus.
+type Duration time.Duration
+
+type relabelConfig struct {
+	Regex string `yaml:"regex"`
+}
+
+type shardStreams struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+type ingesterLimits struct {
+	MaxGlobalSeriesPerUser int `yaml:"max_global_series_per_user"`
+}
+
+type limits struct {
+	IngestionRate        float64                 `yaml:"ingestion_rate"`
+	MaxLineSize          uint64                  `yaml:"max_line_size"`
+	MaxQueryLookback     Duration                `yaml:"max_query_lookback"`
+	DropLabels           []string                `yaml:"drop_labels" category:"advanced"`
+	HistogramBuckets     []float64               `yaml:"histogram_buckets"`
+	MetricRelabelConfigs []*relabelConfig        `yaml:"metric_relabel_configs,omitempty"`
+	ShardStreams         *shardStreams           `yaml:"shard_streams"`
+	RemoteWrite          map[string]shardStreams `yaml:"ruler_remote_write_config"`
+	Dimensions           map[string]bool         `yaml:"intrinsic_dimensions"`
+	Ingester             ingesterLimits          `yaml:",inline"`
+	Internal             int                     `yaml:"-"`
+	Deprecated           bool
+}
+
+func (l *limits) RegisterFlags(f *flag.FlagSet) {
+	f.Float64Var(&l.IngestionRate, "distributor.ingestion-rate-limit", 10000, "Per-tenant ingestion rate limit in samples per second.")
+	f.IntVar(&l.Ingester.MaxGlobalSeriesPerUser, "ingester.max-global-series-per-user", 150000, "The maximum number of in-memory series per tenant.")
+}
+
+type flagSetter func(*flag.FlagSet)
+
+func (f flagSetter) RegisterFlags(fs *flag.FlagSet) { f(fs) }
+
+var _ = Describe("Describe", func() {
+	It("describes every limit", func() {
+		root, err := descriptor.Describe(&limits{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(root.BlockEntries).To(HaveLen(1))
+		block := root.BlockEntries[0]
+		Expect(block.Name).To(Equal(descriptor.LimitsBlock))
+
+		type field struct{ Name, FieldType, FieldCategory, Desc string }
+		var fields []field
+		for _, e := range block.BlockEntries {
+			Expect(e.Kind).To(Equal("field"))
+			fields = append(fields, field{e.Name, e.FieldType, e.FieldCategory, e.Desc})
+		}
+		Expect(fields).To(Equal([]field{
+			{"ingestion_rate", "float", "", "Per-tenant ingestion rate limit in samples per second."},
+			{"max_line_size", "int", "", ""},
+			{"max_query_lookback", "duration", "", ""},
+			{"drop_labels", "list of strings", "advanced", ""},
+			{"histogram_buckets", "list of floats", "", ""},
+			{"metric_relabel_configs", "list of objects", "", ""},
+			{"shard_streams", "object", "", ""},
+			{"ruler_remote_write_config", "map of string to objects", "", ""},
+			{"intrinsic_dimensions", "map of string to bool", "", ""},
+			{"max_global_series_per_user", "int", "", "The maximum number of in-memory series per tenant."},
+			{"deprecated", "boolean", "", ""},
+		}))
+	})
+
+	It("rejects limits that are not a pointer to a struct", func() {
+		_, err := descriptor.Describe(flagSetter(func(*flag.FlagSet) {}))
+		Expect(err).To(MatchError(ContainSubstring("limits must be a pointer to a struct")))
+	})
+})
+
+var _ = Describe("Diff", func() {
+	schema := func(fields ...*descriptor.Entry) *descriptor.Entry {
+		return &descriptor.Entry{Kind: "block", BlockEntries: []*descriptor.Entry{
+			{Kind: "block", Name: descriptor.LimitsBlock, BlockEntries: fields},
+		}}
+	}
+	field := func(name, fieldType string) *descriptor.Entry {
+		return &descriptor.Entry{Kind: "field", Name: name, FieldType: fieldType}
+	}
+
+	It("reports no differences between equal schemas", func() {
+		Expect(descriptor.Diff(schema(field("ingestion_rate", "float")), schema(field("ingestion_rate", "float")))).To(BeEmpty())
+	})
+
+	It("reports limits that were renamed, retyped or removed", func() {
+		Expect(descriptor.Diff(
+			schema(field("ingestion_rate", "float"), field("max_line_size", "int"), field("old_limit", "int"), field("max_query_lookback", "duration")),
+			schema(field("ingestion_rate", "int"), field("max_line_size_bytes", "int"), field("max_query_lookback", "duration")),
+		)).To(Equal([]string{
+			`ingestion_rate: type changed from "float" to "int"`,
+			"max_line_size: removed",
+			"max_line_size_bytes: added",
+			"old_limit: removed",
+		}))
+	})
+
+	It("reports limits whose description changed", func() {
+		changed := field("ingestion_rate", "float")
+		changed.Desc = "Per-tenant ingestion rate limit in samples per second."
+		Expect(descriptor.Diff(schema(field("ingestion_rate", "float")), schema(changed))).To(Equal([]string{"ingestion_rate: description changed"}))
+	})
+})
@@ -0,0 +1,80 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package descriptor
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// Main is the main function of the commands in hack/limitgen/upstream, which
+// describe the Limits type of the backend version they pin. It writes the
+// descriptor of limits to the schema given by the -schema flag or, with
+// -check, fails if the schema differs from it.
+func Main(backend string, limits Limits) {
+	schema := flag.String("schema", "", "The schema to write the descriptor of the limits to.")
+	check := flag.Bool("check", false, "Fail if the schema differs from the limits instead of writing it.")
+	flag.Parse()
+
+	root, err := Describe(limits)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to describe %s limits: %v\n", backend, err)
+		os.Exit(1)
+	}
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode %s schema: %v\n", backend, err)
+		os.Exit(1)
+	}
+
+	if !*check {
+		if err := os.WriteFile(*schema, data, 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write %s schema: %v\n", backend, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	diff, err := Check(*schema, root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to check %s schema: %v\n", backend, err)
+		os.Exit(1)
+	}
+	if len(diff) > 0 {
+		fmt.Fprintf(os.Stderr, "the %s schema is out of date, run make update-limit-schemas:\n", backend)
+		for _, d := range diff {
+			fmt.Fprintf(os.Stderr, "  %s\n", d)
+		}
+		os.Exit(1)
+	}
+}
+
+// Check returns the differences between the limits of the schema in the file
+// path and those of root.
+func Check(path string, root *Entry) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	committed := &Entry{}
+	if err := json.Unmarshal(data, committed); err != nil {
+		return nil, err
+	}
+	return Diff(committed, root), nil
+}
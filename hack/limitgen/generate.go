@@ -0,0 +1,183 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"strings"
+
+	"github.com/traceshield/trace-shield-controller/hack/limitgen/descriptor"
+)
+
+const durationMarkers = `// +kubebuilder:validation:Type=string
+// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+`
+
+const extraField = `
+// Extra holds %s limits that are not modeled yet. They are merged verbatim
+// into the rendered overrides, where modeled fields take precedence over
+// keys of the same name. A tenant whose limits set Extra owns every field of
+// its overrides, so fields it neither models nor holds in Extra are removed.
+// +kubebuilder:pruning:PreserveUnknownFields
+// +kubebuilder:validation:Schemaless
+// +kubebuilder:validation:Type=object
+// +kubebuilder:validation:Optional
+Extra *WrappedMap ` + "`" + `yaml:"extra,omitempty" json:"extra,omitempty"` + "`" + `
+`
+
+// generate returns the Go source of the limits types of the backends, whose
+// schemas are read with schema, and the limits that were skipped because
+// their type is not supported.
+func generate(header []byte, backends []backend, schema func(name string) ([]byte, error)) ([]byte, []string, error) {
+	var buf bytes.Buffer
+	buf.Write(header)
+	buf.WriteString("\n\n// Code generated by limitgen. DO NOT EDIT.\n\npackage v1alpha1\n\n")
+	buf.WriteString("import metav1 \"k8s.io/apimachinery/pkg/apis/meta/v1\"\n")
+
+	var skipped []string
+	for _, b := range backends {
+		data, err := schema(b.name)
+		if err != nil {
+			return nil, nil, err
+		}
+		root := &descriptor.Entry{}
+		if err := json.Unmarshal(data, root); err != nil {
+			return nil, nil, fmt.Errorf("unable to parse %s schema: %w", b.name, err)
+		}
+		limits := findBlock(root, descriptor.LimitsBlock)
+		if limits == nil {
+			return nil, nil, fmt.Errorf("%s schema has no %s block", b.name, descriptor.LimitsBlock)
+		}
+
+		entries := map[string]*descriptor.Entry{}
+		for _, e := range limits.BlockEntries {
+			entries[e.Name] = e
+		}
+
+		fmt.Fprintf(&buf, "\n// %s are the per-tenant limits of %s.\ntype %s struct {\n", b.typeName, b.title, b.typeName)
+		for _, name := range b.fields {
+			f, ok := entries[name]
+			if !ok {
+				return nil, nil, fmt.Errorf("%s schema has no limit %s", b.name, name)
+			}
+			if f.Kind != "field" {
+				skipped = append(skipped, fmt.Sprintf("%s: %s: nested blocks are not supported", b.name, f.Name))
+				continue
+			}
+			goType, ok := fieldTypes[f.FieldType]
+			if t, overridden := b.types[f.Name]; overridden {
+				if f.FieldType != t.schemaType {
+					return nil, nil, fmt.Errorf("%s schema has limit %s of type %q, but its Go type %s is for %q", b.name, f.Name, f.FieldType, t.goType, t.schemaType)
+				}
+				goType, ok = t.goType, true
+			}
+			if !ok {
+				skipped = append(skipped, fmt.Sprintf("%s: %s: type %q is not supported", b.name, f.Name, f.FieldType))
+				continue
+			}
+			writeField(&buf, b, f, goType)
+		}
+		fmt.Fprintf(&buf, extraField, b.title)
+		buf.WriteString("}\n")
+	}
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to format generated code: %w", err)
+	}
+	return out, skipped, nil
+}
+
+func writeField(buf *bytes.Buffer, b backend, f *descriptor.Entry, goType string) {
+	for _, line := range wrap(f.Desc, 80) {
+		fmt.Fprintf(buf, "// %s\n", line)
+	}
+	buf.WriteString("// +kubebuilder:validation:Optional\n")
+	if goType == "*metav1.Duration" {
+		buf.WriteString(durationMarkers)
+	}
+	name := b.names[f.Name]
+	if name == "" {
+		name = goName(f.Name)
+	}
+	jsonName := b.jsonNames[f.Name]
+	if jsonName == "" {
+		jsonName = f.Name
+	}
+	tags := fmt.Sprintf(`yaml:"%s,omitempty" json:"%s,omitempty"`, f.Name, jsonName)
+	if f.FieldCategory != "" {
+		tags += fmt.Sprintf(` category:"%s"`, f.FieldCategory)
+	}
+	fmt.Fprintf(buf, "%s %s `%s`\n", name, goType, tags)
+}
+
+// findBlock returns the first block with the given name below d.
+func findBlock(d *descriptor.Entry, name string) *descriptor.Entry {
+	for _, e := range d.BlockEntries {
+		if e.Kind != "block" {
+			continue
+		}
+		if e.Name == name {
+			return e
+		}
+		if found := findBlock(e, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// goName returns the Go field name of a YAML name, e.g. HAClusterLabel for
+// ha_cluster_label.
+func goName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if initialisms[part] {
+			b.WriteString(strings.ToUpper(part))
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// wrap splits s into lines of at most width characters, keeping the line
+// breaks and the indentation it already has, e.g. of YAML examples.
+func wrap(s string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(strings.TrimSpace(s), "\n") {
+		indent := paragraph[:len(paragraph)-len(strings.TrimLeft(paragraph, " "))]
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			if line != "" && len(indent)+len(line)+1+len(word) > width {
+				lines = append(lines, indent+line)
+				line = ""
+			}
+			if line != "" {
+				line += " "
+			}
+			line += word
+		}
+		if line != "" {
+			lines = append(lines, indent+line)
+		}
+	}
+	return lines
+}
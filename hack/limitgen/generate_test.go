@@ -0,0 +1,101 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	header, err := os.ReadFile("../boilerplate.go.txt")
+	if err != nil {
+		panic(err)
+	}
+	schemas := func(name string) ([]byte, error) {
+		return os.ReadFile(filepath.Join("schemas", name+".json"))
+	}
+
+	It("matches the committed limits types", func() {
+		code, _, err := generate(header, backends, schemas)
+		Expect(err).NotTo(HaveOccurred())
+
+		committed, err := os.ReadFile("../../api/observability/v1alpha1/zz_generated.limits.go")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(code)).To(Equal(string(committed)), "the limits types are out of date, run make generate-limits")
+	})
+
+	It("generates every limit of the schemas", func() {
+		_, skipped, err := generate(header, backends, schemas)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(skipped).To(BeEmpty())
+	})
+
+	testBackends := []backend{{
+		name:      "mimir",
+		title:     "Mimir",
+		typeName:  "MimirLimits",
+		fields:    []string{"ingestion_rate", "new_limit", "old_limit"},
+		jsonNames: map[string]string{"old_limit": "old_limt"},
+	}}
+
+	It("skips limits of unsupported types", func() {
+		code, skipped, err := generate(header, testBackends, func(name string) ([]byte, error) {
+			return []byte(`{"kind": "block", "blockEntries": [{"kind": "block", "name": "limits", "blockEntries": [
+				{"kind": "field", "name": "ingestion_rate", "desc": "Per-tenant ingestion rate limit in samples per second.", "fieldType": "float"},
+				{"kind": "field", "name": "new_limit", "fieldType": "list of objects"},
+				{"kind": "field", "name": "old_limit", "fieldType": "int"}
+			]}]}`), nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(code)).To(ContainSubstring("// Per-tenant ingestion rate limit in samples per second.\n"))
+		Expect(string(code)).To(ContainSubstring("IngestionRate *float64 `yaml:\"ingestion_rate,omitempty\" json:\"ingestion_rate,omitempty\"`"))
+		Expect(skipped).To(ContainElement(`mimir: new_limit: type "list of objects" is not supported`))
+	})
+
+	It("generates only the listed limits under their JSON names", func() {
+		code, _, err := generate(header, testBackends, func(name string) ([]byte, error) {
+			return []byte(`{"kind": "block", "blockEntries": [{"kind": "block", "name": "limits", "blockEntries": [
+				{"kind": "field", "name": "unlisted_limit", "fieldType": "int"},
+				{"kind": "field", "name": "old_limit", "fieldType": "int"},
+				{"kind": "field", "name": "new_limit", "fieldType": "int"},
+				{"kind": "field", "name": "ingestion_rate", "fieldType": "float"}
+			]}]}`), nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(code)).NotTo(ContainSubstring("unlisted_limit"))
+		Expect(string(code)).To(ContainSubstring("OldLimit *int `yaml:\"old_limit,omitempty\" json:\"old_limt,omitempty\"`"))
+		Expect(strings.Index(string(code), "IngestionRate")).To(BeNumerically("<", strings.Index(string(code), "OldLimit")))
+	})
+
+	It("fails on listed limits missing from the schema", func() {
+		_, _, err := generate(header, testBackends, func(name string) ([]byte, error) {
+			return []byte(`{"kind": "block", "blockEntries": [{"kind": "block", "name": "limits", "blockEntries": [
+				{"kind": "field", "name": "ingestion_rate", "fieldType": "float"}
+			]}]}`), nil
+		})
+		Expect(err).To(MatchError("mimir schema has no limit new_limit"))
+	})
+
+	It("fails on listed limits whose type changed", func() {
+		typed := []backend{testBackends[0]}
+		typed[0].types = map[string]limitType{"old_limit": {"int", "*int64"}}
+		_, _, err := generate(header, typed, func(name string) ([]byte, error) {
+			return []byte(`{"kind": "block", "blockEntries": [{"kind": "block", "name": "limits", "blockEntries": [
+				{"kind": "field", "name": "ingestion_rate", "fieldType": "float"},
+				{"kind": "field", "name": "new_limit", "fieldType": "int"},
+				{"kind": "field", "name": "old_limit", "fieldType": "string"}
+			]}]}`), nil
+		})
+		Expect(err).To(MatchError(`mimir schema has limit old_limit of type "string", but its Go type *int64 is for "int"`))
+	})
+
+	It("fails on schemas without limits", func() {
+		_, _, err := generate(header, testBackends, func(name string) ([]byte, error) {
+			return []byte(`{"kind": "block", "blockEntries": []}`), nil
+		})
+		Expect(err).To(MatchError("mimir schema has no limits block"))
+	})
+})
@@ -0,0 +1,57 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// limitgen generates the MimirLimits, LokiLimits and TempoLimits types from
+// the schemas of the limits of Mimir, Loki and Tempo, which the commands in
+// hack/limitgen/upstream describe from the Limits types of the versions they
+// pin. Only the limits listed in backends are generated, so the schemas
+// document the limits and check that they still exist with the same type,
+// but do not decide which limits the Tenant API has.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	schemas := flag.String("schemas", "hack/limitgen/schemas", "The directory holding the reference configuration descriptors.")
+	header := flag.String("header", "hack/boilerplate.go.txt", "The file holding the header of the generated code.")
+	out := flag.String("out", "api/observability/v1alpha1/zz_generated.limits.go", "The file to write the generated code to.")
+	flag.Parse()
+
+	h, err := os.ReadFile(*header)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read header: %v\n", err)
+		os.Exit(1)
+	}
+	code, skipped, err := generate(h, backends, func(name string) ([]byte, error) {
+		return os.ReadFile(filepath.Join(*schemas, name+".json"))
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to generate limits: %v\n", err)
+		os.Exit(1)
+	}
+	for _, s := range skipped {
+		fmt.Fprintf(os.Stderr, "skipped %s\n", s)
+	}
+	if err := os.WriteFile(*out, code, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write limits: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/kv"
+	"github.com/grafana/loki/pkg/runtime"
+	"github.com/grafana/loki/pkg/validation"
+	"gopkg.in/yaml.v2"
+)
+
+// rendered holds the runtime configurations rendered by the controller.
+const rendered = "../../../../internal/controller/observability/testdata/runtime-config/rendered"
+
+// runtimeConfig is runtimeConfigValues of pkg/loki/runtime_config.go, which
+// is not exported.
+type runtimeConfig struct {
+	TenantLimits map[string]*validation.Limits `yaml:"overrides"`
+	TenantConfig map[string]*runtime.Config    `yaml:"configs"`
+	Multi        kv.MultiRuntimeConfig         `yaml:"multi_kv_config"`
+}
+
+// load decodes a runtime configuration like the runtime configuration loader
+// of Loki, which rejects unknown keys.
+func load(data []byte) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.SetStrict(true)
+	return decoder.Decode(&runtimeConfig{})
+}
+
+var _ = BeforeSuite(func() {
+	var defaults validation.Limits
+	flagext.DefaultValues(&defaults)
+	validation.SetDefaultLimitsForYAMLUnmarshalling(defaults)
+})
+
+var _ = Describe("Runtime configuration", func() {
+	paths, err := filepath.Glob(filepath.Join(rendered, "loki*.yaml"))
+	if err != nil {
+		panic(err)
+	}
+
+	It("has rendered runtime configurations to load", func() {
+		Expect(paths).NotTo(BeEmpty())
+	})
+
+	for _, path := range paths {
+		path := path
+		It("loads "+filepath.Base(path), func() {
+			data, err := os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(load(data)).To(Succeed())
+		})
+	}
+
+	It("rejects unknown and mistyped limits", func() {
+		Expect(load([]byte("overrides:\n  team-a:\n    max_line_size: [1]\n"))).NotTo(Succeed())
+		Expect(load([]byte("unknown: true\n"))).To(MatchError(ContainSubstring("field unknown not found")))
+	})
+})
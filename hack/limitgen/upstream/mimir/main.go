@@ -0,0 +1,29 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// mimir writes the schema of the limits of Mimir from validation.Limits of the
+// Mimir version pinned by make update-limit-schemas, mimir-2.9.0 by default.
+package main
+
+import (
+	"github.com/grafana/mimir/pkg/util/validation"
+
+	"github.com/traceshield/trace-shield-controller/hack/limitgen/descriptor"
+)
+
+func main() {
+	descriptor.Main("mimir", &validation.Limits{})
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/kv"
+	"github.com/grafana/mimir/pkg/distributor"
+	"github.com/grafana/mimir/pkg/ingester"
+	"github.com/grafana/mimir/pkg/util/validation"
+	"gopkg.in/yaml.v3"
+)
+
+// rendered holds the runtime configurations rendered by the controller.
+const rendered = "../../../../internal/controller/observability/testdata/runtime-config/rendered"
+
+// runtimeConfig is runtimeConfigValues of pkg/mimir/runtime_config.go, which
+// is not exported.
+type runtimeConfig struct {
+	TenantLimits           map[string]*validation.Limits `yaml:"overrides"`
+	Multi                  kv.MultiRuntimeConfig         `yaml:"multi_kv_config"`
+	IngesterChunkStreaming *bool                         `yaml:"ingester_stream_chunks_when_using_blocks"`
+	IngesterLimits         *ingester.InstanceLimits      `yaml:"ingester_limits"`
+	DistributorLimits      *distributor.InstanceLimits   `yaml:"distributor_limits"`
+}
+
+// load decodes a runtime configuration like the runtime configuration loader
+// of Mimir, which rejects unknown keys.
+func load(data []byte) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	return decoder.Decode(&runtimeConfig{})
+}
+
+var _ = BeforeSuite(func() {
+	var defaults validation.Limits
+	flagext.DefaultValues(&defaults)
+	validation.SetDefaultLimitsForYAMLUnmarshalling(defaults)
+})
+
+var _ = Describe("Runtime configuration", func() {
+	paths, err := filepath.Glob(filepath.Join(rendered, "mimir*.yaml"))
+	if err != nil {
+		panic(err)
+	}
+
+	It("has rendered runtime configurations to load", func() {
+		Expect(paths).NotTo(BeEmpty())
+	})
+
+	for _, path := range paths {
+		path := path
+		It("loads "+filepath.Base(path), func() {
+			data, err := os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(load(data)).To(Succeed())
+		})
+	}
+
+	It("rejects unknown and mistyped limits", func() {
+		Expect(load([]byte("overrides:\n  team-a:\n    ingestion_rates: 10\n"))).To(MatchError(ContainSubstring("field ingestion_rates not found")))
+		Expect(load([]byte("overrides:\n  team-a:\n    max_query_lookback: 1.5s\n"))).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,41 @@
+#!/usr/bin/env bash
+# Usage: pin.sh DIR MODULE TAG
+#
+# Pins the module in the directory DIR to the commit of the tag TAG of the
+# module MODULE, e.g. hack/limitgen/upstream/mimir github.com/grafana/mimir
+# mimir-2.9.0. Loki and Tempo tag major versions their module paths do not
+# have, so the tag cannot be required as a version. The replace directives
+# of MODULE are copied along, since Go only applies those of the main module
+# and MODULE may not build without them, e.g. Mimir needs its fork of
+# Prometheus.
+set -euo pipefail
+
+dir=$1
+module=$2
+tag=$3
+replaces="$(cd "$(dirname "$0")" && pwd)/replaces.awk"
+
+# The last line is the commit of an annotated tag, or the only line of a
+# lightweight one.
+commit=$(git ls-remote "https://${module}" "refs/tags/${tag}" "refs/tags/${tag}^{}" | tail -n1 | cut -f1)
+if [ -z "${commit}" ]; then
+	echo "${module} has no tag ${tag}" >&2
+	exit 1
+fi
+
+gomod=$(go mod download -json "${module}@${commit}" | awk -F'"' '$2 == "GoMod" { print $4 }')
+
+cd "${dir}"
+cat > go.mod <<EOT
+module github.com/traceshield/trace-shield-controller/hack/limitgen/upstream/$(basename "${dir}")
+
+go 1.20
+
+replace github.com/traceshield/trace-shield-controller => ../../../..
+EOT
+rm -f go.sum
+go mod edit -require="${module}@${commit}" -require=github.com/traceshield/trace-shield-controller@v0.0.0
+awk -f "${replaces}" "${gomod}" | while read -r replace; do
+	go mod edit -replace="${replace}"
+done
+go mod tidy
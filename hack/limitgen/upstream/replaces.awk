@@ -0,0 +1,19 @@
+# Prints the replace directives of a go.mod file that replace a module with
+# another module in the form of the -replace flag of go mod edit, e.g.
+# github.com/prometheus/prometheus=github.com/grafana/mimir-prometheus@v0.0.0-...
+# Directives that replace a module with a directory are left out, since the
+# directory is not part of the module cache.
+
+/^replace[ \t]*\($/ { block = 1; next }
+block && /^\)/ { block = 0; next }
+block || /^replace[ \t]/ {
+	sub(/\/\/.*/, "")
+	sub(/^replace[ \t]+/, "")
+	split($0, sides, "=>")
+	n = split(sides[1], old, " ")
+	m = split(sides[2], new, " ")
+	if (new[1] ~ /^\.\.?\//) {
+		next
+	}
+	printf "%s%s=%s%s\n", old[1], (n > 1 ? "@" old[2] : ""), new[1], (m > 1 ? "@" new[2] : "")
+}
@@ -0,0 +1,5 @@
+module github.com/traceshield/trace-shield-controller/hack/limitgen/upstream/tempo
+
+go 1.20
+
+replace github.com/traceshield/trace-shield-controller => ../../../..
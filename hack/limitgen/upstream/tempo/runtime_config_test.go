@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"gopkg.in/yaml.v2"
+)
+
+// rendered holds the runtime configurations rendered by the controller.
+const rendered = "../../../../internal/controller/observability/testdata/runtime-config/rendered"
+
+// runtimeConfig is perTenantOverrides of modules/overrides/overrides.go,
+// which is not exported.
+type runtimeConfig struct {
+	TenantLimits map[string]*overrides.Limits `yaml:"overrides"`
+}
+
+// load decodes a runtime configuration like the per-tenant overrides loader
+// of Tempo, which rejects unknown keys.
+func load(data []byte) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.SetStrict(true)
+	return decoder.Decode(&runtimeConfig{})
+}
+
+var _ = Describe("Runtime configuration", func() {
+	paths, err := filepath.Glob(filepath.Join(rendered, "tempo*.yaml"))
+	if err != nil {
+		panic(err)
+	}
+
+	It("has rendered runtime configurations to load", func() {
+		Expect(paths).NotTo(BeEmpty())
+	})
+
+	for _, path := range paths {
+		path := path
+		It("loads "+filepath.Base(path), func() {
+			data, err := os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(load(data)).To(Succeed())
+		})
+	}
+
+	It("rejects unknown and mistyped limits", func() {
+		Expect(load([]byte("overrides:\n  team-a:\n    max_traces_per_user: many\n"))).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,242 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package alerting renders the Alertmanager configurations of tenants and
+// validates them against their Alertmanager limits before they are synced.
+package alerting
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/clients/alertmanager"
+)
+
+// Render returns the configuration of a TenantAlertmanagerConfig as it is
+// uploaded to the Alertmanager.
+func Render(spec observabilityv1alpha1.TenantAlertmanagerConfigSpec) (alertmanager.UserConfig, error) {
+	data, err := yaml.Marshal(spec.Config.Object)
+	if err != nil {
+		return alertmanager.UserConfig{}, err
+	}
+	return alertmanager.UserConfig{
+		TemplateFiles:      spec.Templates,
+		AlertmanagerConfig: string(data),
+	}, nil
+}
+
+// Validate checks that the configuration has uniquely named receivers and a
+// route whose receivers, and those of its child routes, are defined.
+func Validate(config map[string]interface{}) field.ErrorList {
+	var errs field.ErrorList
+	path := field.NewPath("spec", "config")
+
+	names := map[string]bool{}
+	receivers, ok := config["receivers"].([]interface{})
+	if !ok {
+		errs = append(errs, field.Required(path.Child("receivers"), "must list the receivers"))
+	}
+	for i, r := range receivers {
+		receiverPath := path.Child("receivers").Index(i)
+		receiver, _ := r.(map[string]interface{})
+		name, _ := receiver["name"].(string)
+		switch {
+		case name == "":
+			errs = append(errs, field.Required(receiverPath.Child("name"), "must name the receiver"))
+		case names[name]:
+			errs = append(errs, field.Duplicate(receiverPath.Child("name"), name))
+		}
+		names[name] = true
+	}
+
+	route, ok := config["route"].(map[string]interface{})
+	if !ok {
+		return append(errs, field.Required(path.Child("route"), "must configure the root route"))
+	}
+	if _, ok := route["receiver"]; !ok {
+		errs = append(errs, field.Required(path.Child("route", "receiver"), "the root route must have a receiver"))
+	}
+	return append(errs, validateRoute(route, path.Child("route"), names)...)
+}
+
+func validateRoute(route map[string]interface{}, path *field.Path, receivers map[string]bool) field.ErrorList {
+	var errs field.ErrorList
+	if r, ok := route["receiver"]; ok {
+		if name, _ := r.(string); !receivers[name] {
+			errs = append(errs, field.NotFound(path.Child("receiver"), r))
+		}
+	}
+	routes, _ := route["routes"].([]interface{})
+	for i, r := range routes {
+		child, ok := r.(map[string]interface{})
+		if !ok {
+			errs = append(errs, field.Invalid(path.Child("routes").Index(i), r, "must be a route"))
+			continue
+		}
+		errs = append(errs, validateRoute(child, path.Child("routes").Index(i), receivers)...)
+	}
+	return errs
+}
+
+// ExceedsLimits returns an error if the configuration or its templates are
+// larger or more than the Alertmanager limits of the tenant allow.
+func ExceedsLimits(config alertmanager.UserConfig, limits observabilityv1alpha1.MimirLimits) error {
+	if max := limits.AlertmanagerMaxConfigSizeBytes; max != nil && *max > 0 {
+		// Mimir limits the size of the uploaded payload, which holds the
+		// templates as well as the configuration.
+		data, err := yaml.Marshal(config)
+		if err != nil {
+			return err
+		}
+		if len(data) > *max {
+			return fmt.Errorf("the configuration is %d bytes with its templates, the limit is %d", len(data), *max)
+		}
+	}
+	if max := limits.AlertmanagerMaxTemplatesCount; max != nil && *max > 0 && len(config.TemplateFiles) > *max {
+		return fmt.Errorf("the configuration has %d templates, the limit is %d", len(config.TemplateFiles), *max)
+	}
+	if max := limits.AlertmanagerMaxTemplateSizeBytes; max != nil && *max > 0 {
+		names := make([]string, 0, len(config.TemplateFiles))
+		for name := range config.TemplateFiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if size := len(config.TemplateFiles[name]); size > *max {
+				return fmt.Errorf("template %s is %d bytes, the limit is %d", name, size, *max)
+			}
+		}
+	}
+	return nil
+}
+
+// BlockedReceivers returns the receiver URLs of the configuration whose
+// host is an address in a network blocked for the tenant. Receivers using
+// hostnames are left to the firewall of the Alertmanager, which checks the
+// addresses they resolve to when notifications are sent.
+func BlockedReceivers(config map[string]interface{}, limits observabilityv1alpha1.MimirLimits) (field.ErrorList, error) {
+	var networks []netip.Prefix
+	if limits.AlertmanagerReceiversBlockCIDRNetworks != nil {
+		for _, s := range strings.Split(*limits.AlertmanagerReceiversBlockCIDRNetworks, ",") {
+			if s = strings.TrimSpace(s); s == "" {
+				continue
+			}
+			network, err := netip.ParsePrefix(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid blocked network %q: %w", s, err)
+			}
+			networks = append(networks, network)
+		}
+	}
+	blockPrivate := limits.AlertmanagerReceiversBlockPrivateAddresses != nil && *limits.AlertmanagerReceiversBlockPrivateAddresses
+	if len(networks) == 0 && !blockPrivate {
+		return nil, nil
+	}
+
+	blocked := func(host string) bool {
+		if host == "localhost" {
+			return blockPrivate
+		}
+		addr, err := netip.ParseAddr(host)
+		if err != nil {
+			return false
+		}
+		addr = addr.Unmap()
+		if blockPrivate && (addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsUnspecified()) {
+			return true
+		}
+		for _, network := range networks {
+			if network.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var errs field.ErrorList
+	receivers, _ := config["receivers"].([]interface{})
+	for i, r := range receivers {
+		walkURLs(r, field.NewPath("spec", "config", "receivers").Index(i), func(path *field.Path, u string) {
+			parsed, err := url.Parse(u)
+			if err != nil {
+				return
+			}
+			if host := parsed.Hostname(); blocked(host) {
+				errs = append(errs, field.Forbidden(path, fmt.Sprintf("%s is in a network blocked for the tenant", host)))
+			}
+		})
+	}
+	return errs, nil
+}
+
+// walkURLs calls fn for every string value of v, recursively, whose key ends
+// in url, e.g. url, api_url or webhook_url.
+func walkURLs(v interface{}, path *field.Path, fn func(path *field.Path, u string)) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if s, ok := v[k].(string); ok && strings.HasSuffix(k, "url") {
+				fn(path.Child(k), s)
+				continue
+			}
+			walkURLs(v[k], path.Child(k), fn)
+		}
+	case []interface{}:
+		for i := range v {
+			walkURLs(v[i], path.Index(i), fn)
+		}
+	}
+}
+
+// Owner returns the TenantAlertmanagerConfig that configures the tenant the
+// configs, which all belong to the same tenant, are for. As a tenant has a
+// single Alertmanager configuration, the oldest one that is not being
+// deleted wins.
+func Owner(configs []observabilityv1alpha1.TenantAlertmanagerConfig) *observabilityv1alpha1.TenantAlertmanagerConfig {
+	var owner *observabilityv1alpha1.TenantAlertmanagerConfig
+	for i := range configs {
+		c := &configs[i]
+		if !c.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if owner == nil || olderThan(c, owner) {
+			owner = c
+		}
+	}
+	return owner
+}
+
+func olderThan(a, b *observabilityv1alpha1.TenantAlertmanagerConfig) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
@@ -0,0 +1,174 @@
+package alerting_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/clients/alertmanager"
+	"github.com/traceshield/trace-shield-controller/internal/alerting"
+)
+
+func parseConfig(s string) map[string]interface{} {
+	config := map[string]interface{}{}
+	Expect(yaml.Unmarshal([]byte(s), &config)).To(Succeed())
+	return config
+}
+
+var _ = Describe("Render", func() {
+	It("renders the configuration and templates", func() {
+		spec := observabilityv1alpha1.TenantAlertmanagerConfigSpec{
+			Tenant:    "team-a",
+			Config:    observabilityv1alpha1.WrappedMap{Object: parseConfig("route: {receiver: default}\nreceivers: [{name: default}]")},
+			Templates: map[string]string{"default.tmpl": "{{ define \"x\" }}{{ end }}"},
+		}
+		config, err := alerting.Render(spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.TemplateFiles).To(Equal(spec.Templates))
+		Expect(parseConfig(config.AlertmanagerConfig)).To(Equal(spec.Config.Object))
+	})
+})
+
+var _ = Describe("Validate", func() {
+	It("accepts a valid configuration", func() {
+		Expect(alerting.Validate(parseConfig(`
+route:
+  receiver: default
+  routes:
+  - receiver: pager
+    matchers: ['severity="critical"']
+receivers:
+- name: default
+- name: pager
+`))).To(BeEmpty())
+	})
+
+	It("rejects undefined and duplicate receivers", func() {
+		errs := alerting.Validate(parseConfig(`
+route:
+  receiver: default
+  routes:
+  - receiver: missing
+receivers:
+- name: default
+- name: default
+`))
+		Expect(errs).To(HaveLen(2))
+		Expect(errs.ToAggregate().Error()).To(And(
+			ContainSubstring("spec.config.route.routes[0].receiver: Not found"),
+			ContainSubstring("spec.config.receivers[1].name: Duplicate value"),
+		))
+	})
+
+	It("requires a route and receivers", func() {
+		Expect(alerting.Validate(map[string]interface{}{})).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("ExceedsLimits", func() {
+	limit := func(i int) *int { return &i }
+	config := alertmanager.UserConfig{
+		AlertmanagerConfig: "route: {receiver: default}\nreceivers: [{name: default}]\n",
+		TemplateFiles:      map[string]string{"a.tmpl": "aaaa", "b.tmpl": "bb"},
+	}
+
+	// payload is what the Alertmanager client uploads for the configuration.
+	payload, err := yaml.Marshal(config)
+	if err != nil {
+		panic(err)
+	}
+
+	It("accepts configurations within the limits", func() {
+		Expect(alerting.ExceedsLimits(config, observabilityv1alpha1.MimirLimits{})).To(Succeed())
+		Expect(alerting.ExceedsLimits(config, observabilityv1alpha1.MimirLimits{
+			AlertmanagerMaxConfigSizeBytes:   limit(len(payload)),
+			AlertmanagerMaxTemplatesCount:    limit(2),
+			AlertmanagerMaxTemplateSizeBytes: limit(4),
+		})).To(Succeed())
+	})
+
+	It("enforces the size and count limits", func() {
+		Expect(alerting.ExceedsLimits(config, observabilityv1alpha1.MimirLimits{AlertmanagerMaxConfigSizeBytes: limit(10)})).
+			To(MatchError(ContainSubstring("the limit is 10")))
+		Expect(alerting.ExceedsLimits(config, observabilityv1alpha1.MimirLimits{AlertmanagerMaxTemplatesCount: limit(1)})).
+			To(MatchError("the configuration has 2 templates, the limit is 1"))
+		Expect(alerting.ExceedsLimits(config, observabilityv1alpha1.MimirLimits{AlertmanagerMaxTemplateSizeBytes: limit(3)})).
+			To(MatchError("template a.tmpl is 4 bytes, the limit is 3"))
+	})
+
+	It("counts the templates towards the size of the configuration", func() {
+		large := alertmanager.UserConfig{
+			AlertmanagerConfig: config.AlertmanagerConfig,
+			TemplateFiles:      map[string]string{"large.tmpl": strings.Repeat("x", 1024)},
+		}
+		Expect(alerting.ExceedsLimits(large, observabilityv1alpha1.MimirLimits{AlertmanagerMaxConfigSizeBytes: limit(512)})).
+			To(MatchError(ContainSubstring("with its templates, the limit is 512")))
+	})
+})
+
+var _ = Describe("BlockedReceivers", func() {
+	config := parseConfig(`
+route: {receiver: hooks}
+receivers:
+- name: hooks
+  webhook_configs:
+  - url: http://10.1.2.3:8080/hook
+  - url: https://hooks.example.com/hook
+  slack_configs:
+  - api_url: http://127.0.0.1/slack
+`)
+
+	It("allows every receiver without restrictions", func() {
+		Expect(alerting.BlockedReceivers(config, observabilityv1alpha1.MimirLimits{})).To(BeEmpty())
+	})
+
+	It("rejects receivers in blocked networks", func() {
+		networks := "10.0.0.0/8, 192.168.0.0/16"
+		errs, err := alerting.BlockedReceivers(config, observabilityv1alpha1.MimirLimits{AlertmanagerReceiversBlockCIDRNetworks: &networks})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.config.receivers[0].webhook_configs[0].url"))
+	})
+
+	It("rejects receivers at private addresses", func() {
+		block := true
+		errs, err := alerting.BlockedReceivers(config, observabilityv1alpha1.MimirLimits{AlertmanagerReceiversBlockPrivateAddresses: &block})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Field).To(Equal("spec.config.receivers[0].slack_configs[0].api_url"))
+	})
+
+	It("reports invalid blocked networks", func() {
+		networks := "10.0.0.0/33"
+		_, err := alerting.BlockedReceivers(config, observabilityv1alpha1.MimirLimits{AlertmanagerReceiversBlockCIDRNetworks: &networks})
+		Expect(err).To(MatchError(ContainSubstring("invalid blocked network")))
+	})
+})
+
+var _ = Describe("Owner", func() {
+	now := time.Now()
+	config := func(name string, created time.Time) observabilityv1alpha1.TenantAlertmanagerConfig {
+		return observabilityv1alpha1.TenantAlertmanagerConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", CreationTimestamp: metav1.NewTime(created)},
+		}
+	}
+
+	It("picks the oldest configuration that is not being deleted", func() {
+		configs := []observabilityv1alpha1.TenantAlertmanagerConfig{
+			config("new", now),
+			config("old", now.Add(-time.Hour)),
+			config("oldest", now.Add(-2*time.Hour)),
+		}
+		Expect(alerting.Owner(configs).Name).To(Equal("oldest"))
+
+		deletedAt := metav1.NewTime(now)
+		configs[2].DeletionTimestamp = &deletedAt
+		Expect(alerting.Owner(configs).Name).To(Equal("old"))
+		Expect(alerting.Owner(nil)).To(BeNil())
+	})
+})
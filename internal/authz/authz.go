@@ -0,0 +1,221 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package authz serves permission checks for ingestion and query gateways.
+//
+// The handler follows the Envoy ext_authz HTTP service and nginx auth_request
+// conventions: it ignores the request path, reads the subject, tenant and
+// action from headers and answers 200 to allow and 403 to deny the request.
+package authz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/traceshield/trace-shield-controller/clients/keto"
+)
+
+const (
+	DefaultSubjectHeader = "X-Auth-Request-User"
+	DefaultTenantHeader  = "X-Scope-OrgID"
+	DefaultActionHeader  = "X-Auth-Request-Action"
+
+	// ActionRead allows querying a tenant.
+	ActionRead = "read"
+	// ActionWrite allows ingesting data into a tenant.
+	ActionWrite = "write"
+
+	// tenantSeparator separates tenants in federated queries.
+	tenantSeparator = "|"
+	// maxCacheEntries bounds the decision cache.
+	maxCacheEntries = 10000
+)
+
+// actionPermissions maps gateway actions to tenant permissions.
+var actionPermissions = map[string]string{
+	ActionRead:  keto.TenantViewPermission,
+	ActionWrite: keto.TenantEditPermission,
+}
+
+// Handler answers whether the subject may perform the action on every tenant
+// in the request headers.
+type Handler struct {
+	Checker keto.PermissionChecker
+	Log     logr.Logger
+
+	// SubjectHeader, TenantHeader and ActionHeader name the headers the
+	// request attributes are read from.
+	SubjectHeader, TenantHeader, ActionHeader string
+	// CacheTTL is how long decisions are cached. Zero disables caching.
+	CacheTTL time.Duration
+
+	mu    sync.Mutex
+	cache map[decisionKey]decision
+	now   func() time.Time
+}
+
+type decisionKey struct {
+	subject, tenant, permission string
+}
+
+type decision struct {
+	allowed bool
+	expires time.Time
+}
+
+// NewHandler returns a Handler reading the default headers.
+func NewHandler(checker keto.PermissionChecker, cacheTTL time.Duration, log logr.Logger) *Handler {
+	return &Handler{
+		Checker:       checker,
+		Log:           log,
+		SubjectHeader: DefaultSubjectHeader,
+		TenantHeader:  DefaultTenantHeader,
+		ActionHeader:  DefaultActionHeader,
+		CacheTTL:      cacheTTL,
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	subject := strings.TrimSpace(r.Header.Get(h.SubjectHeader))
+	tenants := strings.TrimSpace(r.Header.Get(h.TenantHeader))
+	action := strings.TrimSpace(r.Header.Get(h.ActionHeader))
+	if subject == "" || tenants == "" || action == "" {
+		http.Error(w, "the "+h.SubjectHeader+", "+h.TenantHeader+" and "+h.ActionHeader+" headers are required", http.StatusBadRequest)
+		return
+	}
+	permission, ok := actionPermissions[action]
+	if !ok {
+		http.Error(w, "unknown action "+action, http.StatusBadRequest)
+		return
+	}
+
+	for _, tenant := range strings.Split(tenants, tenantSeparator) {
+		allowed, err := h.allowed(r.Context(), subject, tenant, permission)
+		if err != nil {
+			h.Log.Error(err, "failed to check permission", "subject", subject, "tenant", tenant, "action", action)
+			http.Error(w, "permission check failed", http.StatusServiceUnavailable)
+			return
+		}
+		if !allowed {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) allowed(ctx context.Context, subject, tenant, permission string) (bool, error) {
+	key := decisionKey{subject: subject, tenant: tenant, permission: permission}
+	if d, ok := h.cached(key); ok {
+		return d, nil
+	}
+
+	allowed, err := h.Checker.CheckTenantPermission(ctx, tenant, permission, keto.NewSubjectSet(keto.UserNamespace, subject, ""))
+	if err != nil {
+		return false, err
+	}
+	h.store(key, allowed)
+	return allowed, nil
+}
+
+func (h *Handler) cached(key decisionKey) (bool, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	d, ok := h.cache[key]
+	if !ok || !h.clock().Before(d.expires) {
+		return false, false
+	}
+	return d.allowed, true
+}
+
+func (h *Handler) store(key decisionKey, allowed bool) {
+	if h.CacheTTL <= 0 {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.clock()
+	if h.cache == nil {
+		h.cache = map[decisionKey]decision{}
+	}
+	if len(h.cache) >= maxCacheEntries {
+		for k, d := range h.cache {
+			if !now.Before(d.expires) {
+				delete(h.cache, k)
+			}
+		}
+		if len(h.cache) >= maxCacheEntries {
+			h.cache = map[decisionKey]decision{}
+		}
+	}
+	h.cache[key] = decision{allowed: allowed, expires: now.Add(h.CacheTTL)}
+}
+
+func (h *Handler) clock() time.Time {
+	if h.now != nil {
+		return h.now()
+	}
+	return time.Now()
+}
+
+// Server serves a Handler and implements manager.Runnable.
+type Server struct {
+	Addr    string
+	Handler http.Handler
+}
+
+// Start serves until ctx is done.
+func (s *Server) Start(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              s.Addr,
+		Handler:           s.Handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			return err
+		}
+		if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}
+}
+
+// NeedLeaderElection lets every replica answer permission checks.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,99 @@
+package authz
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	rts "github.com/ory/keto/proto/ory/keto/relation_tuples/v1alpha2"
+
+	"github.com/traceshield/trace-shield-controller/clients/keto"
+	"github.com/traceshield/trace-shield-controller/clients/keto/ketotest"
+)
+
+var _ = Describe("Handler", func() {
+	var (
+		server  *ketotest.Server
+		handler *Handler
+		now     time.Time
+	)
+
+	grant := func(tenant, permission, user string) {
+		server.Insert(&rts.RelationTuple{
+			Namespace: keto.ObservabilityTenantNamespace,
+			Object:    tenant,
+			Relation:  permission,
+			Subject:   rts.NewSubjectSet(keto.UserNamespace, user, ""),
+		})
+	}
+
+	check := func(user, tenant, action string) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/push", nil)
+		if user != "" {
+			req.Header.Set(DefaultSubjectHeader, user)
+		}
+		req.Header.Set(DefaultTenantHeader, tenant)
+		req.Header.Set(DefaultActionHeader, action)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	BeforeEach(func() {
+		var err error
+		server, err = ketotest.NewServer()
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(server.Stop)
+
+		client, err := keto.NewKetoGrpcClient(context.Background(), server.ConnectionDetails())
+		Expect(err).NotTo(HaveOccurred())
+
+		now = time.Now()
+		handler = NewHandler(client, 10*time.Second, logr.Discard())
+		handler.now = func() time.Time { return now }
+	})
+
+	It("allows subjects holding the permission for the action", func() {
+		grant("team-a", keto.TenantEditPermission, "alice")
+
+		Expect(check("alice", "team-a", ActionWrite)).To(Equal(http.StatusOK))
+		Expect(check("alice", "team-a", ActionRead)).To(Equal(http.StatusForbidden))
+		Expect(check("bob", "team-a", ActionWrite)).To(Equal(http.StatusForbidden))
+	})
+
+	It("requires the permission on every tenant of a federated query", func() {
+		grant("team-a", keto.TenantViewPermission, "alice")
+
+		Expect(check("alice", "team-a|team-b", ActionRead)).To(Equal(http.StatusForbidden))
+
+		grant("team-b", keto.TenantViewPermission, "alice")
+		now = now.Add(11 * time.Second)
+		Expect(check("alice", "team-a|team-b", ActionRead)).To(Equal(http.StatusOK))
+	})
+
+	It("rejects incomplete requests", func() {
+		Expect(check("", "team-a", ActionRead)).To(Equal(http.StatusBadRequest))
+		Expect(check("alice", "team-a", "delete")).To(Equal(http.StatusBadRequest))
+	})
+
+	It("caches decisions until they expire", func() {
+		grant("team-a", keto.TenantViewPermission, "alice")
+		Expect(check("alice", "team-a", ActionRead)).To(Equal(http.StatusOK))
+
+		server.Reset()
+		Expect(check("alice", "team-a", ActionRead)).To(Equal(http.StatusOK))
+
+		now = now.Add(11 * time.Second)
+		Expect(check("alice", "team-a", ActionRead)).To(Equal(http.StatusForbidden))
+	})
+
+	It("fails closed when keto is unavailable", func() {
+		server.Stop()
+		Expect(check("alice", "team-a", ActionRead)).To(Equal(http.StatusServiceUnavailable))
+	})
+})
@@ -0,0 +1,189 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observability
+
+import (
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/internal/limits"
+)
+
+const (
+	// maxAutoscaleHistory is how many changes of an autoscaled limit are kept in the status of a tenant.
+	maxAutoscaleHistory = 10
+
+	// defaultAutoscaleCooldown is the cooldown of autoscaled limits that do not set one.
+	defaultAutoscaleCooldown = time.Hour
+
+	// autoscaledEventReason is the reason of the events recorded when an autoscaled limit changes.
+	autoscaledEventReason = "Autoscaled"
+)
+
+// autoscaleLimits returns spec with the autoscaled limits of the tenant set to
+// their current value, scaling them to the recommended usage of the tenant
+// first if they are due. It also returns how long until a limit that is held
+// back by its cooldown can be scaled, which is zero if none is.
+func (r *TenantReconciler) autoscaleLimits(tenant *observabilityv1alpha1.Tenant, spec *observabilityv1alpha1.LimitSpec, now time.Time) (*observabilityv1alpha1.LimitSpec, time.Duration, error) {
+	ts := metav1.NewTime(now)
+	var wait time.Duration
+	statuses := make([]observabilityv1alpha1.AutoscaleStatus, 0, len(tenant.Spec.Autoscale))
+
+	for _, a := range tenant.Spec.Autoscale {
+		status := observabilityv1alpha1.AutoscaleStatus{Limit: a.Limit}
+		i := autoscaleStatusIndex(tenant.Status.Autoscale, a.Limit)
+		if i >= 0 {
+			status = tenant.Status.Autoscale[i]
+		}
+
+		baseline, err := limits.LimitValue(spec, a.Limit)
+		if err != nil {
+			return nil, 0, err
+		}
+		start := a.Min
+		if baseline != nil {
+			start = limits.Clamp(a, *baseline)
+		}
+		scale := func(reason observabilityv1alpha1.AutoscaleReason, to float64, usage *float64) {
+			r.recordAutoscale(tenant, &status, observabilityv1alpha1.AutoscaleEvent{
+				Time:   ts,
+				Reason: reason,
+				From:   status.Value,
+				To:     to,
+				Usage:  usage,
+			})
+		}
+
+		if i < 0 {
+			status.Baseline = copyFloatPtr(baseline)
+			status.Value = start
+		}
+
+		switch {
+		case !floatPtrEqual(status.Baseline, baseline):
+			// Start over whenever the baseline changes.
+			status.Baseline = copyFloatPtr(baseline)
+			if start != status.Value {
+				scale(observabilityv1alpha1.AutoscaleBaselineReason, start, nil)
+			}
+		case limits.Clamp(a, status.Value) != status.Value:
+			scale(observabilityv1alpha1.AutoscaleBoundsReason, limits.Clamp(a, status.Value), nil)
+		default:
+			usage := r.recommendedUsage(tenant, a.Limit)
+			if usage == nil {
+				break
+			}
+			to, ok := limits.Autoscale(a, status.Value, *usage)
+			if !ok {
+				break
+			}
+			cooldown := defaultAutoscaleCooldown
+			if a.Cooldown != nil {
+				cooldown = a.Cooldown.Duration
+			}
+			if status.LastScaleTime != nil {
+				if due := status.LastScaleTime.Add(cooldown); now.Before(due) {
+					if d := due.Sub(now); wait == 0 || d < wait {
+						wait = d
+					}
+					break
+				}
+			}
+			scale(observabilityv1alpha1.AutoscaleUsageReason, to, usage)
+		}
+
+		if spec, err = limits.SetLimit(spec, a.Limit, status.Value); err != nil {
+			return nil, 0, err
+		}
+		statuses = append(statuses, status)
+	}
+
+	if len(statuses) == 0 {
+		statuses = nil
+	}
+	tenant.Status.Autoscale = statuses
+	return spec, wait, nil
+}
+
+// recordAutoscale applies the change of an autoscaled limit to its status and
+// records it as an event of the tenant.
+func (r *TenantReconciler) recordAutoscale(tenant *observabilityv1alpha1.Tenant, status *observabilityv1alpha1.AutoscaleStatus, event observabilityv1alpha1.AutoscaleEvent) {
+	status.Value = event.To
+	status.LastScaleTime = &event.Time
+	status.History = append(status.History, event)
+	if len(status.History) > maxAutoscaleHistory {
+		status.History = status.History[len(status.History)-maxAutoscaleHistory:]
+	}
+
+	if r.Recorder == nil {
+		return
+	}
+	msg := "scaled " + status.Limit + " from " + formatLimit(event.From) + " to " + formatLimit(event.To)
+	if event.Usage != nil {
+		msg += " for a usage of " + formatLimit(*event.Usage)
+	} else {
+		msg += " (" + string(event.Reason) + ")"
+	}
+	r.Recorder.Event(tenant, corev1.EventTypeNormal, autoscaledEventReason, msg)
+}
+
+// recommendedUsage returns the usage of the tenant for the limit, as last
+// queried for its recommendations.
+func (r *TenantReconciler) recommendedUsage(tenant *observabilityv1alpha1.Tenant, limit string) *float64 {
+	if r.Config.Spec.Usage == nil || tenant.Status.Recommendations == nil {
+		return nil
+	}
+	for _, rec := range tenant.Status.Recommendations.Limits {
+		if rec.Limit == limit {
+			usage := rec.Usage
+			return &usage
+		}
+	}
+	return nil
+}
+
+func autoscaleStatusIndex(statuses []observabilityv1alpha1.AutoscaleStatus, limit string) int {
+	for i := range statuses {
+		if statuses[i].Limit == limit {
+			return i
+		}
+	}
+	return -1
+}
+
+func floatPtrEqual(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func copyFloatPtr(f *float64) *float64 {
+	if f == nil {
+		return nil
+	}
+	c := *f
+	return &c
+}
+
+func formatLimit(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
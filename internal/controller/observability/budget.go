@@ -0,0 +1,97 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observability
+
+import (
+	"context"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/pluralsh/controller-reconcile-helper/pkg/conditions"
+	crhelperTypes "github.com/pluralsh/controller-reconcile-helper/pkg/types"
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/internal/limits"
+)
+
+// resolveBudget marks the tenant if the limits of its children exceed its budget.
+func resolveBudget(tenant *observabilityv1alpha1.Tenant, tenants []observabilityv1alpha1.Tenant) error {
+	if tenant.Spec.Budget == nil {
+		conditions.Delete(tenant, observabilityv1alpha1.BudgetReadyCondition)
+		return nil
+	}
+
+	_, specs := limits.ChildLimits(tenant.Name, tenants)
+	violations, err := limits.CheckBudget(tenant.Spec.Budget, specs)
+	if err != nil {
+		return err
+	}
+	if len(violations) == 0 {
+		conditions.MarkTrue(tenant, observabilityv1alpha1.BudgetReadyCondition)
+		return nil
+	}
+
+	msgs := make([]string, len(violations))
+	for i, v := range violations {
+		msgs[i] = v.String()
+	}
+	conditions.MarkFalse(tenant, observabilityv1alpha1.BudgetReadyCondition, observabilityv1alpha1.BudgetExceededReason,
+		crhelperTypes.ConditionSeverityWarning, "%s", strings.Join(msgs, "; "))
+	return nil
+}
+
+// budgetShare returns the share of the budget of parent allotted to child.
+func budgetShare(parent, child *observabilityv1alpha1.Tenant, tenants []observabilityv1alpha1.Tenant) (*observabilityv1alpha1.LimitSpec, error) {
+	if parent.Spec.Budget == nil {
+		return nil, nil
+	}
+	names, specs := limits.ChildLimits(parent.Name, tenants)
+	for i, name := range names {
+		if name == child.Name {
+			return limits.ShareBudget(parent.Spec.Budget, specs, i)
+		}
+	}
+	return nil, nil
+}
+
+// findBudgetTenants returns the parent of obj, whose budget depends on the
+// limits of obj, and the other descendants of the parent, whose shares of the
+// budget do.
+func (r *TenantReconciler) findBudgetTenants(ctx context.Context, obj client.Object) []reconcile.Request {
+	tenant, ok := obj.(*observabilityv1alpha1.Tenant)
+	if !ok || tenant.Spec.Parent == "" {
+		return []reconcile.Request{}
+	}
+
+	tenantList := &observabilityv1alpha1.TenantList{}
+	if err := r.List(ctx, tenantList); err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := []reconcile.Request{}
+	for i := range tenantList.Items {
+		t := &tenantList.Items[i]
+		if t.Name == tenant.Name {
+			continue
+		}
+		if t.Name == tenant.Spec.Parent || descendsFrom(t, tenant.Spec.Parent, tenantList.Items) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(t)})
+		}
+	}
+	return requests
+}
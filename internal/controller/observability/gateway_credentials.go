@@ -0,0 +1,162 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observability
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	reconcilehelper "github.com/pluralsh/controller-reconcile-helper/pkg/reconcile-helper/core"
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/internal/gateway"
+)
+
+const (
+	// gatewayCredentialsLabel marks the per-tenant gateway credential Secrets.
+	gatewayCredentialsLabel = "observability.traceshield.io/gateway-credentials"
+	// tenantLabel holds the name of the Tenant a Secret belongs to.
+	tenantLabel = "observability.traceshield.io/tenant"
+	// rotatedForAnnotation holds the value of the rotation annotation the
+	// credentials were last generated for.
+	rotatedForAnnotation = "observability.traceshield.io/rotated-for"
+
+	defaultGatewaySecretName = "gateway-tenant-credentials"
+)
+
+func gatewayCredentialsSecretName(tenant string) string {
+	return tenant + "-gateway-credentials"
+}
+
+// reconcileGatewayCredentials makes sure the tenant has a credential Secret,
+// regenerating it when the rotation annotation changes, and updates the
+// aggregated Secret consumed by the gateways.
+func (r *TenantReconciler) reconcileGatewayCredentials(ctx context.Context, tenant *observabilityv1alpha1.Tenant, log logr.Logger) error {
+	gw := r.Config.Spec.Gateway
+
+	existing := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: gatewayCredentialsSecretName(tenant.Name), Namespace: gw.Namespace}, existing)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+
+	rotation := tenant.Annotations[observabilityv1alpha1.RotateCredentialsAnnotation]
+	if apierrs.IsNotFound(err) ||
+		existing.Annotations[rotatedForAnnotation] != rotation ||
+		string(existing.Data[gateway.TenantIDKey]) != tenant.GetTenantID() ||
+		!gateway.MatchesType(gw.CredentialType, existing.Data) {
+
+		data, err := gateway.NewCredentials(gw.CredentialType, tenant.GetTenantID())
+		if err != nil {
+			return err
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      gatewayCredentialsSecretName(tenant.Name),
+				Namespace: gw.Namespace,
+				Labels: map[string]string{
+					gatewayCredentialsLabel: "true",
+					tenantLabel:             tenant.Name,
+				},
+				Annotations: map[string]string{
+					rotatedForAnnotation: rotation,
+				},
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: data,
+		}
+		if err := controllerutil.SetControllerReference(tenant, secret, r.Scheme); err != nil {
+			return err
+		}
+		if err := reconcilehelper.Secret(ctx, r.Client, secret, log); err != nil {
+			log.Error(err, "Error reconciling Secret", "name", secret.Name)
+			return err
+		}
+		existing = secret
+	}
+
+	tenant.Status.GatewayCredentials = &corev1.SecretReference{
+		Name:      gatewayCredentialsSecretName(tenant.Name),
+		Namespace: gw.Namespace,
+	}
+
+	return r.updateGatewaySecret(ctx, tenant.Name, existing, log)
+}
+
+// updateGatewaySecret rebuilds the aggregated gateway Secret from the
+// per-tenant credential Secrets. The Secret of the given tenant is replaced by
+// current, which may be fresher than the cache, or left out if current is nil.
+func (r *TenantReconciler) updateGatewaySecret(ctx context.Context, tenant string, current *corev1.Secret, log logr.Logger) error {
+	gw := r.Config.Spec.Gateway
+
+	secrets := &corev1.SecretList{}
+	if err := r.List(ctx, secrets, client.InNamespace(gw.Namespace), client.MatchingLabels{gatewayCredentialsLabel: "true"}); err != nil {
+		return err
+	}
+	included := make([]corev1.Secret, 0, len(secrets.Items)+1)
+	for _, s := range secrets.Items {
+		if s.Labels[tenantLabel] != tenant {
+			included = append(included, s)
+		}
+	}
+	if current != nil {
+		included = append(included, *current)
+	}
+
+	data, err := gateway.Aggregate(gw.CredentialType, included)
+	if err != nil {
+		return err
+	}
+
+	name := gw.SecretName
+	if name == "" {
+		name = defaultGatewaySecretName
+	}
+	aggregated := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: gw.Namespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+	if err := reconcilehelper.Secret(ctx, r.Client, aggregated, log); err != nil {
+		log.Error(err, "Error reconciling Secret", "name", aggregated.Name)
+		return err
+	}
+	return nil
+}
+
+// deleteGatewayCredentials removes the credentials of the tenant from the gateways.
+func (r *TenantReconciler) deleteGatewayCredentials(ctx context.Context, tenant *observabilityv1alpha1.Tenant, log logr.Logger) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      gatewayCredentialsSecretName(tenant.Name),
+			Namespace: r.Config.Spec.Gateway.Namespace,
+		},
+	}
+	if err := r.Delete(ctx, secret); err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+	return r.updateGatewaySecret(ctx, tenant.Name, nil, log)
+}
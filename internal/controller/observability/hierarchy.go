@@ -0,0 +1,154 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observability
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/pluralsh/controller-reconcile-helper/pkg/conditions"
+	crhelperTypes "github.com/pluralsh/controller-reconcile-helper/pkg/types"
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/internal/limits"
+)
+
+// hierarchyError is returned when the ancestors of a tenant cannot be resolved.
+type hierarchyError struct {
+	reason  string
+	message string
+}
+
+func (e *hierarchyError) Error() string {
+	return e.message
+}
+
+// tenantAncestors returns the ancestors of the tenant, starting with its parent.
+func tenantAncestors(tenant *observabilityv1alpha1.Tenant, tenants []observabilityv1alpha1.Tenant) ([]*observabilityv1alpha1.Tenant, error) {
+	byName := make(map[string]*observabilityv1alpha1.Tenant, len(tenants))
+	for i := range tenants {
+		byName[tenants[i].Name] = &tenants[i]
+	}
+
+	var ancestors []*observabilityv1alpha1.Tenant
+	visited := map[string]bool{tenant.Name: true}
+	for parent := tenant.Spec.Parent; parent != ""; {
+		if visited[parent] {
+			return nil, &hierarchyError{
+				reason:  observabilityv1alpha1.ParentCycleReason,
+				message: fmt.Sprintf("tenant %s is its own ancestor through %s", tenant.Name, parent),
+			}
+		}
+		visited[parent] = true
+
+		t, ok := byName[parent]
+		if !ok {
+			return nil, &hierarchyError{
+				reason:  observabilityv1alpha1.ParentNotFoundReason,
+				message: fmt.Sprintf("parent tenant %s does not exist", parent),
+			}
+		}
+		ancestors = append(ancestors, t)
+		parent = t.Spec.Parent
+	}
+	return ancestors, nil
+}
+
+// resolveLimits returns the limits of the tenant merged on top of the limits
+// inherited from its ancestors and the shares of their budgets. The tenant is marked if its ancestors cannot
+// be resolved, in which case only its own limits are returned.
+func resolveLimits(tenant *observabilityv1alpha1.Tenant, tenants []observabilityv1alpha1.Tenant) (*observabilityv1alpha1.LimitSpec, error) {
+	ancestors, err := tenantAncestors(tenant, tenants)
+	if err != nil {
+		herr := err.(*hierarchyError)
+		conditions.MarkFalse(tenant, observabilityv1alpha1.HierarchyReadyCondition, herr.reason,
+			crhelperTypes.ConditionSeverityWarning, "%s", herr.Error())
+		return tenant.Spec.Limits, nil
+	}
+
+	if tenant.Spec.Parent != "" {
+		conditions.MarkTrue(tenant, observabilityv1alpha1.HierarchyReadyCondition)
+	} else {
+		conditions.Delete(tenant, observabilityv1alpha1.HierarchyReadyCondition)
+	}
+
+	// Walk down from the root, applying the limits of every tenant and the
+	// share of its parent's budget it gets for the limits it leaves unset.
+	chain := make([]*observabilityv1alpha1.Tenant, 0, len(ancestors)+1)
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		chain = append(chain, ancestors[i])
+	}
+	chain = append(chain, tenant)
+
+	var effective *observabilityv1alpha1.LimitSpec
+	for i, t := range chain {
+		if effective, err = limits.Merge(effective, t.Spec.Limits); err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			continue
+		}
+		share, err := budgetShare(chain[i-1], t, tenants)
+		if err != nil {
+			return nil, err
+		}
+		if effective, err = limits.Merge(effective, share); err != nil {
+			return nil, err
+		}
+	}
+	return effective, nil
+}
+
+// findDescendantTenants returns the tenants that inherit from obj.
+func (r *TenantReconciler) findDescendantTenants(ctx context.Context, obj client.Object) []reconcile.Request {
+	tenantList := &observabilityv1alpha1.TenantList{}
+	if err := r.List(ctx, tenantList); err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := []reconcile.Request{}
+	for i := range tenantList.Items {
+		t := &tenantList.Items[i]
+		if t.Name != obj.GetName() && descendsFrom(t, obj.GetName(), tenantList.Items) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(t)})
+		}
+	}
+	return requests
+}
+
+// descendsFrom reports whether name is an ancestor of the tenant.
+func descendsFrom(tenant *observabilityv1alpha1.Tenant, name string, tenants []observabilityv1alpha1.Tenant) bool {
+	visited := map[string]bool{}
+	for parent := tenant.Spec.Parent; parent != "" && !visited[parent]; parent = parentOf(tenants, parent) {
+		if parent == name {
+			return true
+		}
+		visited[parent] = true
+	}
+	return false
+}
+
+func parentOf(tenants []observabilityv1alpha1.Tenant, name string) string {
+	for _, t := range tenants {
+		if t.Name == name {
+			return t.Spec.Parent
+		}
+	}
+	return ""
+}
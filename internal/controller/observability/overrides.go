@@ -0,0 +1,257 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observability
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/internal/limits"
+	"github.com/traceshield/trace-shield-controller/internal/overrides"
+)
+
+// renderedMimirLimits returns the Mimir limits rendered for the tenant in
+// the runtime ConfigMap, which are empty if the ConfigMap does not exist.
+func renderedMimirLimits(ctx context.Context, c client.Client, selector observabilityv1alpha1.ConfigMapSelector, tenantID string) (observabilityv1alpha1.MimirLimits, error) {
+	data := mimirConfigData{}
+	if err := readConfigMapData(ctx, c, selector, &data); err != nil {
+		return observabilityv1alpha1.MimirLimits{}, err
+	}
+	return data.Overrides[tenantID], nil
+}
+
+// renderedLokiLimits returns the Loki limits rendered for the tenant in the
+// runtime ConfigMap, which are empty if the ConfigMap does not exist.
+func renderedLokiLimits(ctx context.Context, c client.Client, selector observabilityv1alpha1.ConfigMapSelector, tenantID string) (observabilityv1alpha1.LokiLimits, error) {
+	data := lokiConfigData{}
+	if err := readConfigMapData(ctx, c, selector, &data, overrides.SplitBlockedQueryTypes); err != nil {
+		return observabilityv1alpha1.LokiLimits{}, err
+	}
+	return data.Overrides[tenantID], nil
+}
+
+// readConfigMapData decodes the runtime configuration held by the ConfigMap
+// into out, after passing it through the given conversions.
+func readConfigMapData(ctx context.Context, c client.Client, selector observabilityv1alpha1.ConfigMapSelector, out interface{}, conversions ...func([]byte) ([]byte, error)) error {
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Name: selector.Name, Namespace: selector.Namespace}, cm); err != nil {
+		return ignoreNotFound(err)
+	}
+	data := []byte(cm.Data[selector.Key])
+	for _, convert := range conversions {
+		var err error
+		if data, err = convert(data); err != nil {
+			return err
+		}
+	}
+	return yaml.Unmarshal(data, out)
+}
+
+// renderRuntimeConfig renders the data of a runtime configuration, handling
+// the keys of the existing runtime configuration the controller does not own
+// as the Config asks for. inlined are the keys merged from the extra limits
+// when the existing runtime configuration was rendered, and the keys merged
+// this time are returned.
+func (r *TenantReconciler) renderRuntimeConfig(ctx context.Context, data interface{}, existing string, inlined overrides.ExtraKeys, schema overrides.Schema) ([]byte, overrides.ExtraKeys, error) {
+	rendered, err := yaml.Marshal(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if existing, err = ownedRuntimeConfig(existing, inlined, schema); err != nil {
+		return nil, nil, err
+	}
+	switch r.unownedKeysPolicy() {
+	case observabilityv1alpha1.UnownedKeysDrop:
+		tenantList := &observabilityv1alpha1.TenantList{}
+		if err := r.List(ctx, tenantList); err != nil {
+			return nil, nil, err
+		}
+		if rendered, err = overrides.Drop(rendered, managedTenantIDs(tenantList.Items)); err != nil {
+			return nil, nil, err
+		}
+	case observabilityv1alpha1.UnownedKeysRefuse:
+		tenantList := &observabilityv1alpha1.TenantList{}
+		if err := r.List(ctx, tenantList); err != nil {
+			return nil, nil, err
+		}
+		extra, err := extraTenantIDs(tenantList.Items, schema)
+		if err != nil {
+			return nil, nil, err
+		}
+		unowned, err := overrides.Unowned(existing, schema, managedTenantIDs(tenantList.Items), extra)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(unowned) > 0 {
+			return nil, nil, fmt.Errorf("refusing to drop keys the controller does not own: %s", strings.Join(unowned, ", "))
+		}
+	default:
+		if rendered, err = overrides.Preserve(rendered, existing, schema); err != nil {
+			return nil, nil, err
+		}
+	}
+	rendered, inlined, err = overrides.InlineExtra(rendered)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch schema {
+	case overrides.LokiSchema:
+		rendered, err = overrides.JoinBlockedQueryTypes(rendered)
+	case overrides.TempoSchema:
+		rendered, err = overrides.RenameDimensionMappings(rendered)
+	}
+	return rendered, inlined, err
+}
+
+// ownedRuntimeConfig returns the existing runtime configuration as the
+// controller compares it with the Tenant API, without the keys merged from
+// the extra limits and with the keys the Tenant API names differently.
+func ownedRuntimeConfig(existing string, inlined overrides.ExtraKeys, schema overrides.Schema) (string, error) {
+	existing, err := overrides.WithoutExtra(existing, inlined)
+	if err != nil || existing == "" || schema != overrides.TempoSchema {
+		return existing, err
+	}
+	data, err := overrides.RestoreDimensionMappings([]byte(existing))
+	return string(data), err
+}
+
+// extraKeysAnnotation records on a runtime ConfigMap the keys merged from the
+// extra limits into the overrides of each tenant, so that they are still
+// owned by the controller once they are removed from the extra limits.
+const extraKeysAnnotation = "observability.traceshield.io/extra-keys"
+
+// extraKeys returns the keys merged from the extra limits recorded on the
+// runtime ConfigMap.
+func extraKeys(cm *corev1.ConfigMap) (overrides.ExtraKeys, error) {
+	value, ok := cm.Annotations[extraKeysAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	keys := overrides.ExtraKeys{}
+	if err := json.Unmarshal([]byte(value), &keys); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation on ConfigMap %s/%s: %w", extraKeysAnnotation, cm.Namespace, cm.Name, err)
+	}
+	return keys, nil
+}
+
+// extraKeysAnnotations returns the annotations of a runtime ConfigMap
+// recording the keys merged from the extra limits.
+func extraKeysAnnotations(keys overrides.ExtraKeys) (map[string]string, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	value, err := json.Marshal(keys)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{extraKeysAnnotation: string(value)}, nil
+}
+
+// unownedKeys returns the keys of the runtime configurations the controller
+// does not own, prefixed by the backend they belong to.
+func (r *TenantReconciler) unownedKeys(tenants []observabilityv1alpha1.Tenant) ([]string, error) {
+	managed := managedTenantIDs(tenants)
+	var out []string
+	for _, rc := range []struct {
+		backend  string
+		enabled  bool
+		existing string
+		inlined  overrides.ExtraKeys
+		schema   overrides.Schema
+	}{
+		{"mimir", r.Config.Spec.Mimir != nil, r.mimirRuntimeConfig, r.mimirExtraKeys, overrides.MimirSchema},
+		{"loki", r.Config.Spec.Loki != nil, r.lokiRuntimeConfig, r.lokiExtraKeys, overrides.LokiSchema},
+		{"tempo", r.Config.Spec.Tempo != nil, r.tempoRuntimeConfig, r.tempoExtraKeys, overrides.TempoSchema},
+	} {
+		if !rc.enabled {
+			continue
+		}
+		extra, err := extraTenantIDs(tenants, rc.schema)
+		if err != nil {
+			return nil, err
+		}
+		existing, err := ownedRuntimeConfig(rc.existing, rc.inlined, rc.schema)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %s runtime configuration: %w", rc.backend, err)
+		}
+		unowned, err := overrides.Unowned(existing, rc.schema, managed, extra)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %s runtime configuration: %w", rc.backend, err)
+		}
+		for _, key := range unowned {
+			out = append(out, rc.backend+" "+key)
+		}
+	}
+	return out, nil
+}
+
+// unownedKeysPolicy returns how the keys of the runtime configurations the
+// controller does not own are handled.
+func (r *TenantReconciler) unownedKeysPolicy() observabilityv1alpha1.UnownedKeysPolicy {
+	if r.Config.Spec.UnownedKeys == "" {
+		return observabilityv1alpha1.UnownedKeysPreserve
+	}
+	return r.Config.Spec.UnownedKeys
+}
+
+// managedTenantIDs returns the tenant IDs of the Tenants.
+func managedTenantIDs(tenants []observabilityv1alpha1.Tenant) map[string]bool {
+	managed := make(map[string]bool, len(tenants))
+	for i := range tenants {
+		managed[tenants[i].GetTenantID()] = true
+	}
+	return managed
+}
+
+// extraTenantIDs returns the tenant IDs of the Tenants whose limits for the
+// backend of the runtime configuration set extra, including limits they
+// inherit or that temporarily apply.
+func extraTenantIDs(tenants []observabilityv1alpha1.Tenant, schema overrides.Schema) (map[string]bool, error) {
+	now := time.Now()
+	extra := map[string]bool{}
+	for i := range tenants {
+		tenant := tenants[i].DeepCopy()
+		spec, err := resolveLimits(tenant, tenants)
+		if err != nil {
+			return nil, err
+		}
+		if spec, _, err = limits.ApplyTemporary(spec, tenant.Spec.TemporaryLimits, now); err != nil {
+			return nil, err
+		}
+		if spec == nil {
+			continue
+		}
+		switch schema {
+		case overrides.MimirSchema:
+			extra[tenant.GetTenantID()] = spec.Mimir != nil && spec.Mimir.Extra != nil
+		case overrides.LokiSchema:
+			extra[tenant.GetTenantID()] = spec.Loki != nil && spec.Loki.Extra != nil
+		case overrides.TempoSchema:
+			extra[tenant.GetTenantID()] = spec.Tempo != nil && spec.Tempo.Extra != nil
+		}
+	}
+	return extra, nil
+}
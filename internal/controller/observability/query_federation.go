@@ -0,0 +1,130 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observability
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
+
+	"github.com/pluralsh/controller-reconcile-helper/pkg/conditions"
+	reconcilehelper "github.com/pluralsh/controller-reconcile-helper/pkg/reconcile-helper/core"
+	crhelperTypes "github.com/pluralsh/controller-reconcile-helper/pkg/types"
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+)
+
+// federationSeparator separates tenant IDs in cross-tenant queries.
+const federationSeparator = "|"
+
+// resolveQueryFederation returns the tenant IDs of the tenants in the query
+// federation of the tenant that exist, and marks the tenant if any of them do not.
+func resolveQueryFederation(tenant *observabilityv1alpha1.Tenant, tenants []observabilityv1alpha1.Tenant) []string {
+	live := map[string]string{}
+	for i := range tenants {
+		if tenants[i].DeletionTimestamp.IsZero() {
+			live[tenants[i].Name] = tenants[i].GetTenantID()
+		}
+	}
+
+	var existing, missing []string
+	seen := map[string]bool{tenant.Name: true}
+	for _, name := range tenant.Spec.QueryFederation {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if id, ok := live[name]; ok {
+			existing = append(existing, id)
+		} else {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(existing)
+
+	switch {
+	case len(missing) > 0:
+		conditions.MarkFalse(tenant, observabilityv1alpha1.QueryFederationReadyCondition, observabilityv1alpha1.FederatedTenantNotFoundReason,
+			crhelperTypes.ConditionSeverityWarning, "tenants %s do not exist", strings.Join(missing, ", "))
+	case len(tenant.Spec.QueryFederation) > 0:
+		conditions.MarkTrue(tenant, observabilityv1alpha1.QueryFederationReadyCondition)
+	default:
+		conditions.Delete(tenant, observabilityv1alpha1.QueryFederationReadyCondition)
+	}
+	return existing
+}
+
+// updateQueryFederationConfigMap exports the query federation of every tenant
+// as a map from tenant ID to the tenant IDs its queries may span.
+func (r *TenantReconciler) updateQueryFederationConfigMap(ctx context.Context, tenants []observabilityv1alpha1.Tenant, log logr.Logger) error {
+	federation := map[string]string{}
+	for i := range tenants {
+		t := &tenants[i]
+		if !t.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if members := resolveQueryFederation(t.DeepCopy(), tenants); len(members) > 0 {
+			federation[t.GetTenantID()] = strings.Join(append([]string{t.GetTenantID()}, members...), federationSeparator)
+		}
+	}
+
+	data, err := yaml.Marshal(federation)
+	if err != nil {
+		return err
+	}
+
+	selector := r.Config.Spec.QueryFederation.ConfigMap
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      selector.Name,
+			Namespace: selector.Namespace,
+		},
+		Data: map[string]string{
+			selector.Key: string(data),
+		},
+	}
+	if err := reconcilehelper.ConfigMap(ctx, r.Client, configMap, log); err != nil {
+		log.Error(err, "Error reconciling ConfigMap", "name", configMap.Name)
+		return err
+	}
+	return nil
+}
+
+// findFederatingTenants returns the tenants whose query federation includes obj.
+func (r *TenantReconciler) findFederatingTenants(ctx context.Context, obj client.Object) []reconcile.Request {
+	tenantList := &observabilityv1alpha1.TenantList{}
+	if err := r.List(ctx, tenantList); err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := []reconcile.Request{}
+	for _, t := range tenantList.Items {
+		for _, name := range t.Spec.QueryFederation {
+			if name == obj.GetName() && t.Name != obj.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&t)})
+				break
+			}
+		}
+	}
+	return requests
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observability
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/internal/usage"
+)
+
+// usageRetryInterval is how long to wait before querying the usage of a tenant again after a failure.
+const usageRetryInterval = 5 * time.Minute
+
+// updateRecommendations recommends limits for the tenant from its usage once
+// the usage interval has passed since they were last recommended. current are
+// the limits configured for the tenant, without temporary limits.
+func (r *TenantReconciler) updateRecommendations(ctx context.Context, tenant *observabilityv1alpha1.Tenant, current *observabilityv1alpha1.LimitSpec, now time.Time, result *ctrl.Result) error {
+	spec := r.Config.Spec.Usage
+	interval := usage.Interval(spec)
+	if rec := tenant.Status.Recommendations; rec != nil {
+		if due := rec.UpdatedAt.Add(interval); now.Before(due) {
+			requeueAfter(result, due.Sub(now))
+			return nil
+		}
+	}
+
+	client, err := usage.NewClient(spec)
+	if err != nil {
+		return err
+	}
+	u, err := client.TenantUsage(ctx, tenant.GetTenantID(), now)
+	if err != nil {
+		return err
+	}
+
+	tenant.Status.Recommendations = &observabilityv1alpha1.Recommendations{
+		UpdatedAt:        metav1.NewTime(now),
+		Limits:           usage.Recommend(u, current, usage.Headroom(spec)),
+		DiscardedSamples: u.DiscardedSamples,
+	}
+	requeueAfter(result, interval)
+	return nil
+}
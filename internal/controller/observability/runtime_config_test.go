@@ -0,0 +1,179 @@
+package observability
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/internal/overrides"
+)
+
+var _ = Describe("Runtime configuration", func() {
+	ctx := context.Background()
+
+	var r *TenantReconciler
+
+	BeforeEach(func() {
+		config := &observabilityv1alpha1.Config{}
+		readFixture("config.yaml", config)
+		r = &TenantReconciler{
+			Config:          config,
+			mimirConfigData: mimirConfigData{Overrides: map[string]observabilityv1alpha1.MimirLimits{}},
+			lokiConfigData:  lokiConfigData{Overrides: map[string]observabilityv1alpha1.LokiLimits{}},
+			tempoConfigData: tempoConfigData{Overrides: map[string]observabilityv1alpha1.TempoLimits{}},
+		}
+	})
+
+	// render returns the Mimir, Loki and Tempo runtime configurations rendered
+	// for the tenants.
+	render := func(tenants ...observabilityv1alpha1.Tenant) (mimir, loki, tempo []byte) {
+		for i := range tenants {
+			r.updateMimirConfigmapData(ctx, &tenants[i], tenants[i].Spec.Limits)
+			r.updateLokiConfigmapData(ctx, &tenants[i], tenants[i].Spec.Limits)
+			r.updateTempoConfigmapData(ctx, &tenants[i], tenants[i].Spec.Limits)
+		}
+		var err error
+		mimir, _, err = r.renderRuntimeConfig(ctx, r.mimirConfigData, "", nil, overrides.MimirSchema)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		loki, _, err = r.renderRuntimeConfig(ctx, r.lokiConfigData, "", nil, overrides.LokiSchema)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		tempo, _, err = r.renderRuntimeConfig(ctx, r.tempoConfigData, "", nil, overrides.TempoSchema)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		return mimir, loki, tempo
+	}
+
+	It("renders the fixture tenants as the backends parse them", func() {
+		var tenants []observabilityv1alpha1.Tenant
+		data, err := os.ReadFile(filepath.Join("testdata", "runtime-config", "tenants.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		for _, doc := range strings.Split(string(data), "\n---\n") {
+			tenant := observabilityv1alpha1.Tenant{}
+			Expect(yaml.Unmarshal([]byte(doc), &tenant)).To(Succeed())
+			tenants = append(tenants, tenant)
+		}
+
+		mimir, loki, tempo := render(tenants...)
+		expectRendered("mimir.yaml", mimir)
+		expectRendered("loki.yaml", loki)
+		expectRendered("tempo.yaml", tempo)
+
+		Expect(string(mimir)).To(ContainSubstring("Team_B:"))
+		Expect(string(mimir)).To(ContainSubstring("multi_kv_config:"))
+		Expect(string(loki)).To(ContainSubstring("configs:"))
+	})
+
+	It("renders every modeled limit as the backends parse it", func() {
+		tenant := observabilityv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+			Spec: observabilityv1alpha1.TenantSpec{
+				Limits: &observabilityv1alpha1.LimitSpec{
+					Mimir: &observabilityv1alpha1.MimirLimits{},
+					Loki:  &observabilityv1alpha1.LokiLimits{},
+					Tempo: &observabilityv1alpha1.TempoLimits{},
+				},
+			},
+		}
+		fill(reflect.ValueOf(tenant.Spec.Limits.Mimir).Elem())
+		fill(reflect.ValueOf(tenant.Spec.Limits.Loki).Elem())
+		fill(reflect.ValueOf(tenant.Spec.Limits.Tempo).Elem())
+		// Mimir parses the networks as a comma-separated list of CIDRs.
+		cidrs := "10.0.0.0/8,192.168.0.0/16"
+		tenant.Spec.Limits.Mimir.AlertmanagerReceiversBlockCIDRNetworks = &cidrs
+
+		mimir, loki, tempo := render(tenant)
+		expectRendered("mimir-all-limits.yaml", mimir)
+		expectRendered("loki-all-limits.yaml", loki)
+		expectRendered("tempo-all-limits.yaml", tempo)
+	})
+})
+
+// updateRendered makes expectRendered rewrite the committed runtime
+// configurations rather than compare with them.
+var updateRendered = flag.Bool("update-rendered", false, "Rewrite the rendered runtime configurations in testdata/runtime-config/rendered.")
+
+// expectRendered compares a rendered runtime configuration with the one
+// committed under the given name in testdata/runtime-config/rendered. The
+// modules in hack/limitgen/upstream load the committed ones with the runtime
+// configuration types of the pinned backend versions, so a rendered runtime
+// configuration is only accepted once the backends parse it.
+func expectRendered(name string, data []byte) {
+	path := filepath.Join("testdata", "runtime-config", "rendered", name)
+	if *updateRendered {
+		ExpectWithOffset(1, os.WriteFile(path, data, 0o644)).To(Succeed())
+		return
+	}
+	committed, err := os.ReadFile(path)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	ExpectWithOffset(1, string(data)).To(Equal(string(committed)), "%s is out of date, run make update-rendered-runtime-configs and make verify-runtime-configs", path)
+}
+
+// readFixture decodes the runtime configuration fixture with the given name
+// into obj.
+func readFixture(name string, obj interface{}) {
+	data, err := os.ReadFile(filepath.Join("testdata", "runtime-config", name))
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	ExpectWithOffset(1, yaml.Unmarshal(data, obj)).To(Succeed())
+}
+
+// fixtureValues are the values of the types whose values are restricted.
+var fixtureValues = map[reflect.Type]interface{}{
+	reflect.TypeOf(observabilityv1alpha1.RelabelAction("")):    observabilityv1alpha1.RelabelActionReplace,
+	reflect.TypeOf(observabilityv1alpha1.MatchType("")):        observabilityv1alpha1.Strict,
+	reflect.TypeOf(observabilityv1alpha1.BlockedQueryType("")): observabilityv1alpha1.BlockedQueryTypeFilter,
+	reflect.TypeOf(metav1.Duration{}):                          metav1.Duration{Duration: 90 * time.Second},
+}
+
+// fill sets every field below v to a value, leaving the passthrough maps
+// empty as they hold no modeled fields.
+func fill(v reflect.Value) {
+	if value, ok := fixtureValues[v.Type()]; ok {
+		v.Set(reflect.ValueOf(value))
+		return
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.Type().Elem() == reflect.TypeOf(observabilityv1alpha1.WrappedMap{}) {
+			return
+		}
+		v.Set(reflect.New(v.Type().Elem()))
+		fill(v.Elem())
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(observabilityv1alpha1.WrappedMap{}) {
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fill(v.Field(i))
+			}
+		}
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fill(v.Index(0))
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+		elem := reflect.New(v.Type().Elem()).Elem()
+		fill(elem)
+		v.SetMapIndex(reflect.ValueOf("key").Convert(v.Type().Key()), elem)
+	case reflect.String:
+		v.SetString("value")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float64:
+		v.SetFloat(1.5)
+	}
+}
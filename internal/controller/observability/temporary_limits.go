@@ -0,0 +1,99 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observability
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/internal/limits"
+)
+
+// maxTemporaryLimitHistory is how many temporary limits are kept in the status
+// of a tenant once they have been removed from its spec.
+const maxTemporaryLimitHistory = 10
+
+// recordTemporaryLimits updates the history of the temporary limits of the tenant.
+func recordTemporaryLimits(tenant *observabilityv1alpha1.Tenant, now time.Time) {
+	ts := metav1.NewTime(now)
+	history := tenant.Status.TemporaryLimits
+	inSpec := map[string]bool{}
+
+	for _, t := range tenant.Spec.TemporaryLimits {
+		inSpec[t.Name] = true
+
+		i := temporaryLimitStatusIndex(history, t.Name)
+		if i < 0 {
+			history = append(history, observabilityv1alpha1.TemporaryLimitStatus{Name: t.Name})
+			i = len(history) - 1
+		}
+		status := &history[i]
+
+		state := limits.TemporaryState(t, now)
+		switch state {
+		case observabilityv1alpha1.TemporaryLimitActive:
+			// Reset the history of limits that were reused for a new period.
+			if status.ActivatedAt == nil || status.DeactivatedAt != nil {
+				status.ActivatedAt = &ts
+				status.DeactivatedAt = nil
+			}
+		case observabilityv1alpha1.TemporaryLimitExpired:
+			if status.ActivatedAt != nil && status.DeactivatedAt == nil {
+				status.DeactivatedAt = &ts
+			}
+		}
+		status.State = state
+	}
+
+	removed := 0
+	for i := range history {
+		status := &history[i]
+		if inSpec[status.Name] {
+			continue
+		}
+		removed++
+		if status.State == observabilityv1alpha1.TemporaryLimitPending || status.State == observabilityv1alpha1.TemporaryLimitActive {
+			status.State = observabilityv1alpha1.TemporaryLimitRemoved
+			if status.ActivatedAt != nil {
+				status.DeactivatedAt = &ts
+			}
+		}
+	}
+
+	// Drop the oldest removed temporary limits beyond the history limit.
+	for i := 0; removed > maxTemporaryLimitHistory && i < len(history); {
+		if inSpec[history[i].Name] {
+			i++
+			continue
+		}
+		history = append(history[:i], history[i+1:]...)
+		removed--
+	}
+
+	tenant.Status.TemporaryLimits = history
+}
+
+func temporaryLimitStatusIndex(history []observabilityv1alpha1.TemporaryLimitStatus, name string) int {
+	for i := range history {
+		if history[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
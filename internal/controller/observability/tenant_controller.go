@@ -18,17 +18,23 @@ package observability
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -38,20 +44,36 @@ import (
 	// mimir "github.com/grafana/mimir/pkg/util/validation"
 
 	"github.com/go-logr/logr"
+	"github.com/pluralsh/controller-reconcile-helper/pkg/conditions"
+	"github.com/pluralsh/controller-reconcile-helper/pkg/patch"
 	reconcilehelper "github.com/pluralsh/controller-reconcile-helper/pkg/reconcile-helper/core"
+	crhelperTypes "github.com/pluralsh/controller-reconcile-helper/pkg/types"
 	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
 	"github.com/traceshield/trace-shield-controller/clients/keto"
+	"github.com/traceshield/trace-shield-controller/internal/limits"
+	"github.com/traceshield/trace-shield-controller/internal/overrides"
 )
 
 // TenantReconciler reconciles a Tenant object
 type TenantReconciler struct {
 	client.Client
-	KetoClient      *keto.KetoGrpcClient
+	Authorizer      keto.TenantAuthorizer
 	Scheme          *runtime.Scheme
+	Recorder        record.EventRecorder
 	Config          *observabilityv1alpha1.Config
 	mimirConfigData mimirConfigData
 	lokiConfigData  lokiConfigData
 	tempoConfigData tempoConfigData
+	// The runtime configurations as they were read, to merge the keys the
+	// controller does not own back into them.
+	mimirRuntimeConfig string
+	lokiRuntimeConfig  string
+	tempoRuntimeConfig string
+	// The keys merged from the extra limits into the runtime configurations
+	// as they were read, which the controller owns.
+	mimirExtraKeys overrides.ExtraKeys
+	lokiExtraKeys  overrides.ExtraKeys
+	tempoExtraKeys overrides.ExtraKeys
 }
 
 type mimirConfigData struct {
@@ -70,9 +92,14 @@ type tempoConfigData struct {
 
 const (
 	tenantFinalizerName = "tenants.observability.traceshield.io/finalizer"
+
+	// ketoRetryInterval is how long to wait before retrying to register a tenant in Keto.
+	ketoRetryInterval = 30 * time.Second
 )
 
 //+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 //+kubebuilder:rbac:groups=observability.traceshield.io,resources=configs,verbs=get;list;watch
 //+kubebuilder:rbac:groups=observability.traceshield.io,resources=tenants,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=observability.traceshield.io,resources=tenants/status,verbs=get;update;patch
@@ -153,7 +180,7 @@ func (r *TenantReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		// The object is being deleted
 		if controllerutil.ContainsFinalizer(tenantInstance, tenantFinalizerName) {
 			// our finalizer is present, so lets handle any external dependency
-			if err := r.deleteTenantResources(ctx, tenantInstance); err != nil {
+			if err := r.deleteTenantResources(ctx, tenantInstance, log); err != nil {
 				// if fail to delete the external dependency here, return with error
 				// so that it can be retried
 				log.Error(err, "unable to delete tenant resources", "name", tenantInstance.Name)
@@ -173,28 +200,215 @@ func (r *TenantReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return ctrl.Result{}, nil
 	}
 
-	if err := r.KetoClient.CreateObservabilityTenantInKetoIfNotExists(ctx, tenantInstance.Name); err != nil {
-		log.Error(err, "unable to create tenant in keto")
+	patchHelper, err := patch.NewHelper(tenantInstance, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	defer func() {
+		if err := patchHelper.Patch(ctx, tenantInstance); err != nil {
+			log.Error(err, "unable to patch tenant status", "name", tenantInstance.Name)
+		}
+	}()
+
+	result := ctrl.Result{}
+
+	tenantList := &observabilityv1alpha1.TenantList{}
+	if err := r.List(ctx, tenantList); err != nil {
+		log.Error(err, "unable to list tenants")
+		return ctrl.Result{}, err
+	}
+	federation := resolveQueryFederation(tenantInstance, tenantList.Items)
+	effectiveLimits, err := resolveLimits(tenantInstance, tenantList.Items)
+	if err != nil {
+		log.Error(err, "unable to resolve tenant limits", "name", tenantInstance.Name)
+		return ctrl.Result{}, err
+	}
+	configuredLimits := effectiveLimits
+	now := time.Now()
+	// Recommendations are advisory, so an unreachable query API marks them
+	// unavailable and is retried later instead of failing the reconcile.
+	if r.Config.Spec.Usage != nil {
+		if err := r.updateRecommendations(ctx, tenantInstance, configuredLimits, now, &result); err != nil {
+			log.Error(err, "unable to query tenant usage", "name", tenantInstance.Name)
+			conditions.MarkFalse(tenantInstance, observabilityv1alpha1.RecommendationsReadyCondition, observabilityv1alpha1.UsageUnavailableReason, crhelperTypes.ConditionSeverityWarning, "%s", err.Error())
+			requeueAfter(&result, usageRetryInterval)
+		} else {
+			conditions.MarkTrue(tenantInstance, observabilityv1alpha1.RecommendationsReadyCondition)
+		}
+	} else {
+		tenantInstance.Status.Recommendations = nil
+		conditions.Delete(tenantInstance, observabilityv1alpha1.RecommendationsReadyCondition)
+	}
+	effectiveLimits, wait, err := r.autoscaleLimits(tenantInstance, effectiveLimits, now)
+	if err != nil {
+		log.Error(err, "unable to autoscale tenant limits", "name", tenantInstance.Name)
 		return ctrl.Result{}, err
 	}
+	if wait > 0 {
+		requeueAfter(&result, wait)
+	}
+	effectiveLimits, next, err := limits.ApplyTemporary(effectiveLimits, tenantInstance.Spec.TemporaryLimits, now)
+	if err != nil {
+		log.Error(err, "unable to apply temporary limits", "name", tenantInstance.Name)
+		return ctrl.Result{}, err
+	}
+	recordTemporaryLimits(tenantInstance, now)
+	if !next.IsZero() {
+		requeueAfter(&result, next.Sub(now))
+	}
+	if tenantInstance.Spec.Suspended {
+		effectiveLimits = limits.Suspend(effectiveLimits, tenantInstance.Spec.BlockQueriesWhenSuspended)
+		conditions.MarkTrue(tenantInstance, observabilityv1alpha1.SuspendedCondition)
+	} else {
+		conditions.Delete(tenantInstance, observabilityv1alpha1.SuspendedCondition)
+	}
+	if err := resolveBudget(tenantInstance, tenantList.Items); err != nil {
+		log.Error(err, "unable to resolve tenant budget", "name", tenantInstance.Name)
+		return ctrl.Result{}, err
+	}
+	// The parent relation is only written to Keto once the hierarchy resolves,
+	// so that access is never inherited through a cycle.
+	var parent string
+	if conditions.IsTrue(tenantInstance, observabilityv1alpha1.HierarchyReadyCondition) {
+		parent = tenantID(tenantInstance.Spec.Parent, tenantList.Items)
+	}
+
+	// Keto failures are only reported on the tenant so that limits are still
+	// rendered while Keto is unavailable.
+	if r.ketoEnabled() {
+		if err := r.syncKeto(ctx, tenantInstance, federation, parent); err != nil {
+			log.Error(err, "unable to sync tenant to keto")
+			conditions.MarkFalse(tenantInstance, observabilityv1alpha1.KetoReadyCondition, observabilityv1alpha1.KetoUnavailableReason, crhelperTypes.ConditionSeverityError, "%s", err.Error())
+			requeueAfter(&result, ketoRetryInterval)
+		} else {
+			conditions.MarkTrue(tenantInstance, observabilityv1alpha1.KetoReadyCondition)
+		}
+	} else {
+		conditions.Delete(tenantInstance, observabilityv1alpha1.KetoReadyCondition)
+	}
+
+	if r.Config.Spec.QueryFederation != nil {
+		if err := r.updateQueryFederationConfigMap(ctx, tenantList.Items, log); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if r.Config.Spec.Gateway != nil {
+		if err := r.reconcileGatewayCredentials(ctx, tenantInstance, log); err != nil {
+			log.Error(err, "unable to reconcile gateway credentials", "name", tenantInstance.Name)
+			return ctrl.Result{}, err
+		}
+	}
+
+	// The runtime configurations are not written while they have keys the
+	// controller does not own if the Config refuses to drop them.
+	if r.unownedKeysPolicy() == observabilityv1alpha1.UnownedKeysRefuse {
+		unowned, err := r.unownedKeys(tenantList.Items)
+		if err != nil {
+			log.Error(err, "unable to parse runtime configurations")
+			return ctrl.Result{}, err
+		}
+		if len(unowned) > 0 {
+			conditions.MarkFalse(tenantInstance, observabilityv1alpha1.RuntimeConfigReadyCondition, observabilityv1alpha1.UnownedKeysReason, crhelperTypes.ConditionSeverityError, "refusing to drop keys the controller does not own: %s", strings.Join(unowned, ", "))
+		} else {
+			conditions.MarkTrue(tenantInstance, observabilityv1alpha1.RuntimeConfigReadyCondition)
+		}
+	} else {
+		conditions.Delete(tenantInstance, observabilityv1alpha1.RuntimeConfigReadyCondition)
+	}
 
 	if r.Config.Spec.Mimir != nil {
-		r.updateMimirConfigmapData(ctx, tenantInstance)
+		r.updateMimirConfigmapData(ctx, tenantInstance, effectiveLimits)
 	}
 
 	if r.Config.Spec.Loki != nil {
-		r.updateLokiConfigmapData(ctx, tenantInstance)
+		r.updateLokiConfigmapData(ctx, tenantInstance, effectiveLimits)
 	}
 
 	if r.Config.Spec.Tempo != nil {
-		r.updateTempoConfigmapData(ctx, tenantInstance)
+		r.updateTempoConfigmapData(ctx, tenantInstance, effectiveLimits)
+	}
+
+	return result, nil
+}
+
+// syncKeto registers the tenant in Keto and syncs its parent and query
+// federation, given as tenant IDs.
+func (r *TenantReconciler) syncKeto(ctx context.Context, tenant *observabilityv1alpha1.Tenant, federation []string, parent string) error {
+	if err := r.Authorizer.RegisterTenant(ctx, tenant.GetTenantID()); err != nil {
+		return err
+	}
+	if err := r.Authorizer.SyncAccessGrants(ctx, tenant.GetTenantID(), keto.TenantParentRelation, parentSubjects(parent)); err != nil {
+		return err
+	}
+	return r.Authorizer.SyncQueryFederation(ctx, tenant.GetTenantID(), federation)
+}
+
+// parentSubjects returns the subjects related to a tenant by its parent relation.
+func parentSubjects(parent string) []keto.Subject {
+	if parent == "" {
+		return nil
+	}
+	return []keto.Subject{keto.NewSubjectSet(keto.ObservabilityTenantNamespace, parent, "")}
+}
+
+// ketoEnabled returns whether tenant permissions should be managed in Keto,
+// which they are not without a Keto client.
+func (r *TenantReconciler) ketoEnabled() bool {
+	if r.Authorizer == nil {
+		return false
+	}
+	if _, ok := r.Authorizer.(keto.NoopAuthorizer); ok {
+		return false
+	}
+	return ketoEnabledInConfig(r.Config)
+}
+
+// ketoEnabledInConfig returns whether the Config enables the management of
+// tenant permissions in Keto, which it does unless told otherwise.
+func ketoEnabledInConfig(config *observabilityv1alpha1.Config) bool {
+	if config.Spec.Keto == nil || config.Spec.Keto.Enabled == nil {
+		return true
+	}
+	return *config.Spec.Keto.Enabled
+}
+
+// KetoEnabled returns whether the Config enables the management of tenant
+// permissions in Keto, which it does while there is no Config.
+func KetoEnabled(ctx context.Context, c client.Reader) (bool, error) {
+	config := &observabilityv1alpha1.Config{}
+	if err := c.Get(ctx, types.NamespacedName{Name: "config"}, config); err != nil {
+		if apierrs.IsNotFound(err) {
+			return true, nil
+		}
+		return false, fmt.Errorf("unable to fetch Observability Config: %w", err)
 	}
+	return ketoEnabledInConfig(config), nil
+}
 
-	return ctrl.Result{}, nil
+// KetoReadyzCheck returns a readiness check that reports the readiness of
+// Keto through checker, unless the Config disables Keto, in which case the
+// controller does not need it to be ready.
+func KetoReadyzCheck(c client.Reader, checker keto.ReadinessChecker) healthz.Checker {
+	return func(req *http.Request) error {
+		enabled, err := KetoEnabled(req.Context(), c)
+		if err != nil || !enabled {
+			return err
+		}
+		return checker.ReadyzCheck(req)
+	}
 }
 
 func (r *TenantReconciler) updateMimirConfigmap(ctx context.Context, log logr.Logger) error {
-	tenDat, _ := yaml.Marshal(r.mimirConfigData)
+	tenDat, inlined, err := r.renderRuntimeConfig(ctx, r.mimirConfigData, r.mimirRuntimeConfig, r.mimirExtraKeys, overrides.MimirSchema)
+	if err != nil {
+		log.Error(err, "unable to render Mimir runtime configuration")
+		return err
+	}
+	annotations, err := extraKeysAnnotations(inlined)
+	if err != nil {
+		return err
+	}
 
 	configmapData := map[string]string{
 		r.Config.Spec.Mimir.ConfigMap.Key: string(tenDat),
@@ -202,8 +416,9 @@ func (r *TenantReconciler) updateMimirConfigmap(ctx context.Context, log logr.Lo
 
 	mimirConfigMap := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      r.Config.Spec.Mimir.ConfigMap.Name,
-			Namespace: r.Config.Spec.Mimir.ConfigMap.Namespace,
+			Name:        r.Config.Spec.Mimir.ConfigMap.Name,
+			Namespace:   r.Config.Spec.Mimir.ConfigMap.Namespace,
+			Annotations: annotations,
 		},
 		Data: configmapData,
 	}
@@ -216,7 +431,15 @@ func (r *TenantReconciler) updateMimirConfigmap(ctx context.Context, log logr.Lo
 }
 
 func (r *TenantReconciler) updateLokiConfigmap(ctx context.Context, log logr.Logger) error {
-	tenDat, _ := yaml.Marshal(r.lokiConfigData)
+	tenDat, inlined, err := r.renderRuntimeConfig(ctx, r.lokiConfigData, r.lokiRuntimeConfig, r.lokiExtraKeys, overrides.LokiSchema)
+	if err != nil {
+		log.Error(err, "unable to render Loki runtime configuration")
+		return err
+	}
+	annotations, err := extraKeysAnnotations(inlined)
+	if err != nil {
+		return err
+	}
 
 	configmapData := map[string]string{
 		r.Config.Spec.Loki.ConfigMap.Key: string(tenDat),
@@ -224,8 +447,9 @@ func (r *TenantReconciler) updateLokiConfigmap(ctx context.Context, log logr.Log
 
 	lokiConfigMap := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      r.Config.Spec.Loki.ConfigMap.Name,
-			Namespace: r.Config.Spec.Loki.ConfigMap.Namespace,
+			Name:        r.Config.Spec.Loki.ConfigMap.Name,
+			Namespace:   r.Config.Spec.Loki.ConfigMap.Namespace,
+			Annotations: annotations,
 		},
 		Data: configmapData,
 	}
@@ -238,7 +462,15 @@ func (r *TenantReconciler) updateLokiConfigmap(ctx context.Context, log logr.Log
 }
 
 func (r *TenantReconciler) updateTempoConfigmap(ctx context.Context, log logr.Logger) error {
-	tenDat, _ := yaml.Marshal(r.tempoConfigData)
+	tenDat, inlined, err := r.renderRuntimeConfig(ctx, r.tempoConfigData, r.tempoRuntimeConfig, r.tempoExtraKeys, overrides.TempoSchema)
+	if err != nil {
+		log.Error(err, "unable to render Tempo runtime configuration")
+		return err
+	}
+	annotations, err := extraKeysAnnotations(inlined)
+	if err != nil {
+		return err
+	}
 
 	configmapData := map[string]string{
 		r.Config.Spec.Tempo.ConfigMap.Key: string(tenDat),
@@ -246,8 +478,9 @@ func (r *TenantReconciler) updateTempoConfigmap(ctx context.Context, log logr.Lo
 
 	tempoConfigMap := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      r.Config.Spec.Tempo.ConfigMap.Name,
-			Namespace: r.Config.Spec.Tempo.ConfigMap.Namespace,
+			Name:        r.Config.Spec.Tempo.ConfigMap.Name,
+			Namespace:   r.Config.Spec.Tempo.ConfigMap.Namespace,
+			Annotations: annotations,
 		},
 		Data: configmapData,
 	}
@@ -259,16 +492,41 @@ func (r *TenantReconciler) updateTempoConfigmap(ctx context.Context, log logr.Lo
 	return nil
 }
 
-func (r *TenantReconciler) deleteTenantResources(ctx context.Context, tenant *observabilityv1alpha1.Tenant) error {
-	delete(r.mimirConfigData.Overrides, tenant.Name)
-	delete(r.lokiConfigData.Overrides, tenant.Name)
-	delete(r.tempoConfigData.Overrides, tenant.Name)
-	return r.KetoClient.DeleteObservabilityTenantInKeto(ctx, tenant.Name)
+func (r *TenantReconciler) deleteTenantResources(ctx context.Context, tenant *observabilityv1alpha1.Tenant, log logr.Logger) error {
+	delete(r.mimirConfigData.Overrides, tenant.GetTenantID())
+	delete(r.lokiConfigData.Overrides, tenant.GetTenantID())
+	delete(r.tempoConfigData.Overrides, tenant.GetTenantID())
+	if r.Config.Spec.Gateway != nil {
+		if err := r.deleteGatewayCredentials(ctx, tenant, log); err != nil {
+			return err
+		}
+	}
+	if r.Config.Spec.QueryFederation != nil {
+		tenantList := &observabilityv1alpha1.TenantList{}
+		if err := r.List(ctx, tenantList); err != nil {
+			return err
+		}
+		if err := r.updateQueryFederationConfigMap(ctx, tenantList.Items, log); err != nil {
+			return err
+		}
+	}
+	if !r.ketoEnabled() {
+		return nil
+	}
+	if err := r.Authorizer.SyncQueryFederation(ctx, tenant.GetTenantID(), nil); err != nil {
+		return err
+	}
+	if err := r.Authorizer.SyncAccessGrants(ctx, tenant.GetTenantID(), keto.TenantParentRelation, nil); err != nil {
+		return err
+	}
+	return r.Authorizer.UnregisterTenant(ctx, tenant.GetTenantID())
 }
 
-func (r *TenantReconciler) updateMimirConfigmapData(ctx context.Context, tenant *observabilityv1alpha1.Tenant) {
-	if tenant.Spec.Limits != nil && tenant.Spec.Limits.Mimir != nil {
-		r.mimirConfigData.Overrides[tenant.Name] = *tenant.Spec.Limits.Mimir
+func (r *TenantReconciler) updateMimirConfigmapData(ctx context.Context, tenant *observabilityv1alpha1.Tenant, spec *observabilityv1alpha1.LimitSpec) {
+	if spec != nil && spec.Mimir != nil {
+		r.mimirConfigData.Overrides[tenant.GetTenantID()] = *spec.Mimir
+	} else {
+		delete(r.mimirConfigData.Overrides, tenant.GetTenantID())
 	}
 	// update the global mimir config
 	if r.Config.Spec.Mimir.Config != nil {
@@ -278,9 +536,11 @@ func (r *TenantReconciler) updateMimirConfigmapData(ctx context.Context, tenant
 	}
 }
 
-func (r *TenantReconciler) updateLokiConfigmapData(ctx context.Context, tenant *observabilityv1alpha1.Tenant) {
-	if tenant.Spec.Limits != nil && tenant.Spec.Limits.Loki != nil {
-		r.lokiConfigData.Overrides[tenant.Name] = *tenant.Spec.Limits.Loki
+func (r *TenantReconciler) updateLokiConfigmapData(ctx context.Context, tenant *observabilityv1alpha1.Tenant, spec *observabilityv1alpha1.LimitSpec) {
+	if spec != nil && spec.Loki != nil {
+		r.lokiConfigData.Overrides[tenant.GetTenantID()] = *spec.Loki
+	} else {
+		delete(r.lokiConfigData.Overrides, tenant.GetTenantID())
 	}
 	// update the global loki config
 	if r.Config.Spec.Loki.Config != nil {
@@ -290,14 +550,17 @@ func (r *TenantReconciler) updateLokiConfigmapData(ctx context.Context, tenant *
 	}
 }
 
-func (r *TenantReconciler) updateTempoConfigmapData(ctx context.Context, tenant *observabilityv1alpha1.Tenant) {
-	if tenant.Spec.Limits != nil && tenant.Spec.Limits.Tempo != nil {
-		r.tempoConfigData.Overrides[tenant.Name] = *tenant.Spec.Limits.Tempo
+func (r *TenantReconciler) updateTempoConfigmapData(ctx context.Context, tenant *observabilityv1alpha1.Tenant, spec *observabilityv1alpha1.LimitSpec) {
+	if spec != nil && spec.Tempo != nil {
+		r.tempoConfigData.Overrides[tenant.GetTenantID()] = *spec.Tempo
+	} else {
+		delete(r.tempoConfigData.Overrides, tenant.GetTenantID())
 	}
 }
 
 func (r *TenantReconciler) getMimirConfigMap(ctx context.Context) error {
 	existingConfigmap := &corev1.ConfigMap{}
+	r.mimirRuntimeConfig = ""
 
 	currentTenantData := mimirConfigData{}
 
@@ -311,22 +574,30 @@ func (r *TenantReconciler) getMimirConfigMap(ctx context.Context) error {
 		// log.Error(err, "unable to fetch Tenant")
 		// return ctrl.Result{}, ignoreNotFound(err)
 	}
+	if r.mimirExtraKeys, err = extraKeys(existingConfigmap); err != nil {
+		return err
+	}
 
 	if existingConfigmap.Data != nil {
 		if tenantData, ok := existingConfigmap.Data[r.Config.Spec.Mimir.ConfigMap.Key]; ok {
 			yaml.Unmarshal([]byte(tenantData), &currentTenantData)
+			r.mimirRuntimeConfig = tenantData
 		} else {
 			// TODO: handle error properly
 		}
 	} else {
 		// TODO: handle error properly
 	}
+	if currentTenantData.Overrides == nil {
+		currentTenantData.Overrides = map[string]observabilityv1alpha1.MimirLimits{}
+	}
 	r.mimirConfigData = currentTenantData
 	return nil
 }
 
 func (r *TenantReconciler) getLokiConfigMap(ctx context.Context) error {
 	existingConfigmap := &corev1.ConfigMap{}
+	r.lokiRuntimeConfig = ""
 
 	currentTenantData := lokiConfigData{}
 
@@ -340,22 +611,32 @@ func (r *TenantReconciler) getLokiConfigMap(ctx context.Context) error {
 		// log.Error(err, "unable to fetch Tenant")
 		// return ctrl.Result{}, ignoreNotFound(err)
 	}
+	if r.lokiExtraKeys, err = extraKeys(existingConfigmap); err != nil {
+		return err
+	}
 
 	if existingConfigmap.Data != nil {
 		if tenantData, ok := existingConfigmap.Data[r.Config.Spec.Loki.ConfigMap.Key]; ok {
-			yaml.Unmarshal([]byte(tenantData), &currentTenantData)
+			if data, err := overrides.SplitBlockedQueryTypes([]byte(tenantData)); err == nil {
+				yaml.Unmarshal(data, &currentTenantData)
+			}
+			r.lokiRuntimeConfig = tenantData
 		} else {
 			// TODO: handle error properly
 		}
 	} else {
 		// TODO: handle error properly
 	}
+	if currentTenantData.Overrides == nil {
+		currentTenantData.Overrides = map[string]observabilityv1alpha1.LokiLimits{}
+	}
 	r.lokiConfigData = currentTenantData
 	return nil
 }
 
 func (r *TenantReconciler) getTempoConfigMap(ctx context.Context) error {
 	existingConfigmap := &corev1.ConfigMap{}
+	r.tempoRuntimeConfig = ""
 
 	currentTenantData := tempoConfigData{}
 
@@ -369,16 +650,25 @@ func (r *TenantReconciler) getTempoConfigMap(ctx context.Context) error {
 		// log.Error(err, "unable to fetch Tenant")
 		// return ctrl.Result{}, ignoreNotFound(err)
 	}
+	if r.tempoExtraKeys, err = extraKeys(existingConfigmap); err != nil {
+		return err
+	}
 
 	if existingConfigmap.Data != nil {
 		if tenantData, ok := existingConfigmap.Data[r.Config.Spec.Tempo.ConfigMap.Key]; ok {
-			yaml.Unmarshal([]byte(tenantData), &currentTenantData)
+			if data, err := overrides.RestoreDimensionMappings([]byte(tenantData)); err == nil {
+				yaml.Unmarshal(data, &currentTenantData)
+			}
+			r.tempoRuntimeConfig = tenantData
 		} else {
 			// TODO: handle error properly
 		}
 	} else {
 		// TODO: handle error properly
 	}
+	if currentTenantData.Overrides == nil {
+		currentTenantData.Overrides = map[string]observabilityv1alpha1.TempoLimits{}
+	}
 	r.tempoConfigData = currentTenantData
 	return nil
 }
@@ -387,6 +677,22 @@ func (r *TenantReconciler) getTempoConfigMap(ctx context.Context) error {
 func (r *TenantReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&observabilityv1alpha1.Tenant{}).
+		Owns(&corev1.Secret{}).
+		Watches(
+			&observabilityv1alpha1.Tenant{},
+			handler.EnqueueRequestsFromMapFunc(r.findFederatingTenants),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Watches(
+			&observabilityv1alpha1.Tenant{},
+			handler.EnqueueRequestsFromMapFunc(r.findDescendantTenants),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Watches(
+			&observabilityv1alpha1.Tenant{},
+			handler.EnqueueRequestsFromMapFunc(r.findBudgetTenants),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
 		// WithEventFilter(predicate.Funcs{
 		// 	CreateFunc: func(e event.CreateEvent) bool {
 
@@ -413,7 +719,8 @@ func (r *TenantReconciler) SetupWithManager(mgr ctrl.Manager) error {
 }
 
 func (r *TenantReconciler) findObjectsToReconcile(ctx context.Context, obj client.Object) []reconcile.Request {
-	if err := r.Get(ctx, types.NamespacedName{Name: "config"}, r.Config); err != nil {
+	config := &observabilityv1alpha1.Config{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "config"}, config); err != nil {
 		if apierrs.IsNotFound(err) {
 			// log.Info("Unable to fetch Tenant - skipping", "name", tenantInstance.Name)
 			return []reconcile.Request{}
@@ -425,20 +732,20 @@ func (r *TenantReconciler) findObjectsToReconcile(ctx context.Context, obj clien
 
 		continueRec := false
 
-		if r.Config.Spec.Mimir != nil {
-			if configmap.GetName() == r.Config.Spec.Mimir.ConfigMap.Name && configmap.GetNamespace() == r.Config.Spec.Mimir.ConfigMap.Namespace {
+		if config.Spec.Mimir != nil {
+			if configmap.GetName() == config.Spec.Mimir.ConfigMap.Name && configmap.GetNamespace() == config.Spec.Mimir.ConfigMap.Namespace {
 				continueRec = true
 			}
 		}
 
-		if r.Config.Spec.Loki != nil {
-			if configmap.GetName() == r.Config.Spec.Loki.ConfigMap.Name && configmap.GetNamespace() == r.Config.Spec.Loki.ConfigMap.Namespace {
+		if config.Spec.Loki != nil {
+			if configmap.GetName() == config.Spec.Loki.ConfigMap.Name && configmap.GetNamespace() == config.Spec.Loki.ConfigMap.Namespace {
 				continueRec = true
 			}
 		}
 
-		if r.Config.Spec.Tempo != nil {
-			if configmap.GetName() == r.Config.Spec.Tempo.ConfigMap.Name && configmap.GetNamespace() == r.Config.Spec.Tempo.ConfigMap.Namespace {
+		if config.Spec.Tempo != nil {
+			if configmap.GetName() == config.Spec.Tempo.ConfigMap.Name && configmap.GetNamespace() == config.Spec.Tempo.ConfigMap.Namespace {
 				continueRec = true
 			}
 		}
@@ -484,6 +791,23 @@ func (r *TenantReconciler) findObjectsToReconcile(ctx context.Context, obj clien
 	return []reconcile.Request{}
 }
 
+// requeueAfter makes sure the result requeues no later than after d.
+func requeueAfter(result *ctrl.Result, d time.Duration) {
+	if result.RequeueAfter == 0 || d < result.RequeueAfter {
+		result.RequeueAfter = d
+	}
+}
+
+// tenantID returns the tenant ID of the named Tenant.
+func tenantID(name string, tenants []observabilityv1alpha1.Tenant) string {
+	for i := range tenants {
+		if tenants[i].Name == name {
+			return tenants[i].GetTenantID()
+		}
+	}
+	return name
+}
+
 func ignoreNotFound(err error) error {
 	if apierrs.IsNotFound(err) {
 		return nil
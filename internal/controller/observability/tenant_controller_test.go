@@ -0,0 +1,653 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	rts "github.com/ory/keto/proto/ory/keto/relation_tuples/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/pluralsh/controller-reconcile-helper/pkg/conditions"
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/clients/keto"
+	"github.com/traceshield/trace-shield-controller/internal/gateway"
+)
+
+var _ = Describe("Tenant controller", func() {
+	const (
+		timeout  = 10 * time.Second
+		interval = 250 * time.Millisecond
+	)
+
+	ctx := context.Background()
+
+	BeforeEach(func() {
+		skipWithoutEnvtest()
+		for _, name := range []string{"mimir", "gateway"} {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+			if err := k8sClient.Create(ctx, ns); err != nil && !apierrs.IsAlreadyExists(err) {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		}
+
+		config := &observabilityv1alpha1.Config{
+			ObjectMeta: metav1.ObjectMeta{Name: "config"},
+			Spec: observabilityv1alpha1.ConfigSpec{
+				Mimir: &observabilityv1alpha1.MimirSpec{
+					ConfigMap: observabilityv1alpha1.ConfigMapSelector{
+						Name:      "mimir-runtime",
+						Namespace: "mimir",
+						Key:       "runtime.yaml",
+					},
+				},
+				QueryFederation: &observabilityv1alpha1.QueryFederationSpec{
+					ConfigMap: observabilityv1alpha1.ConfigMapSelector{
+						Name:      "query-federation",
+						Namespace: "gateway",
+						Key:       "federation.yaml",
+					},
+				},
+				Gateway: &observabilityv1alpha1.GatewaySpec{
+					Namespace:      "gateway",
+					CredentialType: observabilityv1alpha1.GatewayCredentialToken,
+					SecretName:     "gateway-tenant-credentials",
+				},
+			},
+		}
+		if err := k8sClient.Create(ctx, config); err != nil && !apierrs.IsAlreadyExists(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	It("registers the tenant in keto and renders its limits", func() {
+		requestRate := float64(100)
+		tenant := &observabilityv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "tenant-a"},
+			Spec: observabilityv1alpha1.TenantSpec{
+				Limits: &observabilityv1alpha1.LimitSpec{
+					Mimir: &observabilityv1alpha1.MimirLimits{RequestRate: &requestRate},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, tenant)).To(Succeed())
+
+		Eventually(func() []*rts.RelationTuple {
+			return ketoServer.Tuples()
+		}, timeout, interval).Should(ContainElement(HaveField("Object", "tenant-a")))
+
+		Eventually(func() (map[string]observabilityv1alpha1.MimirLimits, error) {
+			cm := &corev1.ConfigMap{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "mimir-runtime", Namespace: "mimir"}, cm); err != nil {
+				return nil, err
+			}
+			data := mimirConfigData{}
+			err := yaml.Unmarshal([]byte(cm.Data["runtime.yaml"]), &data)
+			return data.Overrides, err
+		}, timeout, interval).Should(HaveKey("tenant-a"))
+
+		By("deleting the tenant")
+		Expect(k8sClient.Delete(ctx, tenant)).To(Succeed())
+
+		Eventually(func() []*rts.RelationTuple {
+			return ketoServer.Tuples()
+		}, timeout, interval).ShouldNot(ContainElement(HaveField("Object", "tenant-a")))
+	})
+	It("generates and rotates gateway credentials", func() {
+		tenant := &observabilityv1alpha1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "tenant-b"}}
+		Expect(k8sClient.Create(ctx, tenant)).To(Succeed())
+
+		tokens := func() (map[string]string, error) {
+			secret := &corev1.Secret{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "gateway-tenant-credentials", Namespace: "gateway"}, secret); err != nil {
+				return nil, err
+			}
+			out := map[string]string{}
+			err := yaml.Unmarshal(secret.Data[gateway.TokensFileKey], &out)
+			return out, err
+		}
+
+		var token string
+		Eventually(func() (string, error) {
+			secret := &corev1.Secret{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: "tenant-b-gateway-credentials", Namespace: "gateway"}, secret)
+			token = string(secret.Data[gateway.TokenKey])
+			return token, err
+		}, timeout, interval).ShouldNot(BeEmpty())
+		Eventually(tokens, timeout, interval).Should(HaveKeyWithValue(token, "tenant-b"))
+
+		By("rotating the credentials")
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "tenant-b"}, tenant)).To(Succeed())
+		tenant.Annotations = map[string]string{observabilityv1alpha1.RotateCredentialsAnnotation: "1"}
+		Expect(k8sClient.Update(ctx, tenant)).To(Succeed())
+
+		Eventually(tokens, timeout, interval).ShouldNot(HaveKey(token))
+		Eventually(tokens, timeout, interval).Should(ContainElement("tenant-b"))
+
+		By("deleting the tenant")
+		Expect(k8sClient.Delete(ctx, tenant)).To(Succeed())
+		Eventually(tokens, timeout, interval).ShouldNot(ContainElement("tenant-b"))
+	})
+	It("reconciles the query federation of a tenant", func() {
+		federating := &observabilityv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "tenant-c"},
+			Spec: observabilityv1alpha1.TenantSpec{
+				QueryFederation: []string{"tenant-d", "tenant-e"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, federating)).To(Succeed())
+
+		readyReason := func() string {
+			t := &observabilityv1alpha1.Tenant{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "tenant-c"}, t); err != nil {
+				return err.Error()
+			}
+			for _, c := range t.Status.Conditions {
+				if c.Type == observabilityv1alpha1.QueryFederationReadyCondition {
+					return c.Reason
+				}
+			}
+			return ""
+		}
+		Eventually(readyReason, timeout, interval).Should(Equal(observabilityv1alpha1.FederatedTenantNotFoundReason))
+
+		Expect(k8sClient.Create(ctx, &observabilityv1alpha1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "tenant-d"}})).To(Succeed())
+
+		Eventually(func() (map[string]string, error) {
+			cm := &corev1.ConfigMap{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "query-federation", Namespace: "gateway"}, cm); err != nil {
+				return nil, err
+			}
+			out := map[string]string{}
+			err := yaml.Unmarshal([]byte(cm.Data["federation.yaml"]), &out)
+			return out, err
+		}, timeout, interval).Should(HaveKeyWithValue("tenant-c", "tenant-c|tenant-d"))
+
+		Eventually(func() []*rts.RelationTuple {
+			return ketoServer.Tuples()
+		}, timeout, interval).Should(ContainElement(And(
+			HaveField("Object", "tenant-d"),
+			HaveField("Relation", keto.TenantFederatedReadersRelation),
+		)))
+	})
+
+	It("flattens inherited limits and relates children to their parent", func() {
+		orgRate, teamRate := float64(100), float64(50)
+		burst := 1000
+		Expect(k8sClient.Create(ctx, &observabilityv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "org"},
+			Spec: observabilityv1alpha1.TenantSpec{
+				Limits: &observabilityv1alpha1.LimitSpec{
+					Mimir: &observabilityv1alpha1.MimirLimits{RequestRate: &orgRate, IngestionBurstSize: &burst},
+				},
+			},
+		})).To(Succeed())
+		Expect(k8sClient.Create(ctx, &observabilityv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "team"},
+			Spec: observabilityv1alpha1.TenantSpec{
+				Parent: "org",
+				Limits: &observabilityv1alpha1.LimitSpec{
+					Mimir: &observabilityv1alpha1.MimirLimits{RequestRate: &teamRate},
+				},
+			},
+		})).To(Succeed())
+		Expect(k8sClient.Create(ctx, &observabilityv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "service"},
+			Spec:       observabilityv1alpha1.TenantSpec{Parent: "team"},
+		})).To(Succeed())
+
+		Eventually(func() (map[string]observabilityv1alpha1.MimirLimits, error) {
+			cm := &corev1.ConfigMap{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "mimir-runtime", Namespace: "mimir"}, cm); err != nil {
+				return nil, err
+			}
+			data := mimirConfigData{}
+			err := yaml.Unmarshal([]byte(cm.Data["runtime.yaml"]), &data)
+			return data.Overrides, err
+		}, timeout, interval).Should(HaveKeyWithValue("service", observabilityv1alpha1.MimirLimits{
+			RequestRate:        &teamRate,
+			IngestionBurstSize: &burst,
+		}))
+
+		Eventually(func() []*rts.RelationTuple {
+			return ketoServer.Tuples()
+		}, timeout, interval).Should(ContainElement(And(
+			HaveField("Object", "service"),
+			HaveField("Relation", keto.TenantParentRelation),
+		)))
+
+		By("introducing a cycle")
+		org := &observabilityv1alpha1.Tenant{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "org"}, org)).To(Succeed())
+		org.Spec.Parent = "service"
+		Expect(k8sClient.Update(ctx, org)).To(Succeed())
+
+		Eventually(func() string {
+			t := &observabilityv1alpha1.Tenant{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "org"}, t); err != nil {
+				return err.Error()
+			}
+			for _, c := range t.Status.Conditions {
+				if c.Type == observabilityv1alpha1.HierarchyReadyCondition {
+					return c.Reason
+				}
+			}
+			return ""
+		}, timeout, interval).Should(Equal(observabilityv1alpha1.ParentCycleReason))
+	})
+
+	It("splits the budget of a tenant over its children", func() {
+		budget, series := 900, 300
+		Expect(k8sClient.Create(ctx, &observabilityv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "budgeted"},
+			Spec: observabilityv1alpha1.TenantSpec{
+				Budget: &observabilityv1alpha1.BudgetSpec{
+					Mimir: &observabilityv1alpha1.MimirBudget{MaxGlobalSeriesPerUser: &budget},
+				},
+			},
+		})).To(Succeed())
+		Expect(k8sClient.Create(ctx, &observabilityv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "budgeted-a"},
+			Spec: observabilityv1alpha1.TenantSpec{
+				Parent: "budgeted",
+				Limits: &observabilityv1alpha1.LimitSpec{
+					Mimir: &observabilityv1alpha1.MimirLimits{MaxGlobalSeriesPerUser: &series},
+				},
+			},
+		})).To(Succeed())
+		Expect(k8sClient.Create(ctx, &observabilityv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "budgeted-b"},
+			Spec:       observabilityv1alpha1.TenantSpec{Parent: "budgeted"},
+		})).To(Succeed())
+
+		share := 600
+		Eventually(func() (map[string]observabilityv1alpha1.MimirLimits, error) {
+			cm := &corev1.ConfigMap{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "mimir-runtime", Namespace: "mimir"}, cm); err != nil {
+				return nil, err
+			}
+			data := mimirConfigData{}
+			err := yaml.Unmarshal([]byte(cm.Data["runtime.yaml"]), &data)
+			return data.Overrides, err
+		}, timeout, interval).Should(HaveKeyWithValue("budgeted-b", observabilityv1alpha1.MimirLimits{MaxGlobalSeriesPerUser: &share}))
+
+		Eventually(func() bool {
+			t := &observabilityv1alpha1.Tenant{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "budgeted"}, t); err != nil {
+				return false
+			}
+			return conditions.IsTrue(t, observabilityv1alpha1.BudgetReadyCondition)
+		}, timeout, interval).Should(BeTrue())
+	})
+
+	It("uses the tenant ID instead of the name of the Tenant", func() {
+		requestRate := float64(10)
+		Expect(k8sClient.Create(ctx, &observabilityv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "named"},
+			Spec: observabilityv1alpha1.TenantSpec{
+				TenantID: "Named_Tenant",
+				Limits: &observabilityv1alpha1.LimitSpec{
+					Mimir: &observabilityv1alpha1.MimirLimits{RequestRate: &requestRate},
+				},
+			},
+		})).To(Succeed())
+
+		Eventually(func() (map[string]observabilityv1alpha1.MimirLimits, error) {
+			cm := &corev1.ConfigMap{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "mimir-runtime", Namespace: "mimir"}, cm); err != nil {
+				return nil, err
+			}
+			data := mimirConfigData{}
+			err := yaml.Unmarshal([]byte(cm.Data["runtime.yaml"]), &data)
+			return data.Overrides, err
+		}, timeout, interval).Should(And(HaveKey("Named_Tenant"), Not(HaveKey("named"))))
+
+		Eventually(func() []*rts.RelationTuple {
+			return ketoServer.Tuples()
+		}, timeout, interval).Should(ContainElement(HaveField("Object", "Named_Tenant")))
+
+		By("changing the tenant ID")
+		tenant := &observabilityv1alpha1.Tenant{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "named"}, tenant)).To(Succeed())
+		tenant.Spec.TenantID = "renamed"
+		Expect(k8sClient.Update(ctx, tenant)).NotTo(Succeed())
+	})
+
+	It("preserves the keys of the runtime configuration it does not own", func() {
+		runtime := "overrides:\n  legacy:\n    ingestion_rate: 5\n    new_option: 1\nvendor_section:\n  enabled: true\n"
+		cm := &corev1.ConfigMap{}
+		err := k8sClient.Get(ctx, types.NamespacedName{Name: "mimir-runtime", Namespace: "mimir"}, cm)
+		if apierrs.IsNotFound(err) {
+			cm = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "mimir-runtime", Namespace: "mimir"},
+				Data:       map[string]string{"runtime.yaml": runtime},
+			}
+			Expect(k8sClient.Create(ctx, cm)).To(Succeed())
+		} else {
+			Expect(err).NotTo(HaveOccurred())
+			cm.Data = map[string]string{"runtime.yaml": runtime}
+			Expect(k8sClient.Update(ctx, cm)).To(Succeed())
+		}
+
+		requestRate := float64(10)
+		Expect(k8sClient.Create(ctx, &observabilityv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "preserving"},
+			Spec: observabilityv1alpha1.TenantSpec{
+				Limits: &observabilityv1alpha1.LimitSpec{
+					Mimir: &observabilityv1alpha1.MimirLimits{RequestRate: &requestRate},
+				},
+			},
+		})).To(Succeed())
+
+		Eventually(func() (map[string]interface{}, error) {
+			cm := &corev1.ConfigMap{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "mimir-runtime", Namespace: "mimir"}, cm); err != nil {
+				return nil, err
+			}
+			data := map[string]interface{}{}
+			err := yaml.Unmarshal([]byte(cm.Data["runtime.yaml"]), &data)
+			return data, err
+		}, timeout, interval).Should(And(
+			HaveKeyWithValue("vendor_section", HaveKeyWithValue("enabled", true)),
+			HaveKeyWithValue("overrides", And(
+				HaveKey("preserving"),
+				HaveKeyWithValue("legacy", HaveKeyWithValue("new_option", float64(1))),
+			)),
+		))
+	})
+
+	It("merges extra limits into the rendered overrides", func() {
+		requestRate := float64(10)
+		tenant := &observabilityv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "extra"},
+			Spec: observabilityv1alpha1.TenantSpec{
+				Limits: &observabilityv1alpha1.LimitSpec{
+					Mimir: &observabilityv1alpha1.MimirLimits{
+						RequestRate: &requestRate,
+						Extra:       &observabilityv1alpha1.WrappedMap{Object: map[string]interface{}{"new_limit": int64(5)}},
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, tenant)).To(Succeed())
+
+		renderedOverrides := func() (map[string]interface{}, error) {
+			cm := &corev1.ConfigMap{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "mimir-runtime", Namespace: "mimir"}, cm); err != nil {
+				return nil, err
+			}
+			data := struct {
+				Overrides map[string]map[string]interface{} `json:"overrides"`
+			}{}
+			err := yaml.Unmarshal([]byte(cm.Data["runtime.yaml"]), &data)
+			return data.Overrides["extra"], err
+		}
+		Eventually(renderedOverrides, timeout, interval).Should(And(
+			HaveKeyWithValue("request_rate", float64(10)),
+			HaveKeyWithValue("new_limit", float64(5)),
+			Not(HaveKey("extra")),
+		))
+
+		setExtra := func(extra *observabilityv1alpha1.WrappedMap) {
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "extra"}, tenant)).To(Succeed())
+			tenant.Spec.Limits.Mimir.Extra = extra
+			Expect(k8sClient.Update(ctx, tenant)).To(Succeed())
+		}
+		withNewLimit := &observabilityv1alpha1.WrappedMap{Object: map[string]interface{}{"new_limit": int64(5)}}
+
+		By("removing a key from the extra limits")
+		setExtra(&observabilityv1alpha1.WrappedMap{Object: map[string]interface{}{}})
+		Eventually(renderedOverrides, timeout, interval).ShouldNot(HaveKey("new_limit"))
+
+		By("removing the extra limits")
+		setExtra(withNewLimit)
+		Eventually(renderedOverrides, timeout, interval).Should(HaveKey("new_limit"))
+		setExtra(nil)
+		Eventually(renderedOverrides, timeout, interval).Should(And(
+			HaveKeyWithValue("request_rate", float64(10)),
+			Not(HaveKey("new_limit")),
+		))
+
+		By("removing the extra limits when unowned keys are refused")
+		setUnownedKeys := func(policy observabilityv1alpha1.UnownedKeysPolicy) {
+			config := &observabilityv1alpha1.Config{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "config"}, config)).To(Succeed())
+			config.Spec.UnownedKeys = policy
+			Expect(k8sClient.Update(ctx, config)).To(Succeed())
+		}
+		setUnownedKeys(observabilityv1alpha1.UnownedKeysRefuse)
+		defer setUnownedKeys("")
+		setExtra(withNewLimit)
+		Eventually(renderedOverrides, timeout, interval).Should(HaveKey("new_limit"))
+		setExtra(nil)
+		Eventually(renderedOverrides, timeout, interval).ShouldNot(HaveKey("new_limit"))
+	})
+
+	It("suspends and resumes a tenant", func() {
+		ingestionRate := float64(100)
+		tenant := &observabilityv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "suspended"},
+			Spec: observabilityv1alpha1.TenantSpec{
+				Suspended: true,
+				Limits: &observabilityv1alpha1.LimitSpec{
+					Mimir: &observabilityv1alpha1.MimirLimits{IngestionRate: &ingestionRate},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, tenant)).To(Succeed())
+
+		renderedRate := func() (float64, error) {
+			cm := &corev1.ConfigMap{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "mimir-runtime", Namespace: "mimir"}, cm); err != nil {
+				return -1, err
+			}
+			data := mimirConfigData{}
+			if err := yaml.Unmarshal([]byte(cm.Data["runtime.yaml"]), &data); err != nil {
+				return -1, err
+			}
+			limits, ok := data.Overrides["suspended"]
+			if !ok || limits.IngestionRate == nil {
+				return -1, nil
+			}
+			return *limits.IngestionRate, nil
+		}
+		Eventually(renderedRate, timeout, interval).Should(BeZero())
+
+		By("resuming the tenant")
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "suspended"}, tenant)).To(Succeed())
+		tenant.Spec.Suspended = false
+		Expect(k8sClient.Update(ctx, tenant)).To(Succeed())
+		Eventually(renderedRate, timeout, interval).Should(Equal(ingestionRate))
+	})
+
+	It("applies temporary limits until they expire", func() {
+		ingestionRate, bumped := float64(100), float64(1000)
+		Expect(k8sClient.Create(ctx, &observabilityv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "load-tested"},
+			Spec: observabilityv1alpha1.TenantSpec{
+				Limits: &observabilityv1alpha1.LimitSpec{
+					Mimir: &observabilityv1alpha1.MimirLimits{IngestionRate: &ingestionRate},
+				},
+				TemporaryLimits: []observabilityv1alpha1.TemporaryLimitSpec{{
+					Name:   "load-test",
+					Expiry: metav1.NewTime(time.Now().Add(3 * time.Second)),
+					Limits: observabilityv1alpha1.LimitSpec{
+						Mimir: &observabilityv1alpha1.MimirLimits{IngestionRate: &bumped},
+					},
+				}},
+			},
+		})).To(Succeed())
+
+		renderedRate := func() (float64, error) {
+			cm := &corev1.ConfigMap{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "mimir-runtime", Namespace: "mimir"}, cm); err != nil {
+				return -1, err
+			}
+			data := mimirConfigData{}
+			if err := yaml.Unmarshal([]byte(cm.Data["runtime.yaml"]), &data); err != nil {
+				return -1, err
+			}
+			limits, ok := data.Overrides["load-tested"]
+			if !ok || limits.IngestionRate == nil {
+				return -1, nil
+			}
+			return *limits.IngestionRate, nil
+		}
+		Eventually(renderedRate, timeout, interval).Should(Equal(bumped))
+		Eventually(renderedRate, timeout, interval).Should(Equal(ingestionRate))
+
+		Eventually(func() []observabilityv1alpha1.TemporaryLimitStatus {
+			t := &observabilityv1alpha1.Tenant{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "load-tested"}, t); err != nil {
+				return nil
+			}
+			return t.Status.TemporaryLimits
+		}, timeout, interval).Should(ConsistOf(And(
+			HaveField("Name", "load-test"),
+			HaveField("State", observabilityv1alpha1.TemporaryLimitExpired),
+			HaveField("DeactivatedAt", Not(BeNil())),
+		)))
+	})
+
+	It("starts autoscaled limits from their baseline within bounds", func() {
+		ingestionRate := float64(50)
+		tenant := &observabilityv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "autoscaled"},
+			Spec: observabilityv1alpha1.TenantSpec{
+				Limits: &observabilityv1alpha1.LimitSpec{
+					Mimir: &observabilityv1alpha1.MimirLimits{IngestionRate: &ingestionRate},
+				},
+				Autoscale: []observabilityv1alpha1.AutoscaleSpec{{
+					Limit: "mimir.ingestion_rate",
+					Min:   100,
+					Max:   1000,
+				}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, tenant)).To(Succeed())
+
+		renderedRate := func() (float64, error) {
+			cm := &corev1.ConfigMap{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "mimir-runtime", Namespace: "mimir"}, cm); err != nil {
+				return -1, err
+			}
+			data := mimirConfigData{}
+			if err := yaml.Unmarshal([]byte(cm.Data["runtime.yaml"]), &data); err != nil {
+				return -1, err
+			}
+			limits, ok := data.Overrides["autoscaled"]
+			if !ok || limits.IngestionRate == nil {
+				return -1, nil
+			}
+			return *limits.IngestionRate, nil
+		}
+		Eventually(renderedRate, timeout, interval).Should(Equal(float64(100)))
+
+		By("raising the baseline")
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "autoscaled"}, tenant)).To(Succeed())
+		ingestionRate = 500
+		tenant.Spec.Limits.Mimir.IngestionRate = &ingestionRate
+		Expect(k8sClient.Update(ctx, tenant)).To(Succeed())
+		Eventually(renderedRate, timeout, interval).Should(Equal(ingestionRate))
+
+		Eventually(func() []observabilityv1alpha1.AutoscaleStatus {
+			t := &observabilityv1alpha1.Tenant{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "autoscaled"}, t); err != nil {
+				return nil
+			}
+			return t.Status.Autoscale
+		}, timeout, interval).Should(ConsistOf(And(
+			HaveField("Value", ingestionRate),
+			HaveField("History", ConsistOf(And(
+				HaveField("Reason", observabilityv1alpha1.AutoscaleBaselineReason),
+				HaveField("From", float64(100)),
+				HaveField("To", ingestionRate),
+			))),
+		)))
+	})
+})
+
+var _ = Describe("Keto readiness", func() {
+	unready := readinessFunc(func(*http.Request) error { return errors.New("keto is not serving") })
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+
+	It("reports Keto when the Config enables it", func() {
+		check := KetoReadyzCheck(configReader{config: &observabilityv1alpha1.Config{}}, unready)
+		Expect(check(req)).To(MatchError("keto is not serving"))
+	})
+
+	It("reports Keto when there is no Config", func() {
+		check := KetoReadyzCheck(configReader{}, unready)
+		Expect(check(req)).To(MatchError("keto is not serving"))
+	})
+
+	It("ignores Keto when the Config disables it", func() {
+		enabled := false
+		config := &observabilityv1alpha1.Config{
+			Spec: observabilityv1alpha1.ConfigSpec{Keto: &observabilityv1alpha1.KetoSpec{Enabled: &enabled}},
+		}
+		check := KetoReadyzCheck(configReader{config: config}, unready)
+		Expect(check(req)).To(Succeed())
+	})
+})
+
+var _ = Describe("KetoEnabled", func() {
+	ctx := context.Background()
+
+	It("is true without a Config", func() {
+		Expect(KetoEnabled(ctx, configReader{})).To(BeTrue())
+	})
+
+	It("follows the Config", func() {
+		enabled := false
+		config := &observabilityv1alpha1.Config{
+			Spec: observabilityv1alpha1.ConfigSpec{Keto: &observabilityv1alpha1.KetoSpec{Enabled: &enabled}},
+		}
+		Expect(KetoEnabled(ctx, configReader{config: config})).To(BeFalse())
+	})
+})
+
+var _ = Describe("Keto management", func() {
+	It("is enabled with a Keto client unless the Config disables it", func() {
+		r := &TenantReconciler{Authorizer: &keto.KetoHttpClient{}, Config: &observabilityv1alpha1.Config{}}
+		Expect(r.ketoEnabled()).To(BeTrue())
+
+		enabled := false
+		r.Config.Spec.Keto = &observabilityv1alpha1.KetoSpec{Enabled: &enabled}
+		Expect(r.ketoEnabled()).To(BeFalse())
+	})
+
+	It("is disabled without a Keto client", func() {
+		r := &TenantReconciler{Authorizer: keto.NoopAuthorizer{}, Config: &observabilityv1alpha1.Config{}}
+		Expect(r.ketoEnabled()).To(BeFalse())
+	})
+})
+
+// readinessFunc adapts a function to a keto.ReadinessChecker.
+type readinessFunc func(req *http.Request) error
+
+func (f readinessFunc) ReadyzCheck(req *http.Request) error { return f(req) }
+
+// configReader is a client.Reader serving only the given Config.
+type configReader struct {
+	client.Reader
+	config *observabilityv1alpha1.Config
+}
+
+func (c configReader) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	if c.config == nil {
+		return apierrs.NewNotFound(observabilityv1alpha1.GroupVersion.WithResource("configs").GroupResource(), key.Name)
+	}
+	c.config.DeepCopyInto(obj.(*observabilityv1alpha1.Config))
+	return nil
+}
@@ -0,0 +1,297 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observability
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/pluralsh/controller-reconcile-helper/pkg/conditions"
+	"github.com/pluralsh/controller-reconcile-helper/pkg/patch"
+	crhelperTypes "github.com/pluralsh/controller-reconcile-helper/pkg/types"
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/clients/alertmanager"
+	"github.com/traceshield/trace-shield-controller/internal/alerting"
+)
+
+const (
+	tenantAlertmanagerConfigFinalizerName = "tenantalertmanagerconfigs.observability.traceshield.io/finalizer"
+
+	// defaultAlertmanagerSyncInterval is how often configurations are synced when the Config sets no interval.
+	defaultAlertmanagerSyncInterval = 5 * time.Minute
+
+	// alertmanagerRetryInterval is how long to wait before syncing a configuration again after the Alertmanager failed.
+	alertmanagerRetryInterval = 30 * time.Second
+)
+
+// TenantAlertmanagerConfigReconciler reconciles a TenantAlertmanagerConfig object
+type TenantAlertmanagerConfigReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=observability.traceshield.io,resources=tenantalertmanagerconfigs,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=observability.traceshield.io,resources=tenantalertmanagerconfigs/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=observability.traceshield.io,resources=tenantalertmanagerconfigs/finalizers,verbs=update
+
+// Reconcile syncs the configuration of a TenantAlertmanagerConfig to the Mimir Alertmanager of its tenant.
+func (r *TenantAlertmanagerConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	amConfig := &observabilityv1alpha1.TenantAlertmanagerConfig{}
+	if err := r.Get(ctx, req.NamespacedName, amConfig); err != nil {
+		if apierrs.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "unable to fetch TenantAlertmanagerConfig")
+		return ctrl.Result{}, err
+	}
+
+	config := &observabilityv1alpha1.Config{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "config"}, config); err != nil {
+		if apierrs.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "unable to fetch Observability Config")
+		return ctrl.Result{}, err
+	}
+
+	var amClient *alertmanager.Client
+	if config.Spec.Mimir != nil && config.Spec.Mimir.Alertmanager != nil {
+		amClient = alertmanager.NewClient(config.Spec.Mimir.Alertmanager.URL)
+	}
+
+	if !amConfig.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(amConfig, tenantAlertmanagerConfigFinalizerName) {
+			// Only the TenantAlertmanagerConfig that synced the configuration
+			// of the tenant removes it, and only if no other one takes over,
+			// as it may already have synced its own configuration. Configurations
+			// that cannot be removed because the Alertmanager is no longer
+			// configured are left behind.
+			successor, err := r.owner(ctx, amConfig)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if amClient != nil && amConfig.Status.TenantID != "" && successor == nil {
+				if err := amClient.DeleteConfig(ctx, amConfig.Status.TenantID); err != nil {
+					log.Error(err, "unable to delete configuration from the Alertmanager", "name", amConfig.Name)
+					return ctrl.Result{}, err
+				}
+			}
+			controllerutil.RemoveFinalizer(amConfig, tenantAlertmanagerConfigFinalizerName)
+			if err := r.Update(ctx, amConfig); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if amClient == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(amConfig, tenantAlertmanagerConfigFinalizerName) {
+		controllerutil.AddFinalizer(amConfig, tenantAlertmanagerConfigFinalizerName)
+		if err := r.Update(ctx, amConfig); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	patchHelper, err := patch.NewHelper(amConfig, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	defer func() {
+		if err := patchHelper.Patch(ctx, amConfig); err != nil {
+			log.Error(err, "unable to patch tenant alertmanager config status", "name", amConfig.Name)
+		}
+	}()
+
+	tenant := &observabilityv1alpha1.Tenant{}
+	if err := r.Get(ctx, types.NamespacedName{Name: amConfig.Spec.Tenant}, tenant); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		conditions.MarkFalse(amConfig, observabilityv1alpha1.AlertmanagerConfigSyncedCondition, observabilityv1alpha1.TenantNotFoundReason, crhelperTypes.ConditionSeverityError, "tenant %s does not exist", amConfig.Spec.Tenant)
+		return ctrl.Result{}, nil
+	}
+	if !tenant.DeletionTimestamp.IsZero() {
+		conditions.MarkFalse(amConfig, observabilityv1alpha1.AlertmanagerConfigSyncedCondition, observabilityv1alpha1.TenantNotFoundReason, crhelperTypes.ConditionSeverityError, "tenant %s is being deleted", amConfig.Spec.Tenant)
+		return ctrl.Result{}, nil
+	}
+	tenantID := tenant.GetTenantID()
+
+	owner, err := r.owner(ctx, amConfig)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if owner.Namespace != amConfig.Namespace || owner.Name != amConfig.Name {
+		conditions.MarkFalse(amConfig, observabilityv1alpha1.AlertmanagerConfigSyncedCondition, observabilityv1alpha1.AlertmanagerConfigConflictReason, crhelperTypes.ConditionSeverityError, "tenant %s is already configured by TenantAlertmanagerConfig %s/%s", amConfig.Spec.Tenant, owner.Namespace, owner.Name)
+		return ctrl.Result{}, nil
+	}
+
+	if errs := alerting.Validate(amConfig.Spec.Config.Object); len(errs) > 0 {
+		conditions.MarkFalse(amConfig, observabilityv1alpha1.AlertmanagerConfigSyncedCondition, observabilityv1alpha1.InvalidAlertmanagerConfigReason, crhelperTypes.ConditionSeverityError, "%s", errs.ToAggregate().Error())
+		return ctrl.Result{}, nil
+	}
+	userConfig, err := alerting.Render(amConfig.Spec)
+	if err != nil {
+		conditions.MarkFalse(amConfig, observabilityv1alpha1.AlertmanagerConfigSyncedCondition, observabilityv1alpha1.InvalidAlertmanagerConfigReason, crhelperTypes.ConditionSeverityError, "%s", err.Error())
+		return ctrl.Result{}, nil
+	}
+
+	limits, err := renderedMimirLimits(ctx, r.Client, config.Spec.Mimir.ConfigMap, tenantID)
+	if err != nil {
+		log.Error(err, "unable to fetch Mimir ConfigMap")
+		return ctrl.Result{}, err
+	}
+	if err := alerting.ExceedsLimits(userConfig, limits); err != nil {
+		conditions.MarkFalse(amConfig, observabilityv1alpha1.AlertmanagerConfigSyncedCondition, observabilityv1alpha1.AlertmanagerLimitExceededReason, crhelperTypes.ConditionSeverityError, "%s", err.Error())
+		return ctrl.Result{}, nil
+	}
+	blocked, err := alerting.BlockedReceivers(amConfig.Spec.Config.Object, limits)
+	if err == nil && len(blocked) > 0 {
+		err = blocked.ToAggregate()
+	}
+	if err != nil {
+		conditions.MarkFalse(amConfig, observabilityv1alpha1.AlertmanagerConfigSyncedCondition, observabilityv1alpha1.ReceiverBlockedReason, crhelperTypes.ConditionSeverityError, "%s", err.Error())
+		return ctrl.Result{}, nil
+	}
+
+	syncInterval := defaultAlertmanagerSyncInterval
+	if config.Spec.Mimir.Alertmanager.SyncInterval != nil {
+		syncInterval = config.Spec.Mimir.Alertmanager.SyncInterval.Duration
+	}
+	if err := amClient.Sync(ctx, tenantID, userConfig); err != nil {
+		log.Error(err, "unable to sync configuration to the Alertmanager", "name", amConfig.Name)
+		if alertmanager.IsInvalid(err) {
+			conditions.MarkFalse(amConfig, observabilityv1alpha1.AlertmanagerConfigSyncedCondition, observabilityv1alpha1.InvalidAlertmanagerConfigReason, crhelperTypes.ConditionSeverityError, "%s", err.Error())
+			return ctrl.Result{RequeueAfter: syncInterval}, nil
+		}
+		conditions.MarkFalse(amConfig, observabilityv1alpha1.AlertmanagerConfigSyncedCondition, observabilityv1alpha1.AlertmanagerUnavailableReason, crhelperTypes.ConditionSeverityWarning, "%s", err.Error())
+		return ctrl.Result{RequeueAfter: alertmanagerRetryInterval}, nil
+	}
+
+	now := metav1.Now()
+	amConfig.Status.TenantID = tenantID
+	amConfig.Status.LastSyncTime = &now
+	amConfig.Status.ObservedGeneration = amConfig.Generation
+	conditions.MarkTrue(amConfig, observabilityv1alpha1.AlertmanagerConfigSyncedCondition)
+
+	// Syncing again periodically undoes changes made directly in the Alertmanager.
+	return ctrl.Result{RequeueAfter: syncInterval}, nil
+}
+
+// owner returns the TenantAlertmanagerConfig that configures the tenant of amConfig.
+func (r *TenantAlertmanagerConfigReconciler) owner(ctx context.Context, amConfig *observabilityv1alpha1.TenantAlertmanagerConfig) (*observabilityv1alpha1.TenantAlertmanagerConfig, error) {
+	list := &observabilityv1alpha1.TenantAlertmanagerConfigList{}
+	if err := r.List(ctx, list); err != nil {
+		return nil, err
+	}
+	configs := []observabilityv1alpha1.TenantAlertmanagerConfig{*amConfig}
+	for _, c := range list.Items {
+		if c.Spec.Tenant == amConfig.Spec.Tenant && (c.Namespace != amConfig.Namespace || c.Name != amConfig.Name) {
+			configs = append(configs, c)
+		}
+	}
+	return alerting.Owner(configs), nil
+}
+
+// findConfigsOfTenant enqueues the TenantAlertmanagerConfigs of a Tenant.
+func (r *TenantAlertmanagerConfigReconciler) findConfigsOfTenant(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.findConfigs(ctx, obj.GetName())
+}
+
+// findSiblingConfigs enqueues the other TenantAlertmanagerConfigs of the
+// tenant of a TenantAlertmanagerConfig, which take over when it is deleted.
+func (r *TenantAlertmanagerConfigReconciler) findSiblingConfigs(ctx context.Context, obj client.Object) []reconcile.Request {
+	amConfig, ok := obj.(*observabilityv1alpha1.TenantAlertmanagerConfig)
+	if !ok {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, req := range r.findConfigs(ctx, amConfig.Spec.Tenant) {
+		if req.NamespacedName != client.ObjectKeyFromObject(obj) {
+			requests = append(requests, req)
+		}
+	}
+	return requests
+}
+
+// findConfigsOfConfigMap enqueues every TenantAlertmanagerConfig when the
+// Mimir runtime ConfigMap, which holds the Alertmanager limits, changes.
+func (r *TenantAlertmanagerConfigReconciler) findConfigsOfConfigMap(ctx context.Context, obj client.Object) []reconcile.Request {
+	config := &observabilityv1alpha1.Config{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "config"}, config); err != nil {
+		return nil
+	}
+	if config.Spec.Mimir == nil || config.Spec.Mimir.ConfigMap.Name != obj.GetName() || config.Spec.Mimir.ConfigMap.Namespace != obj.GetNamespace() {
+		return nil
+	}
+	return r.findConfigs(ctx, "")
+}
+
+// findConfigs enqueues the TenantAlertmanagerConfigs of the named tenant, or
+// all of them if the name is empty.
+func (r *TenantAlertmanagerConfigReconciler) findConfigs(ctx context.Context, tenant string) []reconcile.Request {
+	list := &observabilityv1alpha1.TenantAlertmanagerConfigList{}
+	if err := r.List(ctx, list); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range list.Items {
+		if tenant == "" || list.Items[i].Spec.Tenant == tenant {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&list.Items[i])})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TenantAlertmanagerConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&observabilityv1alpha1.TenantAlertmanagerConfig{}).
+		Watches(
+			&observabilityv1alpha1.TenantAlertmanagerConfig{},
+			handler.EnqueueRequestsFromMapFunc(r.findSiblingConfigs),
+		).
+		Watches(
+			&observabilityv1alpha1.Tenant{},
+			handler.EnqueueRequestsFromMapFunc(r.findConfigsOfTenant),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Watches(
+			&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.findConfigsOfConfigMap),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Complete(r)
+}
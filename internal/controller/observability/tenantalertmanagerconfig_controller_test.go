@@ -0,0 +1,168 @@
+package observability
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/pluralsh/controller-reconcile-helper/pkg/conditions"
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/clients/alertmanager/alertmanagertest"
+)
+
+var _ = Describe("TenantAlertmanagerConfig controller", func() {
+	const (
+		timeout  = 10 * time.Second
+		interval = 250 * time.Millisecond
+	)
+
+	ctx := context.Background()
+
+	var server *alertmanagertest.Server
+
+	BeforeEach(func() {
+		skipWithoutEnvtest()
+		server = alertmanagertest.NewServer()
+		DeferCleanup(server.Close)
+
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "mimir"}}
+		if err := k8sClient.Create(ctx, ns); err != nil && !apierrs.IsAlreadyExists(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		config := &observabilityv1alpha1.Config{
+			ObjectMeta: metav1.ObjectMeta{Name: "config"},
+			Spec: observabilityv1alpha1.ConfigSpec{
+				Mimir: &observabilityv1alpha1.MimirSpec{
+					ConfigMap: observabilityv1alpha1.ConfigMapSelector{
+						Name:      "mimir-runtime",
+						Namespace: "mimir",
+						Key:       "runtime.yaml",
+					},
+				},
+			},
+		}
+		if err := k8sClient.Create(ctx, config); err != nil && !apierrs.IsAlreadyExists(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "config"}, config)).To(Succeed())
+		config.Spec.Mimir.Alertmanager = &observabilityv1alpha1.AlertmanagerSpec{URL: server.URL()}
+		Expect(k8sClient.Update(ctx, config)).To(Succeed())
+	})
+
+	syncedReason := func(name string) func() string {
+		return func() string {
+			c := &observabilityv1alpha1.TenantAlertmanagerConfig{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, c); err != nil {
+				return ""
+			}
+			if conditions.IsTrue(c, observabilityv1alpha1.AlertmanagerConfigSyncedCondition) {
+				return "Synced"
+			}
+			if cond := conditions.Get(c, observabilityv1alpha1.AlertmanagerConfigSyncedCondition); cond != nil {
+				return cond.Reason
+			}
+			return ""
+		}
+	}
+
+	It("syncs the Alertmanager configuration of a tenant", func() {
+		blockPrivate := true
+		tenant := &observabilityv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "alerting-tenant"},
+			Spec: observabilityv1alpha1.TenantSpec{
+				Limits: &observabilityv1alpha1.LimitSpec{
+					Mimir: &observabilityv1alpha1.MimirLimits{AlertmanagerReceiversBlockPrivateAddresses: &blockPrivate},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, tenant)).To(Succeed())
+
+		amConfig := &observabilityv1alpha1.TenantAlertmanagerConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "alerting", Namespace: "default"},
+			Spec: observabilityv1alpha1.TenantAlertmanagerConfigSpec{
+				Tenant: "alerting-tenant",
+				Config: observabilityv1alpha1.WrappedMap{Object: map[string]interface{}{
+					"route": map[string]interface{}{"receiver": "default"},
+					"receivers": []interface{}{map[string]interface{}{
+						"name":            "default",
+						"webhook_configs": []interface{}{map[string]interface{}{"url": "https://hooks.example.com"}},
+					}},
+				}},
+				Templates: map[string]string{"default.tmpl": `{{ define "x" }}{{ end }}`},
+			},
+		}
+		Expect(k8sClient.Create(ctx, amConfig)).To(Succeed())
+
+		Eventually(func() bool {
+			_, ok := server.Config("alerting-tenant")
+			return ok
+		}, timeout, interval).Should(BeTrue())
+		Eventually(syncedReason("alerting"), timeout, interval).Should(Equal("Synced"))
+
+		By("rejecting a second configuration for the tenant")
+		second := amConfig.DeepCopy()
+		second.ObjectMeta = metav1.ObjectMeta{Name: "alerting-second", Namespace: "default"}
+		Expect(k8sClient.Create(ctx, second)).To(Succeed())
+		Eventually(syncedReason("alerting-second"), timeout, interval).Should(Equal(observabilityv1alpha1.AlertmanagerConfigConflictReason))
+		Expect(k8sClient.Delete(ctx, second)).To(Succeed())
+
+		By("blocking receivers at private addresses")
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "alerting", Namespace: "default"}, amConfig)).To(Succeed())
+		amConfig.Spec.Config.Object["receivers"] = []interface{}{map[string]interface{}{
+			"name":            "default",
+			"webhook_configs": []interface{}{map[string]interface{}{"url": "http://10.0.0.1/hook"}},
+		}}
+		Expect(k8sClient.Update(ctx, amConfig)).To(Succeed())
+		Eventually(syncedReason("alerting"), timeout, interval).Should(Equal(observabilityv1alpha1.ReceiverBlockedReason))
+
+		By("deleting the configuration")
+		Expect(k8sClient.Delete(ctx, amConfig)).To(Succeed())
+		Eventually(func() bool {
+			_, ok := server.Config("alerting-tenant")
+			return ok
+		}, timeout, interval).Should(BeFalse())
+	})
+
+	It("keeps the configuration synced by the next owner when the owner is deleted", func() {
+		tenant := &observabilityv1alpha1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "handoff-tenant"}}
+		Expect(k8sClient.Create(ctx, tenant)).To(Succeed())
+
+		configFor := func(name, receiver string) *observabilityv1alpha1.TenantAlertmanagerConfig {
+			return &observabilityv1alpha1.TenantAlertmanagerConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Spec: observabilityv1alpha1.TenantAlertmanagerConfigSpec{
+					Tenant: "handoff-tenant",
+					Config: observabilityv1alpha1.WrappedMap{Object: map[string]interface{}{
+						"route":     map[string]interface{}{"receiver": receiver},
+						"receivers": []interface{}{map[string]interface{}{"name": receiver}},
+					}},
+				},
+			}
+		}
+		receiver := func() string {
+			config, _ := server.Config("handoff-tenant")
+			return config.AlertmanagerConfig
+		}
+
+		first := configFor("handoff-first", "first")
+		Expect(k8sClient.Create(ctx, first)).To(Succeed())
+		Eventually(syncedReason("handoff-first"), timeout, interval).Should(Equal("Synced"))
+		Expect(k8sClient.Create(ctx, configFor("handoff-second", "second"))).To(Succeed())
+		Eventually(syncedReason("handoff-second"), timeout, interval).Should(Equal(observabilityv1alpha1.AlertmanagerConfigConflictReason))
+
+		Expect(k8sClient.Delete(ctx, first)).To(Succeed())
+		Eventually(syncedReason("handoff-second"), timeout, interval).Should(Equal("Synced"))
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: "handoff-first", Namespace: "default"}, first)
+		}, timeout, interval).Should(Satisfy(apierrs.IsNotFound))
+		Consistently(receiver, 2*time.Second, interval).Should(ContainSubstring("second"))
+	})
+})
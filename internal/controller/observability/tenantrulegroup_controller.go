@@ -0,0 +1,324 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observability
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/pluralsh/controller-reconcile-helper/pkg/conditions"
+	"github.com/pluralsh/controller-reconcile-helper/pkg/patch"
+	crhelperTypes "github.com/pluralsh/controller-reconcile-helper/pkg/types"
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/clients/ruler"
+	"github.com/traceshield/trace-shield-controller/internal/rules"
+)
+
+const (
+	tenantRuleGroupFinalizerName = "tenantrulegroups.observability.traceshield.io/finalizer"
+
+	// defaultRulerSyncInterval is how often rule groups are synced when the Config sets no interval.
+	defaultRulerSyncInterval = 5 * time.Minute
+
+	// rulerRetryInterval is how long to wait before syncing rule groups again after the ruler failed.
+	rulerRetryInterval = 30 * time.Second
+)
+
+// TenantRuleGroupReconciler reconciles a TenantRuleGroup object
+type TenantRuleGroupReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=observability.traceshield.io,resources=tenantrulegroups,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=observability.traceshield.io,resources=tenantrulegroups/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=observability.traceshield.io,resources=tenantrulegroups/finalizers,verbs=update
+
+// Reconcile syncs the rule groups of a TenantRuleGroup to the Mimir or Loki ruler of its tenant.
+func (r *TenantRuleGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	ruleGroup := &observabilityv1alpha1.TenantRuleGroup{}
+	if err := r.Get(ctx, req.NamespacedName, ruleGroup); err != nil {
+		if apierrs.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "unable to fetch TenantRuleGroup")
+		return ctrl.Result{}, err
+	}
+
+	config := &observabilityv1alpha1.Config{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "config"}, config); err != nil {
+		if apierrs.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "unable to fetch Observability Config")
+		return ctrl.Result{}, err
+	}
+
+	rulerClient, rulerSpec, limitsConfigMap := rulerOf(config, ruleGroup.GetRuler())
+
+	if !ruleGroup.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(ruleGroup, tenantRuleGroupFinalizerName) {
+			// Rule groups that were never synced, or cannot be removed because
+			// the ruler is no longer configured, are left behind.
+			if rulerClient != nil && ruleGroup.Status.TenantID != "" {
+				if err := rulerClient.DeleteNamespace(ctx, ruleGroup.Status.TenantID, ruleGroup.Status.Namespace); err != nil {
+					log.Error(err, "unable to delete rule groups from the ruler", "name", ruleGroup.Name)
+					return ctrl.Result{}, err
+				}
+			}
+			controllerutil.RemoveFinalizer(ruleGroup, tenantRuleGroupFinalizerName)
+			if err := r.Update(ctx, ruleGroup); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if rulerClient == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(ruleGroup, tenantRuleGroupFinalizerName) {
+		controllerutil.AddFinalizer(ruleGroup, tenantRuleGroupFinalizerName)
+		if err := r.Update(ctx, ruleGroup); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	patchHelper, err := patch.NewHelper(ruleGroup, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	defer func() {
+		if err := patchHelper.Patch(ctx, ruleGroup); err != nil {
+			log.Error(err, "unable to patch tenant rule group status", "name", ruleGroup.Name)
+		}
+	}()
+
+	tenant := &observabilityv1alpha1.Tenant{}
+	if err := r.Get(ctx, types.NamespacedName{Name: ruleGroup.Spec.Tenant}, tenant); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		conditions.MarkFalse(ruleGroup, observabilityv1alpha1.RulesSyncedCondition, observabilityv1alpha1.TenantNotFoundReason, crhelperTypes.ConditionSeverityError, "tenant %s does not exist", ruleGroup.Spec.Tenant)
+		return ctrl.Result{}, nil
+	}
+	if !tenant.DeletionTimestamp.IsZero() {
+		conditions.MarkFalse(ruleGroup, observabilityv1alpha1.RulesSyncedCondition, observabilityv1alpha1.TenantNotFoundReason, crhelperTypes.ConditionSeverityError, "tenant %s is being deleted", ruleGroup.Spec.Tenant)
+		return ctrl.Result{}, nil
+	}
+	tenantID := tenant.GetTenantID()
+
+	if errs := rules.Validate(ruleGroup.Spec.Groups, ruleGroup.GetRuler()); len(errs) > 0 {
+		conditions.MarkFalse(ruleGroup, observabilityv1alpha1.RulesSyncedCondition, observabilityv1alpha1.InvalidRulesReason, crhelperTypes.ConditionSeverityError, "%s", errs.ToAggregate().Error())
+		return ctrl.Result{}, nil
+	}
+	maxRulesPerGroup, maxGroups, err := r.rulerLimits(ctx, ruleGroup.GetRuler(), limitsConfigMap, tenantID)
+	if err != nil {
+		log.Error(err, "unable to fetch runtime ConfigMap of the ruler")
+		return ctrl.Result{}, err
+	}
+	if err := rules.ExceedsRuleLimit(ruleGroup.Spec.Groups, maxRulesPerGroup); err != nil {
+		conditions.MarkFalse(ruleGroup, observabilityv1alpha1.RulesSyncedCondition, observabilityv1alpha1.RulerLimitExceededReason, crhelperTypes.ConditionSeverityError, "%s", err.Error())
+		return ctrl.Result{}, nil
+	}
+	siblings, i, err := r.tenantRuleGroups(ctx, ruleGroup)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := rules.ExceedsGroupLimit(siblings, i, maxGroups); err != nil {
+		conditions.MarkFalse(ruleGroup, observabilityv1alpha1.RulesSyncedCondition, observabilityv1alpha1.RulerLimitExceededReason, crhelperTypes.ConditionSeverityError, "%s", err.Error())
+		return ctrl.Result{}, nil
+	}
+
+	syncInterval := defaultRulerSyncInterval
+	if rulerSpec.SyncInterval != nil {
+		syncInterval = rulerSpec.SyncInterval.Duration
+	}
+	namespace := rulerNamespace(ruleGroup)
+	if err := rulerClient.Sync(ctx, tenantID, namespace, ruleGroup.Spec.Groups); err != nil {
+		log.Error(err, "unable to sync rule groups to the ruler", "name", ruleGroup.Name)
+		if ruler.IsInvalid(err) {
+			conditions.MarkFalse(ruleGroup, observabilityv1alpha1.RulesSyncedCondition, observabilityv1alpha1.InvalidRulesReason, crhelperTypes.ConditionSeverityError, "%s", err.Error())
+			return ctrl.Result{RequeueAfter: syncInterval}, nil
+		}
+		conditions.MarkFalse(ruleGroup, observabilityv1alpha1.RulesSyncedCondition, observabilityv1alpha1.RulerUnavailableReason, crhelperTypes.ConditionSeverityWarning, "%s", err.Error())
+		return ctrl.Result{RequeueAfter: rulerRetryInterval}, nil
+	}
+
+	now := metav1.Now()
+	ruleGroup.Status.TenantID = tenantID
+	ruleGroup.Status.Namespace = namespace
+	ruleGroup.Status.LastSyncTime = &now
+	ruleGroup.Status.ObservedGeneration = ruleGroup.Generation
+	conditions.MarkTrue(ruleGroup, observabilityv1alpha1.RulesSyncedCondition)
+
+	// Syncing again periodically undoes changes made directly in the ruler.
+	return ctrl.Result{RequeueAfter: syncInterval}, nil
+}
+
+// rulerNamespace returns the ruler namespace the rule groups of the
+// TenantRuleGroup are synced to. Kubernetes namespaces cannot contain dots,
+// so the namespace is unique.
+func rulerNamespace(ruleGroup *observabilityv1alpha1.TenantRuleGroup) string {
+	return ruleGroup.Namespace + "." + ruleGroup.Name
+}
+
+// rulerOf returns a client for the ruler of the given type, its
+// configuration and the runtime ConfigMap holding the ruler limits of
+// tenants. The client is nil if the ruler is not configured.
+func rulerOf(config *observabilityv1alpha1.Config, rulerType observabilityv1alpha1.RulerType) (*ruler.Client, *observabilityv1alpha1.RulerSpec, observabilityv1alpha1.ConfigMapSelector) {
+	switch rulerType {
+	case observabilityv1alpha1.RulerTypeMimir:
+		if config.Spec.Mimir != nil && config.Spec.Mimir.Ruler != nil {
+			return ruler.NewMimirClient(config.Spec.Mimir.Ruler.URL), config.Spec.Mimir.Ruler, config.Spec.Mimir.ConfigMap
+		}
+	case observabilityv1alpha1.RulerTypeLoki:
+		if config.Spec.Loki != nil && config.Spec.Loki.Ruler != nil {
+			return ruler.NewLokiClient(config.Spec.Loki.Ruler.URL), config.Spec.Loki.Ruler, config.Spec.Loki.ConfigMap
+		}
+	}
+	return nil, nil, observabilityv1alpha1.ConfigMapSelector{}
+}
+
+// rulerLimits returns the maximum number of rules per rule group and of
+// rule groups rendered for the tenant in the runtime ConfigMap of the ruler.
+func (r *TenantRuleGroupReconciler) rulerLimits(ctx context.Context, rulerType observabilityv1alpha1.RulerType, selector observabilityv1alpha1.ConfigMapSelector, tenantID string) (*int, *int, error) {
+	if rulerType == observabilityv1alpha1.RulerTypeLoki {
+		limits, err := renderedLokiLimits(ctx, r.Client, selector, tenantID)
+		return limits.RulerMaxRulesPerRuleGroup, limits.RulerMaxRuleGroupsPerTenant, err
+	}
+	limits, err := renderedMimirLimits(ctx, r.Client, selector, tenantID)
+	return limits.RulerMaxRulesPerRuleGroup, limits.RulerMaxRuleGroupsPerTenant, err
+}
+
+// tenantRuleGroups returns the TenantRuleGroups of the tenant of ruleGroup
+// synced to the same ruler and the index of ruleGroup among them.
+func (r *TenantRuleGroupReconciler) tenantRuleGroups(ctx context.Context, ruleGroup *observabilityv1alpha1.TenantRuleGroup) ([]observabilityv1alpha1.TenantRuleGroup, int, error) {
+	list := &observabilityv1alpha1.TenantRuleGroupList{}
+	if err := r.List(ctx, list); err != nil {
+		return nil, -1, err
+	}
+	var out []observabilityv1alpha1.TenantRuleGroup
+	index := -1
+	for _, g := range list.Items {
+		if g.Spec.Tenant != ruleGroup.Spec.Tenant || g.GetRuler() != ruleGroup.GetRuler() {
+			continue
+		}
+		if g.Namespace == ruleGroup.Namespace && g.Name == ruleGroup.Name {
+			index = len(out)
+			g = *ruleGroup
+		}
+		out = append(out, g)
+	}
+	if index < 0 {
+		index = len(out)
+		out = append(out, *ruleGroup)
+	}
+	return out, index, nil
+}
+
+// findRuleGroupsOfTenant enqueues the TenantRuleGroups of a Tenant.
+func (r *TenantRuleGroupReconciler) findRuleGroupsOfTenant(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.findRuleGroups(ctx, obj.GetName())
+}
+
+// findSiblingRuleGroups enqueues the other TenantRuleGroups of the tenant of
+// a TenantRuleGroup, which share its rule group limit.
+func (r *TenantRuleGroupReconciler) findSiblingRuleGroups(ctx context.Context, obj client.Object) []reconcile.Request {
+	ruleGroup, ok := obj.(*observabilityv1alpha1.TenantRuleGroup)
+	if !ok {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, req := range r.findRuleGroups(ctx, ruleGroup.Spec.Tenant) {
+		if req.NamespacedName != client.ObjectKeyFromObject(obj) {
+			requests = append(requests, req)
+		}
+	}
+	return requests
+}
+
+// findRuleGroupsOfConfigMap enqueues every TenantRuleGroup when the Mimir or
+// Loki runtime ConfigMap, which hold the ruler limits, changes.
+func (r *TenantRuleGroupReconciler) findRuleGroupsOfConfigMap(ctx context.Context, obj client.Object) []reconcile.Request {
+	config := &observabilityv1alpha1.Config{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "config"}, config); err != nil {
+		return nil
+	}
+	isSelected := func(selector observabilityv1alpha1.ConfigMapSelector) bool {
+		return selector.Name == obj.GetName() && selector.Namespace == obj.GetNamespace()
+	}
+	if (config.Spec.Mimir == nil || !isSelected(config.Spec.Mimir.ConfigMap)) && (config.Spec.Loki == nil || !isSelected(config.Spec.Loki.ConfigMap)) {
+		return nil
+	}
+	return r.findRuleGroups(ctx, "")
+}
+
+// findRuleGroups enqueues the TenantRuleGroups of the named tenant, or all
+// of them if the name is empty.
+func (r *TenantRuleGroupReconciler) findRuleGroups(ctx context.Context, tenant string) []reconcile.Request {
+	list := &observabilityv1alpha1.TenantRuleGroupList{}
+	if err := r.List(ctx, list); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range list.Items {
+		if tenant == "" || list.Items[i].Spec.Tenant == tenant {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&list.Items[i])})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TenantRuleGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&observabilityv1alpha1.TenantRuleGroup{}).
+		Watches(
+			&observabilityv1alpha1.TenantRuleGroup{},
+			handler.EnqueueRequestsFromMapFunc(r.findSiblingRuleGroups),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Watches(
+			&observabilityv1alpha1.Tenant{},
+			handler.EnqueueRequestsFromMapFunc(r.findRuleGroupsOfTenant),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Watches(
+			&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.findRuleGroupsOfConfigMap),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Complete(r)
+}
@@ -0,0 +1,151 @@
+package observability
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/pluralsh/controller-reconcile-helper/pkg/conditions"
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/clients/ruler/rulertest"
+)
+
+var _ = Describe("TenantRuleGroup controller", func() {
+	const (
+		timeout  = 10 * time.Second
+		interval = 250 * time.Millisecond
+	)
+
+	ctx := context.Background()
+
+	var server *rulertest.Server
+
+	BeforeEach(func() {
+		skipWithoutEnvtest()
+		server = rulertest.NewMimirServer()
+		DeferCleanup(server.Close)
+
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "mimir"}}
+		if err := k8sClient.Create(ctx, ns); err != nil && !apierrs.IsAlreadyExists(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		config := &observabilityv1alpha1.Config{
+			ObjectMeta: metav1.ObjectMeta{Name: "config"},
+			Spec: observabilityv1alpha1.ConfigSpec{
+				Mimir: &observabilityv1alpha1.MimirSpec{
+					ConfigMap: observabilityv1alpha1.ConfigMapSelector{
+						Name:      "mimir-runtime",
+						Namespace: "mimir",
+						Key:       "runtime.yaml",
+					},
+				},
+			},
+		}
+		if err := k8sClient.Create(ctx, config); err != nil && !apierrs.IsAlreadyExists(err) {
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "config"}, config)).To(Succeed())
+		config.Spec.Mimir.Ruler = &observabilityv1alpha1.RulerSpec{URL: server.URL()}
+		Expect(k8sClient.Update(ctx, config)).To(Succeed())
+	})
+
+	It("syncs rule groups to the ruler of the tenant", func() {
+		tenant := &observabilityv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "rules-tenant"},
+		}
+		Expect(k8sClient.Create(ctx, tenant)).To(Succeed())
+
+		ruleGroup := &observabilityv1alpha1.TenantRuleGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+			Spec: observabilityv1alpha1.TenantRuleGroupSpec{
+				Tenant: "rules-tenant",
+				Groups: []observabilityv1alpha1.RuleGroup{{
+					Name:  "example",
+					Rules: []observabilityv1alpha1.Rule{{Alert: "Down", Expr: "up == 0"}},
+				}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, ruleGroup)).To(Succeed())
+
+		Eventually(func() []observabilityv1alpha1.RuleGroup {
+			return server.Groups("rules-tenant", "default.example")
+		}, timeout, interval).Should(ConsistOf(HaveField("Name", "example")))
+
+		Eventually(func() bool {
+			g := &observabilityv1alpha1.TenantRuleGroup{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "example", Namespace: "default"}, g); err != nil {
+				return false
+			}
+			return conditions.IsTrue(g, observabilityv1alpha1.RulesSyncedCondition)
+		}, timeout, interval).Should(BeTrue())
+
+		By("deleting the TenantRuleGroup")
+		Expect(k8sClient.Delete(ctx, ruleGroup)).To(Succeed())
+		Eventually(func() []string {
+			return server.Namespaces("rules-tenant")
+		}, timeout, interval).Should(BeEmpty())
+	})
+	It("syncs LogQL rule groups to the Loki ruler", func() {
+		lokiServer := rulertest.NewLokiServer()
+		DeferCleanup(lokiServer.Close)
+
+		config := &observabilityv1alpha1.Config{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "config"}, config)).To(Succeed())
+		config.Spec.Loki = &observabilityv1alpha1.LokiSpec{
+			ConfigMap: observabilityv1alpha1.ConfigMapSelector{
+				Name:      "loki-runtime",
+				Namespace: "mimir",
+				Key:       "runtime.yaml",
+			},
+			Ruler: &observabilityv1alpha1.RulerSpec{URL: lokiServer.URL()},
+		}
+		Expect(k8sClient.Update(ctx, config)).To(Succeed())
+
+		tenant := &observabilityv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "log-rules-tenant"},
+		}
+		Expect(k8sClient.Create(ctx, tenant)).To(Succeed())
+
+		ruleGroup := &observabilityv1alpha1.TenantRuleGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "logs", Namespace: "default"},
+			Spec: observabilityv1alpha1.TenantRuleGroupSpec{
+				Tenant: "log-rules-tenant",
+				Ruler:  observabilityv1alpha1.RulerTypeLoki,
+				Groups: []observabilityv1alpha1.RuleGroup{{
+					Name:  "errors",
+					Rules: []observabilityv1alpha1.Rule{{Alert: "Errors", Expr: `sum(rate({app="foo"} |= "error" [5m])) > 1`}},
+				}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, ruleGroup)).To(Succeed())
+
+		Eventually(func() []observabilityv1alpha1.RuleGroup {
+			return lokiServer.Groups("log-rules-tenant", "default.logs")
+		}, timeout, interval).Should(ConsistOf(HaveField("Name", "errors")))
+		Expect(server.Namespaces("log-rules-tenant")).To(BeEmpty())
+
+		By("rejecting invalid LogQL before it is uploaded")
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "logs", Namespace: "default"}, ruleGroup)).To(Succeed())
+		ruleGroup.Spec.Groups[0].Rules[0].Expr = `rate({app="foo"})`
+		Expect(k8sClient.Update(ctx, ruleGroup)).To(Succeed())
+		Eventually(func() string {
+			g := &observabilityv1alpha1.TenantRuleGroup{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "logs", Namespace: "default"}, g); err != nil {
+				return ""
+			}
+			if c := conditions.Get(g, observabilityv1alpha1.RulesSyncedCondition); c != nil {
+				return c.Reason
+			}
+			return ""
+		}, timeout, interval).Should(Equal(observabilityv1alpha1.InvalidRulesReason))
+		Expect(lokiServer.Groups("log-rules-tenant", "default.logs")).To(ConsistOf(HaveField("Rules", ConsistOf(HaveField("Expr", `sum(rate({app="foo"} |= "error" [5m])) > 1`)))))
+	})
+})
@@ -0,0 +1,127 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gateway generates the credentials gateways use to map requests to
+// tenant IDs.
+package gateway
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+)
+
+const (
+	// Keys of the per-tenant credential Secrets.
+	TenantIDKey = "tenant"
+	TokenKey    = "token"
+	UsernameKey = "username"
+	PasswordKey = "password"
+	HtpasswdKey = "htpasswd"
+
+	// Keys of the aggregated Secret.
+	TokensFileKey   = "tokens.yaml"
+	HtpasswdFileKey = ".htpasswd"
+
+	// secretBytes is the amount of randomness in generated tokens and passwords.
+	secretBytes = 32
+)
+
+// NewCredentials returns the data of a per-tenant credential Secret.
+func NewCredentials(credentialType observabilityv1alpha1.GatewayCredentialType, tenantID string) (map[string][]byte, error) {
+	secret, err := randomSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	switch credentialType {
+	case observabilityv1alpha1.GatewayCredentialToken, "":
+		return map[string][]byte{
+			TenantIDKey: []byte(tenantID),
+			TokenKey:    []byte(secret),
+		}, nil
+	case observabilityv1alpha1.GatewayCredentialBasicAuth:
+		hash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		return map[string][]byte{
+			TenantIDKey: []byte(tenantID),
+			UsernameKey: []byte(tenantID),
+			PasswordKey: []byte(secret),
+			HtpasswdKey: []byte(tenantID + ":" + string(hash)),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown gateway credential type %q", credentialType)
+	}
+}
+
+// MatchesType reports whether the credential Secret data was generated for the credential type.
+func MatchesType(credentialType observabilityv1alpha1.GatewayCredentialType, data map[string][]byte) bool {
+	if credentialType == observabilityv1alpha1.GatewayCredentialBasicAuth {
+		return len(data[HtpasswdKey]) > 0
+	}
+	return len(data[TokenKey]) > 0
+}
+
+// Aggregate returns the data of the Secret gateways consume, built from the
+// per-tenant credential Secrets. Tokens are mapped to tenant IDs in a YAML
+// file, basic-auth entries are collected in an htpasswd file whose user names
+// are the tenant IDs.
+func Aggregate(credentialType observabilityv1alpha1.GatewayCredentialType, secrets []corev1.Secret) (map[string][]byte, error) {
+	if credentialType == observabilityv1alpha1.GatewayCredentialBasicAuth {
+		var entries []string
+		for _, s := range secrets {
+			if entry := s.Data[HtpasswdKey]; len(entry) > 0 {
+				entries = append(entries, string(entry))
+			}
+		}
+		sort.Strings(entries)
+		htpasswd := strings.Join(entries, "\n")
+		if htpasswd != "" {
+			htpasswd += "\n"
+		}
+		return map[string][]byte{HtpasswdFileKey: []byte(htpasswd)}, nil
+	}
+
+	tokens := map[string]string{}
+	for _, s := range secrets {
+		if token := s.Data[TokenKey]; len(token) > 0 {
+			tokens[string(token)] = string(s.Data[TenantIDKey])
+		}
+	}
+	out, err := yaml.Marshal(tokens)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{TokensFileKey: out}, nil
+}
+
+func randomSecret() (string, error) {
+	b := make([]byte, secretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate credentials: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
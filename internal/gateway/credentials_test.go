@@ -0,0 +1,56 @@
+package gateway_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/bcrypt"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/internal/gateway"
+)
+
+var _ = Describe("Credentials", func() {
+	It("generates unique tokens mapped to tenant IDs", func() {
+		a, err := gateway.NewCredentials(observabilityv1alpha1.GatewayCredentialToken, "team-a")
+		Expect(err).NotTo(HaveOccurred())
+		b, err := gateway.NewCredentials(observabilityv1alpha1.GatewayCredentialToken, "team-b")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(a[gateway.TokenKey]).NotTo(Equal(b[gateway.TokenKey]))
+		Expect(gateway.MatchesType(observabilityv1alpha1.GatewayCredentialToken, a)).To(BeTrue())
+		Expect(gateway.MatchesType(observabilityv1alpha1.GatewayCredentialBasicAuth, a)).To(BeFalse())
+
+		data, err := gateway.Aggregate(observabilityv1alpha1.GatewayCredentialToken, []corev1.Secret{{Data: a}, {Data: b}})
+		Expect(err).NotTo(HaveOccurred())
+
+		tokens := map[string]string{}
+		Expect(yaml.Unmarshal(data[gateway.TokensFileKey], &tokens)).To(Succeed())
+		Expect(tokens).To(Equal(map[string]string{
+			string(a[gateway.TokenKey]): "team-a",
+			string(b[gateway.TokenKey]): "team-b",
+		}))
+	})
+
+	It("generates htpasswd entries for basic auth", func() {
+		creds, err := gateway.NewCredentials(observabilityv1alpha1.GatewayCredentialBasicAuth, "team-a")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(creds[gateway.UsernameKey]).To(Equal([]byte("team-a")))
+
+		user, hash, ok := strings.Cut(string(creds[gateway.HtpasswdKey]), ":")
+		Expect(ok).To(BeTrue())
+		Expect(user).To(Equal("team-a"))
+		Expect(bcrypt.CompareHashAndPassword([]byte(hash), creds[gateway.PasswordKey])).To(Succeed())
+
+		data, err := gateway.Aggregate(observabilityv1alpha1.GatewayCredentialBasicAuth, []corev1.Secret{{Data: creds}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data[gateway.HtpasswdFileKey])).To(Equal(string(creds[gateway.HtpasswdKey]) + "\n"))
+	})
+
+	It("rejects unknown credential types", func() {
+		_, err := gateway.NewCredentials("oauth", "team-a")
+		Expect(err).To(HaveOccurred())
+	})
+})
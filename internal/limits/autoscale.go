@@ -0,0 +1,134 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package limits
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+)
+
+const (
+	// DefaultTargetUtilization is the target utilization of autoscaled limits that do not set one.
+	DefaultTargetUtilization = 80
+
+	// autoscaleTolerance is how far off the target utilization usage may be,
+	// as a fraction of the target, before an autoscaled limit is changed.
+	autoscaleTolerance = 0.1
+)
+
+// LimitValue returns the value of the limit at path, e.g.
+// mimir.max_global_series_per_user, in spec or nil if it is not set.
+func LimitValue(spec *observabilityv1alpha1.LimitSpec, path string) (*float64, error) {
+	if _, err := limitKind(path); err != nil {
+		return nil, err
+	}
+	var values map[string]interface{}
+	if spec != nil {
+		if err := roundTrip(spec, &values); err != nil {
+			return nil, err
+		}
+	}
+	backend, name, _ := strings.Cut(path, ".")
+	limits, _ := values[backend].(map[string]interface{})
+	v, ok := limits[name].(float64)
+	if !ok {
+		return nil, nil
+	}
+	return &v, nil
+}
+
+// SetLimit returns a copy of spec with the limit at path set to value.
+// Integer limits are rounded up.
+func SetLimit(spec *observabilityv1alpha1.LimitSpec, path string, value float64) (*observabilityv1alpha1.LimitSpec, error) {
+	kind, err := limitKind(path)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{} = value
+	if kind == reflect.Int {
+		v = int64(math.Ceil(value))
+	}
+
+	backend, name, _ := strings.Cut(path, ".")
+	data, err := json.Marshal(map[string]map[string]interface{}{backend: {name: v}})
+	if err != nil {
+		return nil, err
+	}
+	override := &observabilityv1alpha1.LimitSpec{}
+	if err := json.Unmarshal(data, override); err != nil {
+		return nil, err
+	}
+	return Merge(spec, override)
+}
+
+// Clamp returns value within the bounds of the autoscaled limit.
+func Clamp(a observabilityv1alpha1.AutoscaleSpec, value float64) float64 {
+	return math.Min(math.Max(value, a.Min), a.Max)
+}
+
+// Autoscale returns the value the autoscaled limit should be changed to from
+// current so that usage is at the target utilization, and whether it should
+// be changed at all. Like the Kubernetes HorizontalPodAutoscaler, the limit
+// is only changed once the utilization is more than 10% off target.
+func Autoscale(a observabilityv1alpha1.AutoscaleSpec, current, usage float64) (float64, bool) {
+	target := float64(DefaultTargetUtilization)
+	if a.TargetUtilization != nil {
+		target = float64(*a.TargetUtilization)
+	}
+
+	desired := Clamp(a, math.Ceil(usage*100/target))
+	if desired == current {
+		return current, false
+	}
+	if current > 0 && math.Abs(usage*100/current/target-1) <= autoscaleTolerance {
+		return current, false
+	}
+	return desired, true
+}
+
+// limitKind returns the kind of the limit at path in LimitSpec.
+func limitKind(path string) (reflect.Kind, error) {
+	backend, name, ok := strings.Cut(path, ".")
+	if !ok {
+		return reflect.Invalid, fmt.Errorf("invalid limit %q", path)
+	}
+	if f, ok := jsonField(reflect.TypeOf(observabilityv1alpha1.LimitSpec{}), backend); ok {
+		if f, ok := jsonField(f.Type.Elem(), name); ok {
+			if f.Type.Kind() == reflect.Pointer {
+				return f.Type.Elem().Kind(), nil
+			}
+			return f.Type.Kind(), nil
+		}
+	}
+	return reflect.Invalid, fmt.Errorf("unknown limit %q", path)
+}
+
+// jsonField returns the field of the struct t with the json name.
+func jsonField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if n, _, _ := strings.Cut(f.Tag.Get("json"), ","); n == name {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
@@ -0,0 +1,91 @@
+package limits_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/internal/limits"
+)
+
+var _ = Describe("Autoscale", func() {
+	rate := func(f float64) *float64 { return &f }
+	count := func(i int) *int { return &i }
+	target := func(i int32) *int32 { return &i }
+
+	spec := observabilityv1alpha1.AutoscaleSpec{
+		Limit:             "mimir.max_global_series_per_user",
+		Min:               1000,
+		Max:               100000,
+		TargetUtilization: target(50),
+	}
+
+	It("scales to the target utilization", func() {
+		value, ok := limits.Autoscale(spec, 10000, 8000)
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal(16000.0))
+
+		value, ok = limits.Autoscale(spec, 10000, 1200)
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal(2400.0))
+	})
+
+	It("stays within the bounds", func() {
+		value, ok := limits.Autoscale(spec, 90000, 80000)
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal(100000.0))
+
+		value, ok = limits.Autoscale(spec, 2000, 10)
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal(1000.0))
+
+		_, ok = limits.Autoscale(spec, 100000, 90000)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("tolerates usage close to the target", func() {
+		_, ok := limits.Autoscale(spec, 10000, 5400)
+		Expect(ok).To(BeFalse())
+
+		_, ok = limits.Autoscale(spec, 10000, 5600)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("defaults the target utilization", func() {
+		value, ok := limits.Autoscale(observabilityv1alpha1.AutoscaleSpec{Min: 0, Max: 100}, 10, 40)
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal(50.0))
+	})
+
+	It("gets and sets limits by path", func() {
+		in := &observabilityv1alpha1.LimitSpec{
+			Mimir: &observabilityv1alpha1.MimirLimits{IngestionRate: rate(100), MaxGlobalSeriesPerUser: count(1000)},
+		}
+
+		value, err := limits.LimitValue(in, "mimir.max_global_series_per_user")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(HaveValue(Equal(1000.0)))
+
+		value, err = limits.LimitValue(in, "loki.ingestion_rate_mb")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(BeNil())
+
+		out, err := limits.SetLimit(in, "mimir.max_global_series_per_user", 1500.2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*out.Mimir.MaxGlobalSeriesPerUser).To(Equal(1501))
+		Expect(*out.Mimir.IngestionRate).To(Equal(100.0))
+		Expect(*in.Mimir.MaxGlobalSeriesPerUser).To(Equal(1000))
+
+		out, err = limits.SetLimit(nil, "loki.ingestion_rate_mb", 2.5)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*out.Loki.IngestionRateMB).To(Equal(2.5))
+	})
+
+	It("rejects unknown limits", func() {
+		_, err := limits.LimitValue(nil, "mimir.unknown")
+		Expect(err).To(MatchError(ContainSubstring("unknown limit")))
+
+		_, err = limits.SetLimit(nil, "mimir", 1)
+		Expect(err).To(MatchError(ContainSubstring("invalid limit")))
+	})
+})
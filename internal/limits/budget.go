@@ -0,0 +1,228 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package limits
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+)
+
+// Violation is a budgeted limit the children of a tenant exceed together.
+type Violation struct {
+	// Backend is the key of the limits in LimitSpec, e.g. mimir.
+	Backend string
+	// Limit is the name of the limit, e.g. max_global_series_per_user.
+	Limit string
+	// Budget is the budget of the limit.
+	Budget float64
+	// Allocated is the sum of the limit over the children that set it.
+	Allocated float64
+	// Unallocated is the number of children left without a share of the budget.
+	Unallocated int
+}
+
+func (v Violation) String() string {
+	msg := fmt.Sprintf("%s %s: %s of %s allocated", v.Backend, v.Limit, formatFloat(v.Allocated), formatFloat(v.Budget))
+	if v.Unallocated > 0 {
+		msg += fmt.Sprintf(", nothing left for %d tenants", v.Unallocated)
+	}
+	return msg
+}
+
+// Path returns the path of the limit in a LimitSpec.
+func (v Violation) Path() string {
+	return v.Backend + "." + v.Limit
+}
+
+// budgetedLimit is a single limit of a BudgetSpec.
+type budgetedLimit struct {
+	backend string
+	name    string
+	integer bool
+	budget  float64
+}
+
+// CheckBudget returns the budgeted limits the children exceed together. A
+// budget is also exceeded when nothing is left of it for the children that do
+// not set the limit themselves.
+func CheckBudget(budget *observabilityv1alpha1.BudgetSpec, children []*observabilityv1alpha1.LimitSpec) ([]Violation, error) {
+	values, err := limitValues(children)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []Violation
+	for _, l := range budgetedLimits(budget) {
+		allocated, unset := allocate(l, values)
+		if allocated > l.budget {
+			violations = append(violations, Violation{Backend: l.backend, Limit: l.name, Budget: l.budget, Allocated: allocated})
+		} else if len(unset) > 0 && share(l, allocated, len(unset)) <= 0 {
+			violations = append(violations, Violation{Backend: l.backend, Limit: l.name, Budget: l.budget, Allocated: allocated, Unallocated: len(unset)})
+		}
+	}
+	return violations, nil
+}
+
+// ShareBudget returns the share of the budget of the child at index i, with
+// every budgeted limit the child does not set itself. Integer limits are
+// rounded down. Limits the budget leaves nothing of are omitted.
+func ShareBudget(budget *observabilityv1alpha1.BudgetSpec, children []*observabilityv1alpha1.LimitSpec, i int) (*observabilityv1alpha1.LimitSpec, error) {
+	values, err := limitValues(children)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := map[string]map[string]interface{}{}
+	for _, l := range budgetedLimits(budget) {
+		allocated, unset := allocate(l, values)
+		if !containsInt(unset, i) {
+			continue
+		}
+		s := share(l, allocated, len(unset))
+		if s <= 0 {
+			continue
+		}
+		if shares[l.backend] == nil {
+			shares[l.backend] = map[string]interface{}{}
+		}
+		if l.integer {
+			shares[l.backend][l.name] = int64(s)
+		} else {
+			shares[l.backend][l.name] = s
+		}
+	}
+	if len(shares) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(shares)
+	if err != nil {
+		return nil, err
+	}
+	out := &observabilityv1alpha1.LimitSpec{}
+	if err := json.Unmarshal(data, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ChildLimits returns the names and own limits of the children of the tenant
+// that are not being deleted.
+func ChildLimits(name string, tenants []observabilityv1alpha1.Tenant) ([]string, []*observabilityv1alpha1.LimitSpec) {
+	var names []string
+	var specs []*observabilityv1alpha1.LimitSpec
+	for i := range tenants {
+		t := &tenants[i]
+		if t.Spec.Parent == name && t.Name != name && t.DeletionTimestamp.IsZero() {
+			names = append(names, t.Name)
+			specs = append(specs, t.Spec.Limits)
+		}
+	}
+	return names, specs
+}
+
+// budgetedLimits returns the limits set in the budget, keyed like LimitSpec.
+func budgetedLimits(budget *observabilityv1alpha1.BudgetSpec) []budgetedLimit {
+	if budget == nil {
+		return nil
+	}
+
+	var out []budgetedLimit
+	add := func(backend string, spec interface{}) {
+		v := reflect.ValueOf(spec)
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Field(i)
+			if f.IsNil() {
+				continue
+			}
+			name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+			l := budgetedLimit{backend: backend, name: name}
+			switch f.Elem().Kind() {
+			case reflect.Int:
+				l.integer = true
+				l.budget = float64(f.Elem().Int())
+			default:
+				l.budget = f.Elem().Float()
+			}
+			out = append(out, l)
+		}
+	}
+	add("mimir", budget.Mimir)
+	add("loki", budget.Loki)
+	return out
+}
+
+// limitValues returns the limits of the children as generic maps.
+func limitValues(children []*observabilityv1alpha1.LimitSpec) ([]map[string]interface{}, error) {
+	values := make([]map[string]interface{}, len(children))
+	for i, c := range children {
+		if c == nil {
+			continue
+		}
+		if err := roundTrip(c, &values[i]); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// allocate returns the sum of the limit over the children that set it, and
+// the indexes of the children that do not.
+func allocate(l budgetedLimit, values []map[string]interface{}) (float64, []int) {
+	var allocated float64
+	var unset []int
+	for i, v := range values {
+		backend, _ := v[l.backend].(map[string]interface{})
+		if n, ok := backend[l.name].(float64); ok {
+			allocated += n
+		} else {
+			unset = append(unset, i)
+		}
+	}
+	return allocated, unset
+}
+
+func share(l budgetedLimit, allocated float64, n int) float64 {
+	s := (l.budget - allocated) / float64(n)
+	if l.integer {
+		s = math.Floor(s)
+	}
+	return s
+}
+
+func containsInt(list []int, i int) bool {
+	for _, n := range list {
+		if n == i {
+			return true
+		}
+	}
+	return false
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
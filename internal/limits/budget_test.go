@@ -0,0 +1,68 @@
+package limits_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/internal/limits"
+)
+
+var _ = Describe("Budget", func() {
+	count := func(i int) *int { return &i }
+	rate := func(f float64) *float64 { return &f }
+	series := func(i int) *observabilityv1alpha1.LimitSpec {
+		return &observabilityv1alpha1.LimitSpec{Mimir: &observabilityv1alpha1.MimirLimits{MaxGlobalSeriesPerUser: count(i)}}
+	}
+
+	budget := &observabilityv1alpha1.BudgetSpec{
+		Mimir: &observabilityv1alpha1.MimirBudget{MaxGlobalSeriesPerUser: count(1000)},
+		Loki:  &observabilityv1alpha1.LokiBudget{IngestionRateMB: rate(10)},
+	}
+
+	It("accepts children within the budget", func() {
+		violations, err := limits.CheckBudget(budget, []*observabilityv1alpha1.LimitSpec{series(400), series(600)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(violations).To(BeEmpty())
+	})
+
+	It("reports children exceeding the budget", func() {
+		violations, err := limits.CheckBudget(budget, []*observabilityv1alpha1.LimitSpec{series(400), series(700)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(violations).To(ConsistOf(limits.Violation{Backend: "mimir", Limit: "max_global_series_per_user", Budget: 1000, Allocated: 1100}))
+		Expect(violations[0].String()).To(Equal("mimir max_global_series_per_user: 1100 of 1000 allocated"))
+	})
+
+	It("reports children left without a share of the budget", func() {
+		violations, err := limits.CheckBudget(budget, []*observabilityv1alpha1.LimitSpec{series(1000), nil})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(violations).To(ConsistOf(HaveField("Unallocated", 1)))
+	})
+
+	It("splits what is left of the budget over the children that do not set the limit", func() {
+		children := []*observabilityv1alpha1.LimitSpec{
+			series(400),
+			nil,
+			{Loki: &observabilityv1alpha1.LokiLimits{IngestionRateMB: rate(4)}},
+		}
+
+		share, err := limits.ShareBudget(budget, children, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(share).To(Equal(&observabilityv1alpha1.LimitSpec{
+			Loki: &observabilityv1alpha1.LokiLimits{IngestionRateMB: rate(3)},
+		}))
+
+		share, err = limits.ShareBudget(budget, children, 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(share).To(Equal(&observabilityv1alpha1.LimitSpec{
+			Mimir: &observabilityv1alpha1.MimirLimits{MaxGlobalSeriesPerUser: count(300)},
+			Loki:  &observabilityv1alpha1.LokiLimits{IngestionRateMB: rate(3)},
+		}))
+
+		share, err = limits.ShareBudget(budget, children, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(share).To(Equal(&observabilityv1alpha1.LimitSpec{
+			Mimir: &observabilityv1alpha1.MimirLimits{MaxGlobalSeriesPerUser: count(300)},
+		}))
+	})
+})
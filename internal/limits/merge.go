@@ -0,0 +1,81 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package limits computes the limits rendered for tenants.
+package limits
+
+import (
+	"encoding/json"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+)
+
+// Merge returns base with every limit set in override applied on top of it.
+// Nested objects are merged recursively, any other value set in override,
+// including lists, replaces the one in base. Neither argument is modified.
+func Merge(base, override *observabilityv1alpha1.LimitSpec) (*observabilityv1alpha1.LimitSpec, error) {
+	if base == nil {
+		return override.DeepCopy(), nil
+	}
+	if override == nil {
+		return base.DeepCopy(), nil
+	}
+
+	merged, err := mergeJSON(base, override)
+	if err != nil {
+		return nil, err
+	}
+	out := &observabilityv1alpha1.LimitSpec{}
+	if err := json.Unmarshal(merged, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func mergeJSON(base, override interface{}) ([]byte, error) {
+	var b, o map[string]interface{}
+	if err := roundTrip(base, &b); err != nil {
+		return nil, err
+	}
+	if err := roundTrip(override, &o); err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergeObjects(b, o))
+}
+
+func mergeObjects(base, override map[string]interface{}) map[string]interface{} {
+	if base == nil {
+		base = map[string]interface{}{}
+	}
+	for k, v := range override {
+		if ov, ok := v.(map[string]interface{}); ok {
+			if bv, ok := base[k].(map[string]interface{}); ok {
+				base[k] = mergeObjects(bv, ov)
+				continue
+			}
+		}
+		base[k] = v
+	}
+	return base
+}
+
+func roundTrip(in interface{}, out *map[string]interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
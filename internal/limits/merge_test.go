@@ -0,0 +1,77 @@
+package limits_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/internal/limits"
+)
+
+var _ = Describe("Merge", func() {
+	rate := func(f float64) *float64 { return &f }
+	count := func(i int) *int { return &i }
+
+	It("returns a copy of whichever side is set", func() {
+		base := &observabilityv1alpha1.LimitSpec{Mimir: &observabilityv1alpha1.MimirLimits{IngestionRate: rate(10)}}
+
+		merged, err := limits.Merge(base, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(merged).To(Equal(base))
+		Expect(merged).NotTo(BeIdenticalTo(base))
+
+		merged, err = limits.Merge(nil, base)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(merged).To(Equal(base))
+
+		merged, err = limits.Merge(nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(merged).To(BeNil())
+	})
+
+	It("overrides the limits set on both sides and keeps the others", func() {
+		base := &observabilityv1alpha1.LimitSpec{
+			Mimir: &observabilityv1alpha1.MimirLimits{
+				IngestionRate:      rate(10),
+				IngestionBurstSize: count(100),
+				DropLabels:         []string{"a", "b"},
+			},
+			Loki: &observabilityv1alpha1.LokiLimits{MaxGlobalStreamsPerUser: count(5)},
+		}
+		override := &observabilityv1alpha1.LimitSpec{
+			Mimir: &observabilityv1alpha1.MimirLimits{
+				IngestionRate: rate(20),
+				DropLabels:    []string{"c"},
+			},
+		}
+
+		merged, err := limits.Merge(base, override)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(merged).To(Equal(&observabilityv1alpha1.LimitSpec{
+			Mimir: &observabilityv1alpha1.MimirLimits{
+				IngestionRate:      rate(20),
+				IngestionBurstSize: count(100),
+				DropLabels:         []string{"c"},
+			},
+			Loki: &observabilityv1alpha1.LokiLimits{MaxGlobalStreamsPerUser: count(5)},
+		}))
+		Expect(*base.Mimir.IngestionRate).To(Equal(10.0))
+	})
+
+	It("merges the extra limits key by key", func() {
+		base := &observabilityv1alpha1.LimitSpec{
+			Mimir: &observabilityv1alpha1.MimirLimits{
+				Extra: &observabilityv1alpha1.WrappedMap{Object: map[string]interface{}{"a": "base", "b": "base"}},
+			},
+		}
+		override := &observabilityv1alpha1.LimitSpec{
+			Mimir: &observabilityv1alpha1.MimirLimits{
+				Extra: &observabilityv1alpha1.WrappedMap{Object: map[string]interface{}{"b": "override"}},
+			},
+		}
+
+		merged, err := limits.Merge(base, override)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(merged.Mimir.Extra.Object).To(Equal(map[string]interface{}{"a": "base", "b": "override"}))
+	})
+})
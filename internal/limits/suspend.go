@@ -0,0 +1,64 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package limits
+
+import (
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+)
+
+// blockAllPattern matches every query.
+const blockAllPattern = ".*"
+
+// Suspend returns a copy of spec with the ingestion rate and burst limits of
+// every backend set to zero, and, if blockQueries is set, a blocked query
+// matching every Mimir and Loki query appended to the blocked queries.
+func Suspend(spec *observabilityv1alpha1.LimitSpec, blockQueries bool) *observabilityv1alpha1.LimitSpec {
+	out := spec.DeepCopy()
+	if out == nil {
+		out = &observabilityv1alpha1.LimitSpec{}
+	}
+	if out.Mimir == nil {
+		out.Mimir = &observabilityv1alpha1.MimirLimits{}
+	}
+	if out.Loki == nil {
+		out.Loki = &observabilityv1alpha1.LokiLimits{}
+	}
+	if out.Tempo == nil {
+		out.Tempo = &observabilityv1alpha1.TempoLimits{}
+	}
+
+	zeroFloat, zeroInt := float64(0), 0
+	out.Mimir.IngestionRate = &zeroFloat
+	out.Mimir.IngestionBurstSize = &zeroInt
+	out.Loki.IngestionRateMB = &zeroFloat
+	out.Loki.IngestionBurstSizeMB = &zeroFloat
+	out.Tempo.IngestionRateLimitBytes = &zeroInt
+	out.Tempo.IngestionBurstSizeBytes = &zeroInt
+
+	if blockQueries {
+		pattern, regex := blockAllPattern, true
+		out.Mimir.BlockedQueries = append(out.Mimir.BlockedQueries, observabilityv1alpha1.MimirBlockedQuery{
+			Pattern: &pattern,
+			Regex:   &regex,
+		})
+		out.Loki.BlockedQueries = append(out.Loki.BlockedQueries, observabilityv1alpha1.BlockedQuery{
+			Pattern: &pattern,
+			Regex:   &regex,
+		})
+	}
+	return out
+}
@@ -0,0 +1,47 @@
+package limits_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/internal/limits"
+)
+
+var _ = Describe("Suspend", func() {
+	rate := func(f float64) *float64 { return &f }
+	count := func(i int) *int { return &i }
+
+	It("zeroes the ingestion limits and keeps the others", func() {
+		spec := &observabilityv1alpha1.LimitSpec{
+			Mimir: &observabilityv1alpha1.MimirLimits{IngestionRate: rate(100), MaxGlobalSeriesPerUser: count(1000)},
+		}
+
+		suspended := limits.Suspend(spec, false)
+		Expect(*suspended.Mimir.IngestionRate).To(BeZero())
+		Expect(*suspended.Mimir.IngestionBurstSize).To(BeZero())
+		Expect(*suspended.Mimir.MaxGlobalSeriesPerUser).To(Equal(1000))
+		Expect(*suspended.Loki.IngestionRateMB).To(BeZero())
+		Expect(*suspended.Tempo.IngestionRateLimitBytes).To(BeZero())
+		Expect(suspended.Mimir.BlockedQueries).To(BeEmpty())
+		Expect(suspended.Loki.BlockedQueries).To(BeEmpty())
+
+		Expect(*spec.Mimir.IngestionRate).To(Equal(100.0))
+		Expect(spec.Loki).To(BeNil())
+	})
+
+	It("blocks every query on request", func() {
+		pattern := "sum(rate(foo[5m]))"
+		spec := &observabilityv1alpha1.LimitSpec{
+			Loki: &observabilityv1alpha1.LokiLimits{BlockedQueries: []observabilityv1alpha1.BlockedQuery{{Pattern: &pattern}}},
+		}
+
+		suspended := limits.Suspend(spec, true)
+		Expect(suspended.Mimir.BlockedQueries).To(ConsistOf(And(
+			HaveField("Pattern", HaveValue(Equal(".*"))),
+			HaveField("Regex", HaveValue(BeTrue())),
+		)))
+		Expect(suspended.Loki.BlockedQueries).To(HaveLen(2))
+		Expect(spec.Loki.BlockedQueries).To(HaveLen(1))
+	})
+})
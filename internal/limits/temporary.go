@@ -0,0 +1,66 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package limits
+
+import (
+	"time"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+)
+
+// TemporaryState returns the state of the temporary limits at now.
+func TemporaryState(t observabilityv1alpha1.TemporaryLimitSpec, now time.Time) observabilityv1alpha1.TemporaryLimitState {
+	switch {
+	case t.Start != nil && now.Before(t.Start.Time):
+		return observabilityv1alpha1.TemporaryLimitPending
+	case now.Before(t.Expiry.Time):
+		return observabilityv1alpha1.TemporaryLimitActive
+	default:
+		return observabilityv1alpha1.TemporaryLimitExpired
+	}
+}
+
+// ApplyTemporary returns spec with the temporary limits that are active at now
+// merged on top of it in order. It also returns the next time any of the
+// temporary limits starts or expires, which is zero if none will.
+func ApplyTemporary(spec *observabilityv1alpha1.LimitSpec, temporary []observabilityv1alpha1.TemporaryLimitSpec, now time.Time) (*observabilityv1alpha1.LimitSpec, time.Time, error) {
+	var next time.Time
+	later := func(t time.Time) {
+		if t.After(now) && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+
+	out := spec
+	for i := range temporary {
+		t := &temporary[i]
+		if t.Start != nil {
+			later(t.Start.Time)
+		}
+		later(t.Expiry.Time)
+
+		if TemporaryState(*t, now) != observabilityv1alpha1.TemporaryLimitActive {
+			continue
+		}
+		merged, err := Merge(out, &t.Limits)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		out = merged
+	}
+	return out, next, nil
+}
@@ -0,0 +1,63 @@
+package limits_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/internal/limits"
+)
+
+var _ = Describe("Temporary limits", func() {
+	rate := func(f float64) *float64 { return &f }
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *metav1.Time {
+		t := metav1.NewTime(now.Add(d))
+		return &t
+	}
+	temporary := func(name string, start *metav1.Time, expiry time.Duration, ingestionRate float64) observabilityv1alpha1.TemporaryLimitSpec {
+		return observabilityv1alpha1.TemporaryLimitSpec{
+			Name:   name,
+			Start:  start,
+			Expiry: *at(expiry),
+			Limits: observabilityv1alpha1.LimitSpec{
+				Mimir: &observabilityv1alpha1.MimirLimits{IngestionRate: rate(ingestionRate)},
+			},
+		}
+	}
+
+	spec := &observabilityv1alpha1.LimitSpec{
+		Mimir: &observabilityv1alpha1.MimirLimits{IngestionRate: rate(10)},
+	}
+
+	It("reports the state of temporary limits", func() {
+		Expect(limits.TemporaryState(temporary("a", at(time.Hour), 2*time.Hour, 0), now)).To(Equal(observabilityv1alpha1.TemporaryLimitPending))
+		Expect(limits.TemporaryState(temporary("a", nil, time.Hour, 0), now)).To(Equal(observabilityv1alpha1.TemporaryLimitActive))
+		Expect(limits.TemporaryState(temporary("a", at(-time.Hour), 0, 0), now)).To(Equal(observabilityv1alpha1.TemporaryLimitExpired))
+	})
+
+	It("merges the active temporary limits in order", func() {
+		merged, next, err := limits.ApplyTemporary(spec, []observabilityv1alpha1.TemporaryLimitSpec{
+			temporary("load-test", at(-time.Hour), time.Hour, 100),
+			temporary("incident", nil, 3*time.Hour, 200),
+			temporary("expired", at(-2*time.Hour), -time.Hour, 300),
+			temporary("next-week", at(30*time.Minute), 2*time.Hour, 400),
+		}, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*merged.Mimir.IngestionRate).To(Equal(200.0))
+		Expect(next).To(Equal(now.Add(30 * time.Minute)))
+		Expect(*spec.Mimir.IngestionRate).To(Equal(10.0))
+	})
+
+	It("returns the limits unchanged without active temporary limits", func() {
+		merged, next, err := limits.ApplyTemporary(spec, []observabilityv1alpha1.TemporaryLimitSpec{
+			temporary("expired", nil, -time.Hour, 300),
+		}, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(merged).To(Equal(spec))
+		Expect(next.IsZero()).To(BeTrue())
+	})
+})
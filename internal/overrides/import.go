@@ -0,0 +1,372 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package overrides reads the runtime overrides of existing Mimir, Loki and
+// Tempo deployments and merges the keys the controller does not own back
+// into the ones it renders.
+package overrides
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+)
+
+// UnmappedField is a field of the overrides of a tenant that has no
+// equivalent in the limits of a Tenant, or whose value does not fit it.
+type UnmappedField struct {
+	// Backend is mimir, loki or tempo.
+	Backend string
+	// TenantID is the tenant the field is overridden for.
+	TenantID string
+	// Field is the path of the field, e.g. ingestion_rate or
+	// ruler_remote_write_config.default.url.
+	Field string
+	// Reason is why the field could not be mapped.
+	Reason string
+}
+
+func (f UnmappedField) String() string {
+	return fmt.Sprintf("%s overrides of tenant %s: %s: %s", f.Backend, f.TenantID, f.Field, f.Reason)
+}
+
+// Result holds the limits imported from runtime overrides.
+type Result struct {
+	// Limits are the limits of the tenants keyed by tenant ID.
+	Limits map[string]*observabilityv1alpha1.LimitSpec
+	// Unmapped are the fields that could not be imported.
+	Unmapped []UnmappedField
+}
+
+type backend struct {
+	name      string
+	newLimits func() interface{}
+	setLimits func(spec *observabilityv1alpha1.LimitSpec, limits interface{})
+	// convert turns the runtime configuration into the form of the Tenant
+	// API, if they differ.
+	convert func(data []byte) ([]byte, error)
+}
+
+var backends = []backend{
+	{
+		name:      "mimir",
+		newLimits: func() interface{} { return &observabilityv1alpha1.MimirLimits{} },
+		setLimits: func(spec *observabilityv1alpha1.LimitSpec, limits interface{}) {
+			spec.Mimir = limits.(*observabilityv1alpha1.MimirLimits)
+		},
+	},
+	{
+		name:      "loki",
+		newLimits: func() interface{} { return &observabilityv1alpha1.LokiLimits{} },
+		setLimits: func(spec *observabilityv1alpha1.LimitSpec, limits interface{}) {
+			spec.Loki = limits.(*observabilityv1alpha1.LokiLimits)
+		},
+		convert: SplitBlockedQueryTypes,
+	},
+	{
+		name:      "tempo",
+		newLimits: func() interface{} { return &observabilityv1alpha1.TempoLimits{} },
+		setLimits: func(spec *observabilityv1alpha1.LimitSpec, limits interface{}) {
+			spec.Tempo = limits.(*observabilityv1alpha1.TempoLimits)
+		},
+		convert: RestoreDimensionMappings,
+	},
+}
+
+// Import parses the runtime configuration files of Mimir, Loki and Tempo,
+// any of which may be empty, into the limits of their tenants. Fields that
+// are unknown or whose value does not fit the limits are left out and
+// reported.
+func Import(mimir, loki, tempo string) (*Result, error) {
+	result := &Result{Limits: map[string]*observabilityv1alpha1.LimitSpec{}}
+	for i, data := range []string{mimir, loki, tempo} {
+		if err := result.importBackend(backends[i], data); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (r *Result) importBackend(b backend, data string) error {
+	raw := []byte(data)
+	if b.convert != nil {
+		var err error
+		if raw, err = b.convert(raw); err != nil {
+			return fmt.Errorf("unable to parse %s runtime configuration: %w", b.name, err)
+		}
+	}
+	runtimeConfig := struct {
+		Overrides map[string]map[string]interface{} `json:"overrides"`
+	}{}
+	if err := yaml.Unmarshal(raw, &runtimeConfig); err != nil {
+		return fmt.Errorf("unable to parse %s runtime configuration: %w", b.name, err)
+	}
+
+	types := fields(reflect.TypeOf(b.newLimits()).Elem())
+	var unmapped []UnmappedField
+	for tenantID, fields := range runtimeConfig.Overrides {
+		limits := b.newLimits()
+		for _, key := range sortedKeys(fields) {
+			field := map[string]interface{}{key: normalizeDurations(fields[key], types[key])}
+			data, err := json.Marshal(field)
+			if err != nil {
+				return err
+			}
+			// Each field is first decoded on its own, so that a field that
+			// does not fit cannot leave the limits partially set.
+			single := b.newLimits()
+			if err := json.Unmarshal(data, single); err != nil {
+				unmapped = append(unmapped, UnmappedField{Backend: b.name, TenantID: tenantID, Field: key, Reason: typeErrorReason(err)})
+				continue
+			}
+			mapped, err := toMap(single)
+			if err != nil {
+				return err
+			}
+			missing := missingFields(field, mapped, "")
+			for _, m := range missing {
+				unmapped = append(unmapped, UnmappedField{Backend: b.name, TenantID: tenantID, Field: m, Reason: "unknown field"})
+			}
+			if len(missing) > 0 && missing[0] == key {
+				continue
+			}
+			if err := json.Unmarshal(data, limits); err != nil {
+				return err
+			}
+		}
+
+		spec := r.Limits[tenantID]
+		if spec == nil {
+			spec = &observabilityv1alpha1.LimitSpec{}
+			r.Limits[tenantID] = spec
+		}
+		if !reflect.ValueOf(limits).Elem().IsZero() || len(fields) == 0 {
+			b.setLimits(spec, limits)
+		}
+	}
+	sort.Slice(unmapped, func(i, j int) bool {
+		if unmapped[i].TenantID != unmapped[j].TenantID {
+			return unmapped[i].TenantID < unmapped[j].TenantID
+		}
+		return unmapped[i].Field < unmapped[j].Field
+	})
+	r.Unmapped = append(r.Unmapped, unmapped...)
+	return nil
+}
+
+var durationType = reflect.TypeOf(metav1.Duration{})
+
+// normalizeDurations returns value, a field of the type t, with the durations
+// in it that use the units the backends accept but Go does not, e.g. 30d or
+// 1y, rewritten in hours so that they decode into a metav1.Duration.
+func normalizeDurations(value interface{}, t reflect.Type) interface{} {
+	t = indirect(t)
+	if t == nil {
+		return value
+	}
+	switch v := value.(type) {
+	case string:
+		if t != durationType {
+			return v
+		}
+		d, err := model.ParseDuration(v)
+		if err != nil {
+			return v
+		}
+		return time.Duration(d).String()
+	case map[string]interface{}:
+		var types map[string]reflect.Type
+		switch t.Kind() {
+		case reflect.Struct:
+			types = fields(t)
+		case reflect.Map:
+		default:
+			return v
+		}
+		out := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			if types != nil {
+				out[k] = normalizeDurations(e, types[k])
+			} else {
+				out[k] = normalizeDurations(e, t.Elem())
+			}
+		}
+		return out
+	case []interface{}:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return v
+		}
+		out := make([]interface{}, len(v))
+		for i, e := range v {
+			out[i] = normalizeDurations(e, t.Elem())
+		}
+		return out
+	}
+	return value
+}
+
+// Tenants returns a Tenant for every imported tenant, sorted by name. The
+// Tenant is named after the tenant ID if it is a valid name, otherwise the
+// tenant ID is set explicitly.
+func (r *Result) Tenants() []observabilityv1alpha1.Tenant {
+	ids := make([]string, 0, len(r.Limits))
+	for id := range r.Limits {
+		ids = append(ids, id)
+	}
+	// Tenant IDs that are valid names keep them, the others are named after
+	// them with a suffix if that name is taken.
+	sort.SliceStable(ids, func(i, j int) bool {
+		vi, vj := TenantName(ids[i]) == ids[i], TenantName(ids[j]) == ids[j]
+		if vi != vj {
+			return vi
+		}
+		return ids[i] < ids[j]
+	})
+
+	names := map[string]bool{}
+	tenants := make([]observabilityv1alpha1.Tenant, 0, len(ids))
+	for _, id := range ids {
+		name := TenantName(id)
+		for i := 2; names[name]; i++ {
+			name = fmt.Sprintf("%s-%d", TenantName(id), i)
+		}
+		names[name] = true
+
+		tenant := observabilityv1alpha1.Tenant{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: observabilityv1alpha1.GroupVersion.String(),
+				Kind:       "Tenant",
+			},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: observabilityv1alpha1.TenantSpec{
+				Limits: r.Limits[id].DeepCopy(),
+			},
+		}
+		if name != id {
+			tenant.Spec.TenantID = id
+		}
+		tenants = append(tenants, tenant)
+	}
+	sort.Slice(tenants, func(i, j int) bool {
+		return tenants[i].Name < tenants[j].Name
+	})
+	return tenants
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// TenantName returns the name of the Tenant for a tenant ID, which is the
+// tenant ID itself if it is a valid name.
+func TenantName(tenantID string) string {
+	if len(validation.IsDNS1123Subdomain(tenantID)) == 0 {
+		return tenantID
+	}
+	name := invalidNameChars.ReplaceAllString(strings.ToLower(tenantID), "-")
+	if len(name) > validation.DNS1123SubdomainMaxLength {
+		name = name[:validation.DNS1123SubdomainMaxLength]
+	}
+	name = strings.Trim(name, ".-")
+	if name == "" {
+		return "tenant"
+	}
+	return name
+}
+
+// typeErrorReason describes why the value of a field could not be decoded.
+func typeErrorReason(err error) string {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return fmt.Sprintf("cannot use a %s value as %s", typeErr.Value, typeErr.Type)
+	}
+	return err.Error()
+}
+
+func toMap(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]interface{}{}
+	return out, json.Unmarshal(data, &out)
+}
+
+// missingFields returns the paths of the fields of orig that are missing in
+// mapped, which is orig after it was decoded into the limits and encoded
+// again.
+func missingFields(orig, mapped interface{}, path string) []string {
+	var out []string
+	switch orig := orig.(type) {
+	case map[string]interface{}:
+		m, _ := mapped.(map[string]interface{})
+		for _, k := range sortedKeys(orig) {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			v, ok := m[k]
+			if !ok {
+				if !isEmpty(orig[k]) {
+					out = append(out, p)
+				}
+				continue
+			}
+			out = append(out, missingFields(orig[k], v, p)...)
+		}
+	case []interface{}:
+		l, _ := mapped.([]interface{})
+		for i := range orig {
+			if i < len(l) {
+				out = append(out, missingFields(orig[i], l[i], fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	}
+	return out
+}
+
+// isEmpty reports whether v is null or an empty map or list, which the
+// limits omit when they are encoded.
+func isEmpty(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
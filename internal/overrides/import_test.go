@@ -0,0 +1,144 @@
+package overrides_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/internal/overrides"
+)
+
+var _ = Describe("Import", func() {
+	const mimir = `
+overrides:
+  team-a:
+    ingestion_rate: 10000
+    max_query_lookback: 720h
+    not_a_limit: true
+  Team_B:
+    ingestion_rate: fast
+    max_global_series_per_user: 150000
+multi_kv_config:
+  primary: consul
+`
+	const loki = `
+overrides:
+  team-a:
+    ingestion_rate_mb: 8
+    blocked_queries:
+    - pattern: 'rate({app="foo"}[5m])'
+      types: metric,filter
+`
+	const tempo = `
+overrides:
+  team-c: {}
+`
+
+	It("imports the limits of every tenant", func() {
+		result, err := overrides.Import(mimir, loki, tempo)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Limits).To(HaveLen(3))
+
+		teamA := result.Limits["team-a"]
+		Expect(*teamA.Mimir.IngestionRate).To(Equal(float64(10000)))
+		Expect(teamA.Mimir.MaxQueryLookback.Duration).To(Equal(720 * time.Hour))
+		Expect(*teamA.Loki.IngestionRateMB).To(Equal(float64(8)))
+		Expect(teamA.Loki.BlockedQueries[0].Types).To(Equal(observabilityv1alpha1.BlockedQueryTypes{
+			observabilityv1alpha1.BlockedQueryTypeMetric,
+			observabilityv1alpha1.BlockedQueryTypeFilter,
+		}))
+		Expect(teamA.Tempo).To(BeNil())
+
+		teamB := result.Limits["Team_B"]
+		Expect(teamB.Mimir.IngestionRate).To(BeNil())
+		Expect(*teamB.Mimir.MaxGlobalSeriesPerUser).To(Equal(150000))
+
+		Expect(result.Limits["team-c"].Tempo).To(Equal(&observabilityv1alpha1.TempoLimits{}))
+	})
+
+	It("reports fields it could not map", func() {
+		result, err := overrides.Import(mimir, loki, tempo)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Unmapped).To(ConsistOf(
+			overrides.UnmappedField{Backend: "mimir", TenantID: "Team_B", Field: "ingestion_rate", Reason: "cannot use a string value as float64"},
+			overrides.UnmappedField{Backend: "mimir", TenantID: "team-a", Field: "not_a_limit", Reason: "unknown field"},
+		))
+		Expect(result.Unmapped[0].String()).To(Equal("mimir overrides of tenant Team_B: ingestion_rate: cannot use a string value as float64"))
+	})
+
+	It("reports unknown nested fields", func() {
+		result, err := overrides.Import("", `
+overrides:
+  team-a:
+    ruler_remote_write_config:
+      default:
+        url: http://prometheus/api/v1/write
+        unknown_option: 1
+`, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Unmapped).To(ConsistOf(HaveField("Field", "ruler_remote_write_config.default.unknown_option")))
+	})
+
+	It("imports durations in days, weeks and years", func() {
+		result, err := overrides.Import(`
+overrides:
+  team-a:
+    max_query_lookback: 2w
+    compactor_blocks_retention_period: 1y
+`, `
+overrides:
+  team-a:
+    retention_period: 30d
+    retention_stream:
+    - selector: '{namespace="dev"}'
+      priority: 1
+      period: 1w2d
+`, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Unmapped).To(BeEmpty())
+
+		teamA := result.Limits["team-a"]
+		Expect(teamA.Mimir.MaxQueryLookback.Duration).To(Equal(14 * 24 * time.Hour))
+		Expect(teamA.Mimir.CompactorBlocksRetentionPeriod.Duration).To(Equal(365 * 24 * time.Hour))
+		Expect(teamA.Loki.RetentionPeriod.Duration).To(Equal(30 * 24 * time.Hour))
+		Expect(teamA.Loki.StreamRetention[0].Period.Duration).To(Equal(9 * 24 * time.Hour))
+	})
+
+	It("reports durations that do not parse", func() {
+		result, err := overrides.Import("", "overrides:\n  team-a:\n    retention_period: 30days\n", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Unmapped).To(ConsistOf(HaveField("Field", "retention_period")))
+	})
+
+	It("rejects runtime configurations that do not parse", func() {
+		_, err := overrides.Import("overrides: [", "", "")
+		Expect(err).To(MatchError(ContainSubstring("unable to parse mimir runtime configuration")))
+	})
+
+	It("creates a Tenant per tenant ID", func() {
+		result, err := overrides.Import(mimir, loki, tempo)
+		Expect(err).NotTo(HaveOccurred())
+		tenants := result.Tenants()
+		Expect(tenants).To(HaveLen(3))
+
+		Expect(tenants[0].Name).To(Equal("team-a"))
+		Expect(tenants[0].Spec.TenantID).To(BeEmpty())
+		Expect(tenants[0].Kind).To(Equal("Tenant"))
+		Expect(tenants[1].Name).To(Equal("team-b"))
+		Expect(tenants[1].Spec.TenantID).To(Equal("Team_B"))
+		Expect(tenants[2].Name).To(Equal("team-c"))
+	})
+
+	It("keeps Tenant names unique", func() {
+		result, err := overrides.Import("overrides:\n  team-b: {}\n  Team_B: {}\n", "", "")
+		Expect(err).NotTo(HaveOccurred())
+		tenants := result.Tenants()
+		Expect(tenants).To(HaveLen(2))
+		Expect(tenants[0].Name).To(Equal("team-b"))
+		Expect(tenants[0].Spec.TenantID).To(BeEmpty())
+		Expect(tenants[1].Name).To(Equal("team-b-2"))
+		Expect(tenants[1].Spec.TenantID).To(Equal("Team_B"))
+	})
+})
@@ -0,0 +1,85 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overrides
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	blockedQueriesKey = "blocked_queries"
+	typesKey          = "types"
+)
+
+// JoinBlockedQueryTypes returns the Loki runtime configuration rendered by the
+// controller with the types of the blocked queries of every tenant written as
+// the comma-separated string Loki parses, rather than as the list the Tenant
+// API takes.
+func JoinBlockedQueryTypes(rendered []byte) ([]byte, error) {
+	return mapBlockedQueryTypes(rendered, func(types interface{}) interface{} {
+		list, ok := types.([]interface{})
+		if !ok {
+			return types
+		}
+		names := make([]string, 0, len(list))
+		for _, t := range list {
+			names = append(names, fmt.Sprint(t))
+		}
+		return strings.Join(names, ",")
+	})
+}
+
+// SplitBlockedQueryTypes returns a Loki runtime configuration with the types
+// of the blocked queries of every tenant written as the list the Tenant API
+// takes, undoing JoinBlockedQueryTypes.
+func SplitBlockedQueryTypes(data []byte) ([]byte, error) {
+	return mapBlockedQueryTypes(data, func(types interface{}) interface{} {
+		s, ok := types.(string)
+		if !ok {
+			return types
+		}
+		var list []interface{}
+		for _, t := range strings.Split(s, ",") {
+			list = append(list, strings.TrimSpace(t))
+		}
+		return list
+	})
+}
+
+// mapBlockedQueryTypes replaces the types of the blocked queries of every
+// tenant of the Loki runtime configuration with their value under f.
+func mapBlockedQueryTypes(data []byte, f func(types interface{}) interface{}) ([]byte, error) {
+	out, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+	tenants, _ := out[overridesKey].(map[string]interface{})
+	for _, limits := range tenants {
+		fields, _ := limits.(map[string]interface{})
+		queries, _ := fields[blockedQueriesKey].([]interface{})
+		for _, q := range queries {
+			query, _ := q.(map[string]interface{})
+			if types, ok := query[typesKey]; ok {
+				query[typesKey] = f(types)
+			}
+		}
+	}
+	return yaml.Marshal(out)
+}
@@ -0,0 +1,51 @@
+package overrides_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/traceshield/trace-shield-controller/internal/overrides"
+)
+
+var _ = Describe("Loki blocked queries", func() {
+	const (
+		list = `
+overrides:
+  team-a:
+    blocked_queries:
+    - pattern: 'rate({app="foo"}[5m])'
+      types:
+      - metric
+      - filter
+    - hash: 2943214005
+`
+		joined = `
+overrides:
+  team-a:
+    blocked_queries:
+    - pattern: 'rate({app="foo"}[5m])'
+      types: metric,filter
+    - hash: 2943214005
+`
+	)
+
+	parse := func(data []byte) map[string]interface{} {
+		out := map[string]interface{}{}
+		ExpectWithOffset(1, yaml.Unmarshal(data, &out)).To(Succeed())
+		return out
+	}
+
+	It("writes the types of blocked queries as a comma-separated string", func() {
+		out, err := overrides.JoinBlockedQueryTypes([]byte(list))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parse(out)).To(Equal(parse([]byte(joined))))
+	})
+
+	It("reads the types of blocked queries back as a list", func() {
+		out, err := overrides.SplitBlockedQueryTypes([]byte(joined))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parse(out)).To(Equal(parse([]byte(list))))
+	})
+})
@@ -0,0 +1,343 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overrides
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+)
+
+// Schema is the part of a runtime configuration the controller owns: the
+// overrides, the fields of the global configuration and the fields of the
+// overrides of a tenant.
+type Schema struct {
+	// Config is the global configuration written next to the overrides, nil
+	// if there is none.
+	Config reflect.Type
+	// Limits are the overrides of a tenant.
+	Limits reflect.Type
+}
+
+var (
+	// MimirSchema is the part of the Mimir runtime configuration the controller owns.
+	MimirSchema = Schema{
+		Config: reflect.TypeOf(observabilityv1alpha1.MimirConfigSpec{}),
+		Limits: reflect.TypeOf(observabilityv1alpha1.MimirLimits{}),
+	}
+	// LokiSchema is the part of the Loki runtime configuration the controller owns.
+	LokiSchema = Schema{
+		Config: reflect.TypeOf(observabilityv1alpha1.LokiConfigSpec{}),
+		Limits: reflect.TypeOf(observabilityv1alpha1.LokiLimits{}),
+	}
+	// TempoSchema is the part of the Tempo runtime configuration the controller owns.
+	TempoSchema = Schema{
+		Limits: reflect.TypeOf(observabilityv1alpha1.TempoLimits{}),
+	}
+)
+
+const (
+	overridesKey = "overrides"
+	extraKey     = "extra"
+)
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// Unowned returns the paths of the keys of a runtime configuration the
+// controller does not own, e.g. ingester_limits.new_limit for an unknown
+// key, overrides.team-a.new_option for an unknown field of the overrides of a
+// tenant and overrides.legacy for the overrides of a tenant that is not
+// managed. Every field of the overrides of the managed tenants in extra,
+// whose limits set extra, is owned.
+func Unowned(data string, schema Schema, managed, extra map[string]bool) ([]string, error) {
+	config, err := parse([]byte(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var out []string
+	fs := schema.fields()
+	for _, key := range sortedKeys(config) {
+		if key != overridesKey {
+			out = append(out, unownedFields(map[string]interface{}{key: config[key]}, fs, "")...)
+			continue
+		}
+		tenants, _ := config[overridesKey].(map[string]interface{})
+		for _, tenantID := range sortedKeys(tenants) {
+			path := join(overridesKey, tenantID)
+			if !managed[tenantID] {
+				out = append(out, path)
+				continue
+			}
+			if extra[tenantID] {
+				continue
+			}
+			out = append(out, unowned(tenants[tenantID], schema.Limits, path)...)
+		}
+	}
+	return out, nil
+}
+
+// Preserve returns the runtime configuration rendered by the controller with
+// the keys of the existing runtime configuration it does not own added back.
+// Keys below a field the controller owns are only added back if the field is
+// still rendered, and the overrides of tenants that are not managed are
+// already rendered as they were read. Nothing is added back to overrides that
+// set extra, as those own all their fields.
+func Preserve(rendered []byte, existing string, schema Schema) ([]byte, error) {
+	out, err := parse(rendered)
+	if err != nil {
+		return nil, err
+	}
+	config, err := parse([]byte(existing))
+	if err != nil {
+		return nil, err
+	}
+	preserveFields(out, config, schema.fields())
+	return yaml.Marshal(out)
+}
+
+// Drop returns the runtime configuration rendered by the controller without
+// the overrides of tenants that are not managed.
+func Drop(rendered []byte, managed map[string]bool) ([]byte, error) {
+	out, err := parse(rendered)
+	if err != nil {
+		return nil, err
+	}
+	tenants, _ := out[overridesKey].(map[string]interface{})
+	for tenantID := range tenants {
+		if !managed[tenantID] {
+			delete(tenants, tenantID)
+		}
+	}
+	return yaml.Marshal(out)
+}
+
+// ExtraKeys are the keys merged from the extra limits into the overrides of
+// each tenant, by tenant ID.
+type ExtraKeys map[string][]string
+
+// InlineExtra merges the extra limits of every tenant into its overrides,
+// leaving the fields it already sets as they are, and returns the keys it
+// merged.
+func InlineExtra(rendered []byte) ([]byte, ExtraKeys, error) {
+	out, err := parse(rendered)
+	if err != nil {
+		return nil, nil, err
+	}
+	inlined := ExtraKeys{}
+	tenants, _ := out[overridesKey].(map[string]interface{})
+	for tenantID, limits := range tenants {
+		fields, _ := limits.(map[string]interface{})
+		extra, ok := fields[extraKey]
+		if !ok {
+			continue
+		}
+		delete(fields, extraKey)
+		extraFields, _ := extra.(map[string]interface{})
+		for _, key := range sortedKeys(extraFields) {
+			if _, ok := fields[key]; !ok {
+				fields[key] = extraFields[key]
+				inlined[tenantID] = append(inlined[tenantID], key)
+			}
+		}
+	}
+	rendered, err = yaml.Marshal(out)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rendered, inlined, nil
+}
+
+// WithoutExtra returns the existing runtime configuration without the keys
+// that were merged from the extra limits when it was rendered. Those keys are
+// owned by the controller even though it does not model them, so they are
+// neither preserved nor reported as unowned once they leave the extra limits.
+func WithoutExtra(existing string, inlined ExtraKeys) (string, error) {
+	if len(inlined) == 0 {
+		return existing, nil
+	}
+	config, err := parse([]byte(existing))
+	if err != nil {
+		return "", err
+	}
+	tenants, _ := config[overridesKey].(map[string]interface{})
+	for tenantID, keys := range inlined {
+		fields, _ := tenants[tenantID].(map[string]interface{})
+		for _, key := range keys {
+			delete(fields, key)
+		}
+	}
+	out, err := yaml.Marshal(config)
+	return string(out), err
+}
+
+// fields returns the types of the top-level keys of the runtime configuration.
+func (s Schema) fields() map[string]reflect.Type {
+	fs := fields(s.Config)
+	fs[overridesKey] = reflect.MapOf(reflect.TypeOf(""), s.Limits)
+	return fs
+}
+
+// unownedFields returns the paths of the keys of value, a decoded struct with
+// the given fields, that the fields do not model.
+func unownedFields(value map[string]interface{}, fs map[string]reflect.Type, path string) []string {
+	var out []string
+	for _, key := range sortedKeys(value) {
+		p := join(path, key)
+		ft, ok := fs[key]
+		if !ok {
+			out = append(out, p)
+			continue
+		}
+		out = append(out, unowned(value[key], ft, p)...)
+	}
+	return out
+}
+
+// unowned returns the paths of the keys below value that t does not model.
+func unowned(value interface{}, t reflect.Type, path string) []string {
+	t = indirect(t)
+	if opaque(t) {
+		return nil
+	}
+	var out []string
+	switch v := value.(type) {
+	case map[string]interface{}:
+		switch t.Kind() {
+		case reflect.Struct:
+			out = append(out, unownedFields(v, fields(t), path)...)
+		case reflect.Map:
+			for _, key := range sortedKeys(v) {
+				out = append(out, unowned(v[key], t.Elem(), join(path, key))...)
+			}
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, item := range v {
+				out = append(out, unowned(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	}
+	return out
+}
+
+// preserveFields copies the keys of existing that the fields do not model
+// into rendered, both decoded structs with the given fields.
+func preserveFields(rendered, existing map[string]interface{}, fs map[string]reflect.Type) {
+	for key, value := range existing {
+		ft, ok := fs[key]
+		if !ok {
+			rendered[key] = value
+			continue
+		}
+		if r, ok := rendered[key]; ok {
+			preserve(r, value, ft)
+		}
+	}
+}
+
+// preserve copies the keys below existing that t does not model into
+// rendered.
+func preserve(rendered, existing interface{}, t reflect.Type) {
+	t = indirect(t)
+	r, ok := rendered.(map[string]interface{})
+	if !ok || opaque(t) {
+		return
+	}
+	e, ok := existing.(map[string]interface{})
+	if !ok {
+		return
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if _, ok := r[extraKey]; ok {
+			return
+		}
+		preserveFields(r, e, fields(t))
+	case reflect.Map:
+		for key, value := range e {
+			if rv, ok := r[key]; ok {
+				preserve(rv, value, t.Elem())
+			}
+		}
+	}
+}
+
+// fields returns the types of the fields of the struct t by their JSON
+// names, following encoding/json in promoting the fields of embedded structs.
+func fields(t reflect.Type) map[string]reflect.Type {
+	fs := map[string]reflect.Type{}
+	if t == nil {
+		return fs
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" || (!f.IsExported() && !f.Anonymous) {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" && f.Anonymous && indirect(f.Type).Kind() == reflect.Struct {
+			for n, ft := range fields(indirect(f.Type)) {
+				fs[n] = ft
+			}
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fs[name] = f.Type
+	}
+	return fs
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// opaque reports whether t is owned as a whole, because it decodes itself or
+// can hold any value.
+func opaque(t reflect.Type) bool {
+	return t == nil || t.Kind() == reflect.Interface || reflect.PointerTo(t).Implements(unmarshalerType)
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func parse(data []byte) (map[string]interface{}, error) {
+	out := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	if out == nil {
+		out = map[string]interface{}{}
+	}
+	return out, nil
+}
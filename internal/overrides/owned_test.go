@@ -0,0 +1,175 @@
+package overrides_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/traceshield/trace-shield-controller/internal/overrides"
+)
+
+var _ = Describe("Owned keys", func() {
+	const existing = `
+overrides:
+  team-a:
+    ingestion_rate: 10000
+    new_option: 5
+  legacy:
+    ingestion_rate: 500
+    new_option: 3
+multi_kv_config:
+  primary: consul
+ingester_limits:
+  max_tenants: 100
+  new_limit: 1000000
+vendor_section:
+  enabled: true
+`
+	managed := map[string]bool{"team-a": true}
+
+	parse := func(data []byte) map[string]interface{} {
+		out := map[string]interface{}{}
+		ExpectWithOffset(1, yaml.Unmarshal(data, &out)).To(Succeed())
+		return out
+	}
+
+	It("lists the keys the controller does not own", func() {
+		unowned, err := overrides.Unowned(existing, overrides.MimirSchema, managed, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(unowned).To(Equal([]string{
+			"ingester_limits.new_limit",
+			"overrides.legacy",
+			"overrides.team-a.new_option",
+			"vendor_section",
+		}))
+	})
+
+	It("owns every field of the overrides of tenants setting extra", func() {
+		unowned, err := overrides.Unowned(existing, overrides.MimirSchema, managed, map[string]bool{"team-a": true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(unowned).NotTo(ContainElement("overrides.team-a.new_option"))
+	})
+
+	It("lists nothing for an empty runtime configuration", func() {
+		unowned, err := overrides.Unowned("", overrides.TempoSchema, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(unowned).To(BeEmpty())
+	})
+
+	It("preserves the keys the controller does not own", func() {
+		rendered := []byte(`
+overrides:
+  team-a:
+    ingestion_rate: 20000
+  legacy:
+    ingestion_rate: 500
+ingester_limits:
+  max_tenants: 200
+`)
+		out, err := overrides.Preserve(rendered, existing, overrides.MimirSchema)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parse(out)).To(Equal(parse([]byte(`
+overrides:
+  team-a:
+    ingestion_rate: 20000
+    new_option: 5
+  legacy:
+    ingestion_rate: 500
+    new_option: 3
+ingester_limits:
+  max_tenants: 200
+  new_limit: 1000000
+vendor_section:
+  enabled: true
+`))))
+	})
+
+	It("does not preserve the overrides of tenants the controller removed", func() {
+		out, err := overrides.Preserve([]byte("overrides: {}\n"), existing, overrides.MimirSchema)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parse(out)).To(HaveKeyWithValue("overrides", BeEmpty()))
+	})
+
+	It("drops the overrides of tenants that are not managed", func() {
+		rendered := []byte(`
+overrides:
+  team-a:
+    ingestion_rate: 20000
+  legacy:
+    ingestion_rate: 500
+`)
+		out, err := overrides.Drop(rendered, managed)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parse(out)).To(Equal(parse([]byte(`
+overrides:
+  team-a:
+    ingestion_rate: 20000
+`))))
+	})
+
+	It("does not preserve fields of overrides setting extra", func() {
+		rendered := []byte(`
+overrides:
+  team-a:
+    ingestion_rate: 20000
+    extra: {}
+`)
+		out, err := overrides.Preserve(rendered, existing, overrides.MimirSchema)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parse(out)).To(HaveKeyWithValue("overrides", HaveKeyWithValue("team-a", Not(HaveKey("new_option")))))
+	})
+
+	It("merges extra limits into the overrides", func() {
+		rendered := []byte(`
+overrides:
+  team-a:
+    ingestion_rate: 20000
+    extra:
+      ingestion_rate: 1
+      new_option: 7
+  team-b:
+    ingestion_rate: 10
+`)
+		out, inlined, err := overrides.InlineExtra(rendered)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parse(out)).To(Equal(parse([]byte(`
+overrides:
+  team-a:
+    ingestion_rate: 20000
+    new_option: 7
+  team-b:
+    ingestion_rate: 10
+`))))
+		Expect(inlined).To(Equal(overrides.ExtraKeys{"team-a": {"new_option"}}))
+	})
+
+	Context("when extra limits are removed", func() {
+		const inlinedExisting = `
+overrides:
+  team-a:
+    ingestion_rate: 20000
+    new_option: 7
+`
+		inlined := overrides.ExtraKeys{"team-a": {"new_option"}}
+		rendered := []byte(`
+overrides:
+  team-a:
+    ingestion_rate: 20000
+`)
+
+		It("does not preserve the keys merged from them", func() {
+			existing, err := overrides.WithoutExtra(inlinedExisting, inlined)
+			Expect(err).NotTo(HaveOccurred())
+			out, err := overrides.Preserve(rendered, existing, overrides.MimirSchema)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parse(out)).To(HaveKeyWithValue("overrides", HaveKeyWithValue("team-a", Not(HaveKey("new_option")))))
+		})
+
+		It("does not report the keys merged from them as unowned", func() {
+			existing, err := overrides.WithoutExtra(inlinedExisting, inlined)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(overrides.Unowned(existing, overrides.MimirSchema, managed, nil)).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,60 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overrides
+
+import "sigs.k8s.io/yaml"
+
+const (
+	// dimensionMappingsKey is the key Tempo reads the span metrics dimension
+	// mappings of a tenant from.
+	dimensionMappingsKey = "metrics_generator_processor_span_metrics_dimension_mappings"
+	// dimensionMappingsAPIKey is the misspelled key the Tenant API takes the
+	// span metrics dimension mappings under, kept for compatibility.
+	dimensionMappingsAPIKey = "metrics_generator_processor_span_metrics_dimension_mapings"
+)
+
+// RenameDimensionMappings returns the Tempo runtime configuration rendered by
+// the controller with the span metrics dimension mappings of every tenant
+// under the key Tempo reads, rather than the key the Tenant API takes.
+func RenameDimensionMappings(rendered []byte) ([]byte, error) {
+	return renameKey(rendered, dimensionMappingsAPIKey, dimensionMappingsKey)
+}
+
+// RestoreDimensionMappings returns a Tempo runtime configuration with the
+// span metrics dimension mappings of every tenant under the key the Tenant
+// API takes, undoing RenameDimensionMappings.
+func RestoreDimensionMappings(data []byte) ([]byte, error) {
+	return renameKey(data, dimensionMappingsKey, dimensionMappingsAPIKey)
+}
+
+// renameKey moves the value of the key from to the key to in the overrides
+// of every tenant of the runtime configuration.
+func renameKey(data []byte, from, to string) ([]byte, error) {
+	out, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+	tenants, _ := out[overridesKey].(map[string]interface{})
+	for _, limits := range tenants {
+		fields, _ := limits.(map[string]interface{})
+		if value, ok := fields[from]; ok {
+			delete(fields, from)
+			fields[to] = value
+		}
+	}
+	return yaml.Marshal(out)
+}
@@ -0,0 +1,51 @@
+package overrides_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/traceshield/trace-shield-controller/internal/overrides"
+)
+
+var _ = Describe("Tempo dimension mappings", func() {
+	const (
+		api = `
+overrides:
+  team-a:
+    max_traces_per_user: 1000
+    metrics_generator_processor_span_metrics_dimension_mapings:
+    - name: service
+      source_labels:
+      - service.name
+`
+		tempo = `
+overrides:
+  team-a:
+    max_traces_per_user: 1000
+    metrics_generator_processor_span_metrics_dimension_mappings:
+    - name: service
+      source_labels:
+      - service.name
+`
+	)
+
+	parse := func(data []byte) map[string]interface{} {
+		out := map[string]interface{}{}
+		ExpectWithOffset(1, yaml.Unmarshal(data, &out)).To(Succeed())
+		return out
+	}
+
+	It("writes the dimension mappings under the key Tempo reads", func() {
+		out, err := overrides.RenameDimensionMappings([]byte(api))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parse(out)).To(Equal(parse([]byte(tempo))))
+	})
+
+	It("reads the dimension mappings back under the key of the Tenant API", func() {
+		out, err := overrides.RestoreDimensionMappings([]byte(tempo))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parse(out)).To(Equal(parse([]byte(api))))
+	})
+})
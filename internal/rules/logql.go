@@ -0,0 +1,913 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+// unwrapMode is whether a range aggregation works on an unwrapped label.
+type unwrapMode int
+
+const (
+	unwrapForbidden unwrapMode = iota
+	unwrapOptional
+	unwrapRequired
+)
+
+var (
+	logqlRangeOps = map[string]unwrapMode{
+		"count_over_time":    unwrapForbidden,
+		"bytes_rate":         unwrapForbidden,
+		"bytes_over_time":    unwrapForbidden,
+		"absent_over_time":   unwrapForbidden,
+		"rate":               unwrapOptional,
+		"rate_counter":       unwrapRequired,
+		"sum_over_time":      unwrapRequired,
+		"avg_over_time":      unwrapRequired,
+		"max_over_time":      unwrapRequired,
+		"min_over_time":      unwrapRequired,
+		"stdvar_over_time":   unwrapRequired,
+		"stddev_over_time":   unwrapRequired,
+		"quantile_over_time": unwrapRequired,
+		"first_over_time":    unwrapRequired,
+		"last_over_time":     unwrapRequired,
+	}
+
+	// logqlVectorOps are the vector aggregations of LogQL and whether they
+	// take a parameter.
+	logqlVectorOps = map[string]bool{
+		"sum": false, "avg": false, "count": false, "max": false, "min": false,
+		"stddev": false, "stdvar": false, "sort": false, "sort_desc": false,
+		"topk": true, "bottomk": true,
+	}
+
+	// logqlBinaryOps are the binary operators of LogQL and whether they are
+	// comparisons, which may be followed by bool.
+	logqlBinaryOps = map[string]bool{
+		"+": false, "-": false, "*": false, "/": false, "%": false, "^": false,
+		"and": false, "or": false, "unless": false,
+		"==": true, "!=": true, ">": true, ">=": true, "<": true, "<=": true,
+	}
+
+	logqlLineFilterOps  = []string{"|=", "!=", "|~", "!~", "|>", "!>"}
+	logqlMatcherOps     = []string{"=", "!=", "=~", "!~"}
+	logqlLabelFilterOps = []string{"=", "==", "!=", "=~", "!~", ">", ">=", "<", "<="}
+	logqlUnwrapFuncs    = []string{"bytes", "duration", "duration_seconds"}
+
+	// logqlOps are the operators of LogQL, longest first.
+	logqlOps = []string{
+		"|=", "|~", "|>", "!=", "!~", "!>", "=~", "==", ">=", "<=",
+		"{", "}", "(", ")", "[", "]", ",", "=", ">", "<", "+", "-", "*", "/", "%", "^", "|",
+	}
+	logqlFlags = []string{"--strict", "--keep-empty"}
+
+	logqlBytes = regexp.MustCompile(`(?i)^[0-9]+(\.[0-9]+)?([kmgtpe]i?)?b$`)
+)
+
+// ValidateLogQL checks that expr is a syntactically valid LogQL metric query,
+// which the Loki ruler requires for alerting and recording rules.
+func ValidateLogQL(expr string) error {
+	tokens, err := lexLogQL(expr)
+	if err != nil {
+		return err
+	}
+	p := &logqlParser{input: expr, tokens: tokens}
+	isLog, err := p.parseExpr()
+	if err != nil {
+		return err
+	}
+	if t := p.peek(); t.kind != logqlEOF {
+		return p.unexpected(t)
+	}
+	if isLog {
+		return errors.New("rules require a metric query, not a log query")
+	}
+	return nil
+}
+
+type logqlTokenKind int
+
+const (
+	logqlEOF logqlTokenKind = iota
+	logqlIdent
+	logqlString
+	logqlNumber
+	logqlFlag
+	logqlOp
+)
+
+type logqlToken struct {
+	kind logqlTokenKind
+	// text is the token as written, except for strings which are unquoted.
+	text string
+	pos  int
+}
+
+func lexLogQL(s string) ([]logqlToken, error) {
+	var tokens []logqlToken
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '#':
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case c == '"' || c == '`':
+			end := i + 1
+			for end < len(s) && s[end] != c {
+				if c == '"' && s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(s) {
+				return nil, logqlError(s, i, "unterminated string")
+			}
+			value, err := strconv.Unquote(s[i : end+1])
+			if err != nil {
+				return nil, logqlError(s, i, "invalid string %s", s[i:end+1])
+			}
+			tokens = append(tokens, logqlToken{kind: logqlString, text: value, pos: i})
+			i = end + 1
+		case isLogQLLetter(c):
+			end := i + 1
+			for end < len(s) && (isLogQLLetter(s[end]) || isLogQLDigit(s[end])) {
+				end++
+			}
+			tokens = append(tokens, logqlToken{kind: logqlIdent, text: s[i:end], pos: i})
+			i = end
+		case isLogQLDigit(c) || (c == '.' && i+1 < len(s) && isLogQLDigit(s[i+1])):
+			// Numbers, durations and byte sizes are told apart by the parser.
+			end := i + 1
+			for end < len(s) && (isLogQLLetter(s[end]) || isLogQLDigit(s[end]) || s[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, logqlToken{kind: logqlNumber, text: s[i:end], pos: i})
+			i = end
+		default:
+			token, ok := lexLogQLOp(s[i:])
+			if !ok {
+				return nil, logqlError(s, i, "unexpected character %q", c)
+			}
+			token.pos = i
+			tokens = append(tokens, token)
+			i += len(token.text)
+		}
+	}
+	return append(tokens, logqlToken{kind: logqlEOF, pos: len(s)}), nil
+}
+
+func lexLogQLOp(s string) (logqlToken, bool) {
+	for _, f := range logqlFlags {
+		if strings.HasPrefix(s, f) {
+			return logqlToken{kind: logqlFlag, text: f}, true
+		}
+	}
+	for _, op := range logqlOps {
+		if strings.HasPrefix(s, op) {
+			return logqlToken{kind: logqlOp, text: op}, true
+		}
+	}
+	return logqlToken{}, false
+}
+
+func isLogQLLetter(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isLogQLDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func logqlError(input string, pos int, format string, args ...interface{}) error {
+	line := 1 + strings.Count(input[:pos], "\n")
+	col := pos - strings.LastIndex(input[:pos], "\n")
+	return fmt.Errorf("parse error at line %d, col %d: %s", line, col, fmt.Sprintf(format, args...))
+}
+
+// logqlParser checks the syntax of a LogQL query by recursive descent. It
+// does not build a syntax tree, only whether expressions are log or metric
+// queries, which cannot be mixed.
+type logqlParser struct {
+	input  string
+	tokens []logqlToken
+	pos    int
+}
+
+func (p *logqlParser) peek() logqlToken {
+	return p.tokens[p.pos]
+}
+
+func (p *logqlParser) next() logqlToken {
+	t := p.tokens[p.pos]
+	if t.kind != logqlEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *logqlParser) isOp(ops ...string) bool {
+	t := p.peek()
+	return t.kind == logqlOp && contains(ops, t.text)
+}
+
+func (p *logqlParser) isIdent(names ...string) bool {
+	t := p.peek()
+	return t.kind == logqlIdent && contains(names, keyword(t))
+}
+
+func (p *logqlParser) expectOp(op string) error {
+	if !p.isOp(op) {
+		return p.unexpected(p.peek())
+	}
+	p.next()
+	return nil
+}
+
+func (p *logqlParser) expect(kind logqlTokenKind) (logqlToken, error) {
+	t := p.next()
+	if t.kind != kind {
+		return t, p.unexpected(t)
+	}
+	return t, nil
+}
+
+// keyword returns the text of t, in lower case if it is an identifier as
+// the keywords and functions of LogQL are case-insensitive.
+func keyword(t logqlToken) string {
+	if t.kind == logqlIdent {
+		return strings.ToLower(t.text)
+	}
+	return t.text
+}
+
+func (p *logqlParser) errorf(t logqlToken, format string, args ...interface{}) error {
+	return logqlError(p.input, t.pos, format, args...)
+}
+
+func (p *logqlParser) unexpected(t logqlToken) error {
+	switch t.kind {
+	case logqlEOF:
+		return p.errorf(t, "unexpected end of query")
+	case logqlString:
+		return p.errorf(t, "unexpected string %q", t.text)
+	default:
+		return p.errorf(t, "unexpected %q", t.text)
+	}
+}
+
+// parseExpr parses operands joined by binary operators and reports whether
+// the expression is a log query.
+func (p *logqlParser) parseExpr() (bool, error) {
+	isLog, err := p.parseUnary()
+	if err != nil {
+		return false, err
+	}
+	for {
+		t := p.peek()
+		comparison, ok := logqlBinaryOps[keyword(t)]
+		if !ok || (t.kind != logqlOp && t.kind != logqlIdent) {
+			return isLog, nil
+		}
+		if isLog {
+			return false, p.errorf(t, "unexpected %q after a log query", t.text)
+		}
+		p.next()
+		if comparison && p.isIdent("bool") {
+			p.next()
+		}
+		if p.isIdent("on", "ignoring") {
+			p.next()
+			if err := p.parseLabelList(); err != nil {
+				return false, err
+			}
+			if p.isIdent("group_left", "group_right") {
+				p.next()
+				if p.isOp("(") {
+					if err := p.parseLabelList(); err != nil {
+						return false, err
+					}
+				}
+			}
+		}
+		operand := p.peek()
+		rhsLog, err := p.parseUnary()
+		if err != nil {
+			return false, err
+		}
+		if rhsLog {
+			return false, p.errorf(operand, "log queries cannot be operands of %q", t.text)
+		}
+	}
+}
+
+func (p *logqlParser) parseUnary() (bool, error) {
+	if p.isOp("-", "+") {
+		t := p.next()
+		isLog, err := p.parseUnary()
+		if err == nil && isLog {
+			err = p.errorf(t, "unexpected %q before a log query", t.text)
+		}
+		return false, err
+	}
+	return p.parsePrimary()
+}
+
+func (p *logqlParser) parsePrimary() (bool, error) {
+	t := p.peek()
+	switch {
+	case t.kind == logqlNumber:
+		p.next()
+		if _, err := strconv.ParseFloat(t.text, 64); err != nil {
+			return false, p.errorf(t, "invalid number %q", t.text)
+		}
+		return false, nil
+	case p.isOp("("):
+		p.next()
+		isLog, err := p.parseExpr()
+		if err != nil {
+			return false, err
+		}
+		return isLog, p.expectOp(")")
+	case p.isOp("{"):
+		if err := p.parseSelector(); err != nil {
+			return false, err
+		}
+		_, err := p.parsePipeline()
+		return true, err
+	case t.kind == logqlIdent:
+		if mode, ok := logqlRangeOps[keyword(t)]; ok {
+			return false, p.parseRangeAggregation(mode)
+		}
+		if param, ok := logqlVectorOps[keyword(t)]; ok {
+			return false, p.parseVectorAggregation(param)
+		}
+		switch keyword(t) {
+		case "label_replace":
+			return false, p.parseLabelReplace()
+		case "vector":
+			p.next()
+			if err := p.expectOp("("); err != nil {
+				return false, err
+			}
+			if err := p.parseNumber(); err != nil {
+				return false, err
+			}
+			return false, p.expectOp(")")
+		}
+		return false, p.errorf(t, "unknown function %q", t.text)
+	}
+	return false, p.unexpected(t)
+}
+
+// parseRangeAggregation parses e.g. rate({app="foo"} |= "error" [5m]).
+func (p *logqlParser) parseRangeAggregation(mode unwrapMode) error {
+	op := p.next()
+	if err := p.expectOp("("); err != nil {
+		return err
+	}
+	if keyword(op) == "quantile_over_time" {
+		if err := p.parseNumber(); err != nil {
+			return err
+		}
+		if err := p.expectOp(","); err != nil {
+			return err
+		}
+	}
+	unwrapped, err := p.parseLogRange()
+	if err != nil {
+		return err
+	}
+	if err := p.expectOp(")"); err != nil {
+		return err
+	}
+	switch {
+	case mode == unwrapRequired && !unwrapped:
+		return p.errorf(op, "%s requires an unwrapped label", op.text)
+	case mode == unwrapForbidden && unwrapped:
+		return p.errorf(op, "%s does not support unwrapped labels", op.text)
+	}
+	if p.isIdent("by", "without") {
+		if !unwrapped {
+			return p.errorf(p.peek(), "grouping is only supported by %s with an unwrapped label", op.text)
+		}
+		return p.parseGrouping()
+	}
+	return nil
+}
+
+// parseLogRange parses a log query over a range and reports whether a label
+// was unwrapped.
+func (p *logqlParser) parseLogRange() (bool, error) {
+	if p.isOp("(") {
+		p.next()
+		unwrapped, err := p.parseLogRange()
+		if err != nil {
+			return false, err
+		}
+		return unwrapped, p.expectOp(")")
+	}
+	if err := p.parseSelector(); err != nil {
+		return false, err
+	}
+	start := p.pos
+	unwrapped, err := p.parsePipeline()
+	if err != nil {
+		return false, err
+	}
+	piped := p.pos > start
+	if err := p.expectOp("["); err != nil {
+		return false, err
+	}
+	if err := p.parseDuration(); err != nil {
+		return false, err
+	}
+	if err := p.expectOp("]"); err != nil {
+		return false, err
+	}
+	// The pipeline may also follow the range, but not both.
+	if !piped {
+		if unwrapped, err = p.parsePipeline(); err != nil {
+			return false, err
+		}
+	}
+	if p.isIdent("offset") {
+		p.next()
+		// Negative offsets query ahead of the evaluation time.
+		if p.isOp("-") {
+			p.next()
+		}
+		if err := p.parseDuration(); err != nil {
+			return false, err
+		}
+	}
+	return unwrapped, nil
+}
+
+// parseVectorAggregation parses e.g. sum by (app) (expr) or topk(5, expr).
+func (p *logqlParser) parseVectorAggregation(param bool) error {
+	op := p.next()
+	grouped := false
+	if p.isIdent("by", "without") {
+		if err := p.parseGrouping(); err != nil {
+			return err
+		}
+		grouped = true
+	}
+	if err := p.expectOp("("); err != nil {
+		return err
+	}
+	if param {
+		if err := p.parseNumber(); err != nil {
+			return err
+		}
+		if err := p.expectOp(","); err != nil {
+			return err
+		}
+	}
+	operand := p.peek()
+	isLog, err := p.parseExpr()
+	if err != nil {
+		return err
+	}
+	if isLog {
+		return p.errorf(operand, "%s requires a metric query, not a log query", op.text)
+	}
+	if err := p.expectOp(")"); err != nil {
+		return err
+	}
+	if !grouped && p.isIdent("by", "without") {
+		return p.parseGrouping()
+	}
+	return nil
+}
+
+// parseLabelReplace parses label_replace(expr, dst, replacement, src, regex).
+func (p *logqlParser) parseLabelReplace() error {
+	p.next()
+	if err := p.expectOp("("); err != nil {
+		return err
+	}
+	operand := p.peek()
+	isLog, err := p.parseExpr()
+	if err != nil {
+		return err
+	}
+	if isLog {
+		return p.errorf(operand, "label_replace requires a metric query, not a log query")
+	}
+	var regex logqlToken
+	for i := 0; i < 4; i++ {
+		if err := p.expectOp(","); err != nil {
+			return err
+		}
+		if regex, err = p.expect(logqlString); err != nil {
+			return err
+		}
+	}
+	if _, err := regexp.Compile("^(?:" + regex.text + ")$"); err != nil {
+		return p.errorf(regex, "invalid regular expression %q: %v", regex.text, err)
+	}
+	return p.expectOp(")")
+}
+
+func (p *logqlParser) parseGrouping() error {
+	p.next()
+	return p.parseLabelList()
+}
+
+func (p *logqlParser) parseLabelList() error {
+	if err := p.expectOp("("); err != nil {
+		return err
+	}
+	for !p.isOp(")") {
+		if _, err := p.expect(logqlIdent); err != nil {
+			return err
+		}
+		if !p.isOp(",") {
+			break
+		}
+		p.next()
+	}
+	return p.expectOp(")")
+}
+
+// parseSelector parses a stream selector such as {app="foo", env=~"prod|dev"}.
+func (p *logqlParser) parseSelector() error {
+	start := p.peek()
+	if err := p.expectOp("{"); err != nil {
+		return err
+	}
+	selective := false
+	for {
+		if _, err := p.expect(logqlIdent); err != nil {
+			return err
+		}
+		op := p.next()
+		if op.kind != logqlOp || !contains(logqlMatcherOps, op.text) {
+			return p.unexpected(op)
+		}
+		value, err := p.expect(logqlString)
+		if err != nil {
+			return err
+		}
+		switch op.text {
+		case "=":
+			selective = selective || value.text != ""
+		case "=~", "!~":
+			re, err := regexp.Compile("^(?:" + value.text + ")$")
+			if err != nil {
+				return p.errorf(value, "invalid regular expression %q: %v", value.text, err)
+			}
+			selective = selective || (op.text == "=~" && !re.MatchString(""))
+		}
+		if !p.isOp(",") {
+			break
+		}
+		p.next()
+	}
+	if err := p.expectOp("}"); err != nil {
+		return err
+	}
+	if !selective {
+		return p.errorf(start, "queries require at least one regexp or equality matcher that does not have an empty-compatible value")
+	}
+	return nil
+}
+
+// parsePipeline parses the line filters and stages following a stream
+// selector and reports whether a label was unwrapped.
+func (p *logqlParser) parsePipeline() (bool, error) {
+	unwrapped := false
+	for {
+		switch {
+		case p.isOp(logqlLineFilterOps...):
+			if err := p.parseLineFilter(); err != nil {
+				return false, err
+			}
+		case p.isOp("|"):
+			p.next()
+			isUnwrap := p.isIdent("unwrap")
+			if err := p.parseStage(); err != nil {
+				return false, err
+			}
+			unwrapped = unwrapped || isUnwrap
+		default:
+			return unwrapped, nil
+		}
+	}
+}
+
+// parseLineFilter parses e.g. |= "foo" or "bar", != ip("10.0.0.0/8") or |~ "fo+".
+func (p *logqlParser) parseLineFilter() error {
+	op := p.next()
+	for {
+		if (op.text == "|=" || op.text == "!=") && p.isIdent("ip") {
+			if err := p.parseIP(); err != nil {
+				return err
+			}
+		} else {
+			value, err := p.expect(logqlString)
+			if err != nil {
+				return err
+			}
+			switch op.text {
+			case "|~", "!~":
+				if _, err := regexp.Compile(value.text); err != nil {
+					return p.errorf(value, "invalid regular expression %q: %v", value.text, err)
+				}
+			case "|>", "!>":
+				if value.text == "" {
+					return p.errorf(value, "pattern must not be empty")
+				}
+			}
+		}
+		if !p.isIdent("or") {
+			return nil
+		}
+		p.next()
+	}
+}
+
+// parseStage parses the stage of a pipeline following a |.
+func (p *logqlParser) parseStage() error {
+	t := p.peek()
+	if t.kind != logqlIdent {
+		return p.unexpected(t)
+	}
+	switch keyword(t) {
+	case "json", "logfmt":
+		p.next()
+		for p.peek().kind == logqlFlag {
+			if keyword(t) != "logfmt" {
+				return p.unexpected(p.peek())
+			}
+			p.next()
+		}
+		if p.peek().kind != logqlIdent || p.isIdent("or", "and", "unless", "offset", "bool") {
+			return nil
+		}
+		return p.parseExtractions()
+	case "unpack", "decolorize":
+		p.next()
+		return nil
+	case "regexp":
+		p.next()
+		value, err := p.expect(logqlString)
+		if err != nil {
+			return err
+		}
+		re, err := regexp.Compile(value.text)
+		if err != nil {
+			return p.errorf(value, "invalid regular expression %q: %v", value.text, err)
+		}
+		if !hasNamedGroup(re) {
+			return p.errorf(value, "regexp %q has no named capture group", value.text)
+		}
+		return nil
+	case "pattern", "line_format":
+		p.next()
+		value, err := p.expect(logqlString)
+		if err == nil && keyword(t) == "pattern" && value.text == "" {
+			err = p.errorf(value, "pattern must not be empty")
+		}
+		return err
+	case "label_format":
+		p.next()
+		for {
+			if _, err := p.expect(logqlIdent); err != nil {
+				return err
+			}
+			if err := p.expectOp("="); err != nil {
+				return err
+			}
+			if value := p.next(); value.kind != logqlString && value.kind != logqlIdent {
+				return p.unexpected(value)
+			}
+			if !p.isOp(",") {
+				return nil
+			}
+			p.next()
+		}
+	case "drop", "keep":
+		p.next()
+		for {
+			if _, err := p.expect(logqlIdent); err != nil {
+				return err
+			}
+			if p.isOp(logqlMatcherOps...) {
+				p.next()
+				if _, err := p.expect(logqlString); err != nil {
+					return err
+				}
+			}
+			if !p.isOp(",") {
+				return nil
+			}
+			p.next()
+		}
+	case "unwrap":
+		p.next()
+		if p.isIdent(logqlUnwrapFuncs...) && p.tokens[p.pos+1].kind == logqlOp && p.tokens[p.pos+1].text == "(" {
+			p.next()
+			p.next()
+			if _, err := p.expect(logqlIdent); err != nil {
+				return err
+			}
+			return p.expectOp(")")
+		}
+		_, err := p.expect(logqlIdent)
+		return err
+	}
+	return p.parseLabelFilters()
+}
+
+// parseExtractions parses the labels extracted by json or logfmt, e.g.
+// | json status, ua="request.headers.user_agent".
+func (p *logqlParser) parseExtractions() error {
+	for {
+		if _, err := p.expect(logqlIdent); err != nil {
+			return err
+		}
+		if p.isOp("=") {
+			p.next()
+			if _, err := p.expect(logqlString); err != nil {
+				return err
+			}
+		}
+		if !p.isOp(",") {
+			return nil
+		}
+		p.next()
+	}
+}
+
+// parseLabelFilters parses label filters joined by and, or and commas,
+// e.g. | status >= 500 or (level="error" and duration > 1s).
+func (p *logqlParser) parseLabelFilters() error {
+	for {
+		if p.isOp("(") {
+			p.next()
+			if err := p.parseLabelFilters(); err != nil {
+				return err
+			}
+			if err := p.expectOp(")"); err != nil {
+				return err
+			}
+		} else if err := p.parseLabelFilter(); err != nil {
+			return err
+		}
+		switch {
+		case p.isIdent("and", "or") || p.isOp(","):
+			p.next()
+		case p.peek().kind == logqlIdent && !p.isIdent("offset", "unless", "bool"):
+			// Consecutive filters without an operator are joined by and.
+		default:
+			return nil
+		}
+	}
+}
+
+func (p *logqlParser) parseLabelFilter() error {
+	if _, err := p.expect(logqlIdent); err != nil {
+		return err
+	}
+	op := p.next()
+	if op.kind != logqlOp || !contains(logqlLabelFilterOps, op.text) {
+		return p.unexpected(op)
+	}
+	value := p.peek()
+	switch {
+	case value.kind == logqlString:
+		p.next()
+		if op.text == "=~" || op.text == "!~" {
+			if _, err := regexp.Compile("^(?:" + value.text + ")$"); err != nil {
+				return p.errorf(value, "invalid regular expression %q: %v", value.text, err)
+			}
+		}
+		return nil
+	case value.kind == logqlIdent && keyword(value) == "ip" && (op.text == "=" || op.text == "!="):
+		return p.parseIP()
+	case value.kind == logqlNumber && op.text != "=~" && op.text != "!~":
+		p.next()
+		if !isLogQLNumber(value.text) && !isLogQLDuration(value.text) && !logqlBytes.MatchString(value.text) {
+			return p.errorf(value, "invalid number, duration or byte size %q", value.text)
+		}
+		return nil
+	}
+	return p.unexpected(value)
+}
+
+// parseIP parses ip("..."), which matches an address, CIDR or address range.
+func (p *logqlParser) parseIP() error {
+	p.next()
+	if err := p.expectOp("("); err != nil {
+		return err
+	}
+	value, err := p.expect(logqlString)
+	if err != nil {
+		return err
+	}
+	if !isIPMatch(value.text) {
+		return p.errorf(value, "invalid IP address, CIDR or range %q", value.text)
+	}
+	return p.expectOp(")")
+}
+
+func (p *logqlParser) parseNumber() error {
+	t := p.next()
+	if t.kind != logqlNumber {
+		return p.unexpected(t)
+	}
+	if !isLogQLNumber(t.text) {
+		return p.errorf(t, "invalid number %q", t.text)
+	}
+	return nil
+}
+
+func (p *logqlParser) parseDuration() error {
+	t := p.next()
+	if t.kind != logqlNumber {
+		return p.unexpected(t)
+	}
+	if !isLogQLDuration(t.text) {
+		return p.errorf(t, "invalid duration %q", t.text)
+	}
+	return nil
+}
+
+func isLogQLNumber(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
+
+func isLogQLDuration(s string) bool {
+	if _, err := time.ParseDuration(s); err == nil {
+		return true
+	}
+	_, err := model.ParseDuration(s)
+	return err == nil
+}
+
+func isIPMatch(s string) bool {
+	if _, err := netip.ParseAddr(s); err == nil {
+		return true
+	}
+	if _, err := netip.ParsePrefix(s); err == nil {
+		return true
+	}
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return false
+	}
+	a, err := netip.ParseAddr(strings.TrimSpace(from))
+	if err != nil {
+		return false
+	}
+	b, err := netip.ParseAddr(strings.TrimSpace(to))
+	return err == nil && a.BitLen() == b.BitLen() && !b.Less(a)
+}
+
+func hasNamedGroup(re *regexp.Regexp) bool {
+	for _, name := range re.SubexpNames() {
+		if name != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package rules_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/traceshield/trace-shield-controller/internal/rules"
+)
+
+var _ = Describe("ValidateLogQL", func() {
+	DescribeTable("accepts metric queries",
+		func(expr string) {
+			Expect(rules.ValidateLogQL(expr)).To(Succeed())
+		},
+		Entry("rate", `rate({app="foo"}[5m])`),
+		Entry("line filters", `count_over_time({app="foo", env=~"prod|dev"} |= "error" != "timeout" |~ "fo+" or "ba+" [1h])`),
+		Entry("range before pipeline", `count_over_time({app="foo"}[5m] |= "error")`),
+		Entry("parsers and label filters", `sum by (status) (count_over_time({app="foo"} | json | status >= 500 and duration > 1s or level="error" [5m]))`),
+		Entry("logfmt flags and extractions", `rate({app="foo"} | logfmt --strict msg, user="user.name" | __error__="" [5m])`),
+		Entry("formatting stages", `rate({app="foo"} | pattern "<ip> - <_>" | line_format "{{.ip}}" | label_format ip=ip, src="{{.ip}}" | drop level, env="dev" [5m])`),
+		Entry("regexp", "rate({app=\"foo\"} | regexp `(?P<status>\\d{3})` [5m])"),
+		Entry("ip filters", `rate({app="foo"} |= ip("10.0.0.0/8") | addr = ip("192.168.0.1-192.168.0.10") [5m])`),
+		Entry("unwrap", `quantile_over_time(0.99, {app="foo"} | logfmt | unwrap duration(latency) | __error__="" [5m]) by (path)`),
+		Entry("offset", `sum_over_time({app="foo"} | unwrap bytes [5m] offset 1h)`),
+		Entry("vector aggregations", `topk(5, sum(rate({app="foo"}[5m])) by (host))`),
+		Entry("binary operations", `sum(rate({app="foo"} |= "error" [5m])) / on (app) group_left sum(rate({app="foo"}[5m])) > bool 0.1`),
+		Entry("set operations", `absent_over_time({app="foo"}[10m]) or vector(0)`),
+		Entry("label_replace", `label_replace(rate({app="foo"}[5m]), "dst", "$1", "app", "(.*)")`),
+		Entry("comments", "# errors\nrate({app=\"foo\"}[5m])"),
+		Entry("uppercase grouping", `SUM BY (app) (rate({app="foo"}[5m])) / SUM WITHOUT (env) (rate({app="foo"}[5m]))`),
+		Entry("uppercase functions and operators", `Count_Over_Time({app="foo"} | JSON | status >= 500 AND level="error" [5m]) > BOOL 0 OR Vector(0)`),
+		Entry("uppercase offset", `rate({app="foo"}[5m] OFFSET 1h)`),
+		Entry("negative offset", `sum_over_time({app="foo"} | unwrap bytes [5m] offset -1h)`),
+		Entry("negative offset before pipeline", `count_over_time({app="foo"}[5m] |= "error" offset -30m)`),
+	)
+
+	DescribeTable("rejects invalid queries",
+		func(expr, message string) {
+			Expect(rules.ValidateLogQL(expr)).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("log query", `{app="foo"} |= "error"`, "rules require a metric query"),
+		Entry("missing range", `rate({app="foo"})`, `unexpected ")"`),
+		Entry("empty selector", `rate({app=""}[5m])`, "at least one regexp or equality matcher"),
+		Entry("invalid regular expression", `rate({app="foo"} |~ "(" [5m])`, "invalid regular expression"),
+		Entry("invalid duration", `rate({app="foo"}[5x])`, `invalid duration "5x"`),
+		Entry("missing unwrap", `sum_over_time({app="foo"}[5m])`, "sum_over_time requires an unwrapped label"),
+		Entry("forbidden unwrap", `count_over_time({app="foo"} | unwrap bytes [5m])`, "does not support unwrapped labels"),
+		Entry("unknown function", `increase({app="foo"}[5m])`, `unknown function "increase"`),
+		Entry("unterminated string", `rate({app="foo}[5m])`, "unterminated string"),
+		Entry("log query operand", `sum({app="foo"})`, "sum requires a metric query"),
+		Entry("offset without duration", `rate({app="foo"}[5m] offset -)`, "unexpected"),
+		Entry("uppercase unknown function", `INCREASE({app="foo"}[5m])`, `unknown function "INCREASE"`),
+		Entry("trailing tokens", `rate({app="foo"}[5m]) )`, "line 1, col 23"),
+	)
+})
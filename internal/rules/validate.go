@@ -0,0 +1,123 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rules validates the rule groups of tenants before they are synced to a ruler.
+package rules
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+)
+
+// Validate checks the names and labels of the rules in groups and, for the
+// Loki ruler, the syntax of their LogQL queries.
+func Validate(groups []observabilityv1alpha1.RuleGroup, ruler observabilityv1alpha1.RulerType) field.ErrorList {
+	var errs field.ErrorList
+	for i, g := range groups {
+		path := field.NewPath("spec", "groups").Index(i)
+		for j, r := range g.Rules {
+			rulePath := path.Child("rules").Index(j)
+			if r.Record != "" && !model.IsValidMetricName(model.LabelValue(r.Record)) {
+				errs = append(errs, field.Invalid(rulePath.Child("record"), r.Record, "must be a valid metric name"))
+			}
+			if ruler == observabilityv1alpha1.RulerTypeLoki {
+				if err := ValidateLogQL(r.Expr); err != nil {
+					errs = append(errs, field.Invalid(rulePath.Child("expr"), r.Expr, err.Error()))
+				}
+			}
+			for _, k := range sortedKeys(r.Labels) {
+				if !model.LabelName(k).IsValid() || k == model.MetricNameLabel {
+					errs = append(errs, field.Invalid(rulePath.Child("labels"), k, "must be a valid label name"))
+				}
+			}
+			for _, k := range sortedKeys(r.Annotations) {
+				if !model.LabelName(k).IsValid() {
+					errs = append(errs, field.Invalid(rulePath.Child("annotations"), k, "must be a valid label name"))
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// ExceedsRuleLimit returns an error if a group has more rules than
+// maxRulesPerGroup, if set.
+func ExceedsRuleLimit(groups []observabilityv1alpha1.RuleGroup, maxRulesPerGroup *int) error {
+	if maxRulesPerGroup == nil || *maxRulesPerGroup <= 0 {
+		return nil
+	}
+	for _, g := range groups {
+		if len(g.Rules) > *maxRulesPerGroup {
+			return fmt.Errorf("rule group %s has %d rules, the limit is %d", g.Name, len(g.Rules), *maxRulesPerGroup)
+		}
+	}
+	return nil
+}
+
+// ExceedsGroupLimit returns an error if the rule groups of the
+// TenantRuleGroup at index i of groups, which all belong to the same tenant,
+// do not fit within maxGroups, if set. The TenantRuleGroups are counted in
+// order of creation, so that new TenantRuleGroups cannot push out the
+// groups of existing ones.
+func ExceedsGroupLimit(groups []observabilityv1alpha1.TenantRuleGroup, i int, maxGroups *int) error {
+	if maxGroups == nil || *maxGroups <= 0 {
+		return nil
+	}
+	target := &groups[i]
+
+	ordered := make([]*observabilityv1alpha1.TenantRuleGroup, 0, len(groups))
+	for j := range groups {
+		if groups[j].DeletionTimestamp.IsZero() {
+			ordered = append(ordered, &groups[j])
+		}
+	}
+	sort.SliceStable(ordered, func(a, b int) bool {
+		ta, tb := ordered[a].CreationTimestamp, ordered[b].CreationTimestamp
+		if !ta.Equal(&tb) {
+			return ta.Before(&tb)
+		}
+		if ordered[a].Namespace != ordered[b].Namespace {
+			return ordered[a].Namespace < ordered[b].Namespace
+		}
+		return ordered[a].Name < ordered[b].Name
+	})
+
+	count := 0
+	for _, g := range ordered {
+		count += len(g.Spec.Groups)
+		if g == target {
+			break
+		}
+	}
+	if count > *maxGroups {
+		return fmt.Errorf("the tenant would have %d rule groups, the limit is %d", count, *maxGroups)
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
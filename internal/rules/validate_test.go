@@ -0,0 +1,102 @@
+package rules_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/internal/rules"
+)
+
+var _ = Describe("Validate", func() {
+	It("accepts valid rule groups", func() {
+		groups := []observabilityv1alpha1.RuleGroup{{
+			Name: "example",
+			Rules: []observabilityv1alpha1.Rule{
+				{Record: "job:up:sum", Expr: "sum by (job) (up)", Labels: map[string]string{"team": "a"}},
+				{Alert: "Down", Expr: "up == 0", Annotations: map[string]string{"summary": "down"}},
+			},
+		}}
+		Expect(rules.Validate(groups, observabilityv1alpha1.RulerTypeMimir)).To(BeEmpty())
+	})
+
+	It("rejects invalid names", func() {
+		groups := []observabilityv1alpha1.RuleGroup{{
+			Name: "example",
+			Rules: []observabilityv1alpha1.Rule{
+				{Record: "job-up", Expr: "up"},
+				{Alert: "Down", Expr: "up == 0", Labels: map[string]string{"__name__": "x", "ok": "y"}},
+			},
+		}}
+		errs := rules.Validate(groups, observabilityv1alpha1.RulerTypeMimir)
+		Expect(errs).To(HaveLen(2))
+		Expect(errs.ToAggregate().Error()).To(And(
+			ContainSubstring("spec.groups[0].rules[0].record"),
+			ContainSubstring("spec.groups[0].rules[1].labels"),
+		))
+	})
+
+	It("checks the LogQL syntax of rules for the Loki ruler", func() {
+		groups := []observabilityv1alpha1.RuleGroup{{
+			Name: "logs",
+			Rules: []observabilityv1alpha1.Rule{
+				{Alert: "Errors", Expr: `sum(rate({app="foo"} |= "error" [5m])) > 1`},
+				{Alert: "Broken", Expr: `rate({app="foo"} |= "error")`},
+			},
+		}}
+		errs := rules.Validate(groups, observabilityv1alpha1.RulerTypeLoki)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.groups[0].rules[1].expr"))
+	})
+})
+
+var _ = Describe("ExceedsRuleLimit", func() {
+	limit := func(i int) *int { return &i }
+
+	It("limits the rules per group", func() {
+		groups := []observabilityv1alpha1.RuleGroup{{
+			Name:  "example",
+			Rules: []observabilityv1alpha1.Rule{{Alert: "A", Expr: "up"}, {Alert: "B", Expr: "up"}},
+		}}
+		Expect(rules.ExceedsRuleLimit(groups, limit(2))).To(Succeed())
+		Expect(rules.ExceedsRuleLimit(groups, nil)).To(Succeed())
+		Expect(rules.ExceedsRuleLimit(groups, limit(1))).To(MatchError("rule group example has 2 rules, the limit is 1"))
+	})
+})
+
+var _ = Describe("ExceedsGroupLimit", func() {
+	limit := func(i int) *int { return &i }
+	now := time.Now()
+	ruleGroup := func(name string, created time.Time, groups int) observabilityv1alpha1.TenantRuleGroup {
+		g := observabilityv1alpha1.TenantRuleGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", CreationTimestamp: metav1.NewTime(created)},
+			Spec:       observabilityv1alpha1.TenantRuleGroupSpec{Tenant: "team-a"},
+		}
+		for i := 0; i < groups; i++ {
+			g.Spec.Groups = append(g.Spec.Groups, observabilityv1alpha1.RuleGroup{})
+		}
+		return g
+	}
+
+	It("counts rule groups in order of creation", func() {
+		groups := []observabilityv1alpha1.TenantRuleGroup{
+			ruleGroup("new", now, 2),
+			ruleGroup("old", now.Add(-time.Hour), 2),
+		}
+		Expect(rules.ExceedsGroupLimit(groups, 1, limit(3))).To(Succeed())
+		Expect(rules.ExceedsGroupLimit(groups, 0, limit(3))).To(MatchError("the tenant would have 4 rule groups, the limit is 3"))
+		Expect(rules.ExceedsGroupLimit(groups, 0, limit(4))).To(Succeed())
+		Expect(rules.ExceedsGroupLimit(groups, 0, nil)).To(Succeed())
+	})
+
+	It("ignores TenantRuleGroups being deleted", func() {
+		deleted := ruleGroup("deleted", now.Add(-time.Hour), 2)
+		deletedAt := metav1.NewTime(now)
+		deleted.DeletionTimestamp = &deletedAt
+		groups := []observabilityv1alpha1.TenantRuleGroup{deleted, ruleGroup("new", now, 2)}
+		Expect(rules.ExceedsGroupLimit(groups, 1, limit(3))).To(Succeed())
+	})
+})
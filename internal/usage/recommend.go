@@ -0,0 +1,70 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"math"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+)
+
+// Recommend returns the limits recommended for the usage, which is the peak
+// usage with headroom percent added and rounded up. current are the limits
+// currently rendered for the tenant. Limits without usage data are omitted.
+func Recommend(usage *Usage, current *observabilityv1alpha1.LimitSpec, headroom int32) []observabilityv1alpha1.LimitRecommendation {
+	var mimir observabilityv1alpha1.MimirLimits
+	var loki observabilityv1alpha1.LokiLimits
+	if current != nil && current.Mimir != nil {
+		mimir = *current.Mimir
+	}
+	if current != nil && current.Loki != nil {
+		loki = *current.Loki
+	}
+
+	var out []observabilityv1alpha1.LimitRecommendation
+	add := func(limit string, usage, current *float64) {
+		if usage == nil {
+			return
+		}
+		out = append(out, observabilityv1alpha1.LimitRecommendation{
+			Limit:       limit,
+			Usage:       *usage,
+			Current:     current,
+			Recommended: math.Ceil(*usage * (1 + float64(headroom)/100)),
+		})
+	}
+	add("mimir.max_global_series_per_user", usage.ActiveSeries, intToFloat(mimir.MaxGlobalSeriesPerUser))
+	add("mimir.ingestion_rate", usage.IngestionRate, copyFloat(mimir.IngestionRate))
+	add("loki.ingestion_rate_mb", usage.IngestionRateMB, copyFloat(loki.IngestionRateMB))
+	return out
+}
+
+func intToFloat(i *int) *float64 {
+	if i == nil {
+		return nil
+	}
+	f := float64(*i)
+	return &f
+}
+
+func copyFloat(f *float64) *float64 {
+	if f == nil {
+		return nil
+	}
+	c := *f
+	return &c
+}
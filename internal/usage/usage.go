@@ -0,0 +1,218 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package usage queries the usage of tenants from a Prometheus compatible API.
+package usage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"text/template"
+	"time"
+
+	"github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+)
+
+const (
+	// DefaultHeadroom is the percentage added to the peak usage when none is configured.
+	DefaultHeadroom = 20
+	// DefaultWindow is the period the peak usage is taken over when none is configured.
+	DefaultWindow = 24 * time.Hour
+	// DefaultInterval is how often usage is queried when no interval is configured.
+	DefaultInterval = time.Hour
+)
+
+// queryTimeout bounds how long a single usage query may take.
+const queryTimeout = 10 * time.Second
+
+// DefaultQueries are the queries used to measure usage when they are not overridden.
+var DefaultQueries = observabilityv1alpha1.UsageQueries{
+	ActiveSeries:     `max_over_time((sum(cortex_ingester_active_series{user="{{.TenantID}}"}) / on() group_left max(cortex_distributor_replication_factor))[{{.Window}}:5m])`,
+	IngestionRate:    `max_over_time(sum(rate(cortex_distributor_received_samples_total{user="{{.TenantID}}"}[5m]))[{{.Window}}:5m])`,
+	IngestionRateMB:  `max_over_time(sum(rate(loki_distributor_bytes_received_total{tenant="{{.TenantID}}"}[5m]))[{{.Window}}:5m]) / 1024 / 1024`,
+	DiscardedSamples: `sum(increase(cortex_discarded_samples_total{user="{{.TenantID}}"}[{{.Window}}]))`,
+}
+
+// Usage is the peak usage of a tenant over the usage window. Fields are nil
+// when the query API has no data for them.
+type Usage struct {
+	// ActiveSeries is the peak number of active series in Mimir.
+	ActiveSeries *float64
+	// IngestionRate is the peak rate of samples per second sent to Mimir.
+	IngestionRate *float64
+	// IngestionRateMB is the peak rate of MB per second sent to Loki.
+	IngestionRateMB *float64
+	// DiscardedSamples is the number of samples Mimir discarded.
+	DiscardedSamples *float64
+}
+
+// Client queries the usage of tenants.
+type Client struct {
+	api     promv1.API
+	window  time.Duration
+	queries observabilityv1alpha1.UsageQueries
+}
+
+// NewClient returns a Client for the query API configured in spec.
+func NewClient(spec *observabilityv1alpha1.UsageSpec) (*Client, error) {
+	cfg := api.Config{Address: spec.PrometheusURL}
+	if spec.OrgID != "" {
+		cfg.RoundTripper = &orgIDRoundTripper{orgID: spec.OrgID, next: api.DefaultRoundTripper}
+	}
+	apiClient, err := api.NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := DefaultQueries
+	if spec.Queries != nil {
+		override(&queries.ActiveSeries, spec.Queries.ActiveSeries)
+		override(&queries.IngestionRate, spec.Queries.IngestionRate)
+		override(&queries.IngestionRateMB, spec.Queries.IngestionRateMB)
+		override(&queries.DiscardedSamples, spec.Queries.DiscardedSamples)
+	}
+	for name, q := range map[string]string{
+		"activeSeries":     queries.ActiveSeries,
+		"ingestionRate":    queries.IngestionRate,
+		"ingestionRateMB":  queries.IngestionRateMB,
+		"discardedSamples": queries.DiscardedSamples,
+	} {
+		if _, err := template.New(name).Parse(q); err != nil {
+			return nil, fmt.Errorf("invalid %s query: %w", name, err)
+		}
+	}
+
+	return &Client{
+		api:     promv1.NewAPI(apiClient),
+		window:  Window(spec),
+		queries: queries,
+	}, nil
+}
+
+// TenantUsage returns the peak usage of the tenant over the usage window ending at now.
+func (c *Client) TenantUsage(ctx context.Context, tenantID string, now time.Time) (*Usage, error) {
+	usage := &Usage{}
+	for _, q := range []struct {
+		query string
+		out   **float64
+	}{
+		{c.queries.ActiveSeries, &usage.ActiveSeries},
+		{c.queries.IngestionRate, &usage.IngestionRate},
+		{c.queries.IngestionRateMB, &usage.IngestionRateMB},
+		{c.queries.DiscardedSamples, &usage.DiscardedSamples},
+	} {
+		value, err := c.query(ctx, q.query, tenantID, now)
+		if err != nil {
+			return nil, err
+		}
+		*q.out = value
+	}
+	return usage, nil
+}
+
+// query runs the query templated for the tenant and returns the sum of its
+// result, or nil if it is empty.
+func (c *Client) query(ctx context.Context, query, tenantID string, now time.Time) (*float64, error) {
+	tmpl, err := template.New("query").Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, struct {
+		TenantID string
+		Window   string
+	}{tenantID, model.Duration(c.window).String()}); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	value, _, err := c.api.Query(ctx, buf.String(), now)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query %q: %w", buf.String(), err)
+	}
+
+	var sum float64
+	var found bool
+	switch v := value.(type) {
+	case model.Vector:
+		for _, s := range v {
+			if !math.IsNaN(float64(s.Value)) {
+				sum += float64(s.Value)
+				found = true
+			}
+		}
+	case *model.Scalar:
+		if !math.IsNaN(float64(v.Value)) {
+			sum, found = float64(v.Value), true
+		}
+	default:
+		return nil, fmt.Errorf("unexpected result type %s of query %q", value.Type(), buf.String())
+	}
+	if !found {
+		return nil, nil
+	}
+	return &sum, nil
+}
+
+// Headroom returns the configured headroom percentage.
+func Headroom(spec *observabilityv1alpha1.UsageSpec) int32 {
+	if spec.Headroom != nil {
+		return *spec.Headroom
+	}
+	return DefaultHeadroom
+}
+
+// Window returns the configured usage window.
+func Window(spec *observabilityv1alpha1.UsageSpec) time.Duration {
+	if spec.Window != nil && spec.Window.Duration > 0 {
+		return spec.Window.Duration
+	}
+	return DefaultWindow
+}
+
+// Interval returns how often usage is queried.
+func Interval(spec *observabilityv1alpha1.UsageSpec) time.Duration {
+	if spec.Interval != nil && spec.Interval.Duration > 0 {
+		return spec.Interval.Duration
+	}
+	return DefaultInterval
+}
+
+func override(query *string, with string) {
+	if with != "" {
+		*query = with
+	}
+}
+
+// orgIDRoundTripper sets the tenant of multi-tenant query APIs.
+type orgIDRoundTripper struct {
+	orgID string
+	next  http.RoundTripper
+}
+
+func (rt *orgIDRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("X-Scope-OrgID", rt.orgID)
+	return rt.next.RoundTrip(req)
+}
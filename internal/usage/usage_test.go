@@ -0,0 +1,142 @@
+package usage_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/internal/usage"
+)
+
+// stubPrometheus serves the query API, answering queries containing a key of
+// results with a vector holding its value and any other query with an empty vector.
+type stubPrometheus struct {
+	results map[string]string
+	queries []string
+	orgIDs  []string
+	fail    bool
+}
+
+func (s *stubPrometheus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.URL.Path != "/api/v1/query" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	query := r.Form.Get("query")
+	s.queries = append(s.queries, query)
+	s.orgIDs = append(s.orgIDs, r.Header.Get("X-Scope-OrgID"))
+
+	w.Header().Set("Content-Type", "application/json")
+	if s.fail {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprint(w, `{"status":"error","errorType":"execution","error":"query timed out"}`)
+		return
+	}
+	result := ""
+	for metric, value := range s.results {
+		if strings.Contains(query, metric) {
+			result = fmt.Sprintf(`{"metric":{},"value":[%d,%q]}`, time.Now().Unix(), value)
+		}
+	}
+	fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":[%s]}}`, result)
+}
+
+var _ = Describe("Client", func() {
+	var stub *stubPrometheus
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		stub = &stubPrometheus{results: map[string]string{
+			"cortex_ingester_active_series":         "15000",
+			"cortex_distributor_received_samples":   "2500.5",
+			"loki_distributor_bytes_received_total": "3.2",
+			"cortex_discarded_samples_total":        "42",
+		}}
+		server = httptest.NewServer(stub)
+		DeferCleanup(server.Close)
+	})
+
+	It("queries the usage of the tenant", func() {
+		client, err := usage.NewClient(&observabilityv1alpha1.UsageSpec{PrometheusURL: server.URL, OrgID: "meta"})
+		Expect(err).NotTo(HaveOccurred())
+
+		u, err := client.TenantUsage(context.Background(), "team-a", time.Now())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(u.ActiveSeries).To(HaveValue(Equal(15000.0)))
+		Expect(u.IngestionRate).To(HaveValue(Equal(2500.5)))
+		Expect(u.IngestionRateMB).To(HaveValue(Equal(3.2)))
+		Expect(u.DiscardedSamples).To(HaveValue(Equal(42.0)))
+
+		Expect(stub.queries).To(HaveLen(4))
+		for _, q := range stub.queries {
+			Expect(q).To(ContainSubstring(`"team-a"`))
+		}
+		Expect(stub.queries[0]).To(ContainSubstring("[1d:5m]"))
+		Expect(stub.orgIDs).To(HaveEach("meta"))
+	})
+
+	It("uses overridden queries and window", func() {
+		client, err := usage.NewClient(&observabilityv1alpha1.UsageSpec{
+			PrometheusURL: server.URL,
+			Window:        &metav1.Duration{Duration: 7 * 24 * time.Hour},
+			Queries: &observabilityv1alpha1.UsageQueries{
+				ActiveSeries: `max_over_time(custom_series{org="{{.TenantID}}"}[{{.Window}}])`,
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		u, err := client.TenantUsage(context.Background(), "team-a", time.Now())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stub.queries[0]).To(Equal(`max_over_time(custom_series{org="team-a"}[1w])`))
+		Expect(u.ActiveSeries).To(BeNil())
+		Expect(u.IngestionRate).NotTo(BeNil())
+		Expect(stub.orgIDs).To(HaveEach(""))
+	})
+
+	It("rejects invalid query templates", func() {
+		_, err := usage.NewClient(&observabilityv1alpha1.UsageSpec{
+			PrometheusURL: server.URL,
+			Queries:       &observabilityv1alpha1.UsageQueries{IngestionRate: "rate({{.TenantID}"},
+		})
+		Expect(err).To(MatchError(ContainSubstring("invalid ingestionRate query")))
+	})
+
+	It("returns query errors", func() {
+		stub.fail = true
+		client, err := usage.NewClient(&observabilityv1alpha1.UsageSpec{PrometheusURL: server.URL})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = client.TenantUsage(context.Background(), "team-a", time.Now())
+		Expect(err).To(MatchError(ContainSubstring("query timed out")))
+	})
+})
+
+var _ = Describe("Recommend", func() {
+	value := func(f float64) *float64 { return &f }
+	count := func(i int) *int { return &i }
+
+	It("adds headroom to the peak usage", func() {
+		current := &observabilityv1alpha1.LimitSpec{
+			Mimir: &observabilityv1alpha1.MimirLimits{MaxGlobalSeriesPerUser: count(10000)},
+		}
+		u := &usage.Usage{ActiveSeries: value(15000), IngestionRateMB: value(3.2)}
+
+		recommendations := usage.Recommend(u, current, 20)
+		Expect(recommendations).To(Equal([]observabilityv1alpha1.LimitRecommendation{
+			{Limit: "mimir.max_global_series_per_user", Usage: 15000, Current: value(10000), Recommended: 18000},
+			{Limit: "loki.ingestion_rate_mb", Usage: 3.2, Recommended: 4},
+		}))
+	})
+
+	It("recommends nothing without usage", func() {
+		Expect(usage.Recommend(&usage.Usage{}, nil, 20)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,291 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package observability contains the admission webhooks of the observability API group.
+package observability
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/internal/limits"
+)
+
+//+kubebuilder:webhook:path=/validate-observability-traceshield-io-v1alpha1-tenant,mutating=false,failurePolicy=fail,sideEffects=None,groups=observability.traceshield.io,resources=tenants,verbs=create;update,versions=v1alpha1,name=vtenant.observability.traceshield.io,admissionReviewVersions=v1
+
+// maxTenantIDLength is the longest tenant ID accepted by Mimir, Loki and Tempo.
+const maxTenantIDLength = 150
+
+// TenantValidator rejects Tenants with invalid or conflicting tenant IDs and
+// Tenants that would make the children of a tenant exceed its budget, and
+// warns about extra limits that collide with modeled ones.
+type TenantValidator struct {
+	Client client.Reader
+}
+
+var _ webhook.CustomValidator = &TenantValidator{}
+
+// SetupWebhookWithManager registers the webhook with the Manager.
+func (v *TenantValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&observabilityv1alpha1.Tenant{}).
+		WithValidator(v).
+		Complete()
+}
+
+// ValidateCreate implements webhook.CustomValidator.
+func (v *TenantValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return warnings(obj), v.validate(ctx, nil, obj)
+}
+
+// ValidateUpdate implements webhook.CustomValidator.
+func (v *TenantValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return warnings(newObj), v.validate(ctx, oldObj, newObj)
+}
+
+// ValidateDelete implements webhook.CustomValidator.
+func (v *TenantValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func warnings(obj runtime.Object) admission.Warnings {
+	tenant, ok := obj.(*observabilityv1alpha1.Tenant)
+	if !ok {
+		return nil
+	}
+	return ExtraWarnings(tenant)
+}
+
+func (v *TenantValidator) validate(ctx context.Context, oldObj, obj runtime.Object) error {
+	tenant, ok := obj.(*observabilityv1alpha1.Tenant)
+	if !ok {
+		return fmt.Errorf("expected a Tenant but got %T", obj)
+	}
+
+	tenantList := &observabilityv1alpha1.TenantList{}
+	if err := v.Client.List(ctx, tenantList); err != nil {
+		return apierrs.NewInternalError(err)
+	}
+
+	var old *observabilityv1alpha1.Tenant
+	if oldObj != nil {
+		if old, ok = oldObj.(*observabilityv1alpha1.Tenant); !ok {
+			return fmt.Errorf("expected a Tenant but got %T", oldObj)
+		}
+	}
+
+	errs := ValidateTenantID(tenant, old, tenantList.Items)
+	budgetErrs, err := ValidateBudgets(tenant, tenantList.Items)
+	if err != nil {
+		return apierrs.NewInternalError(err)
+	}
+	errs = append(errs, budgetErrs...)
+	if len(errs) > 0 {
+		return apierrs.NewInvalid(observabilityv1alpha1.GroupVersion.WithKind("Tenant").GroupKind(), tenant.Name, errs)
+	}
+	return nil
+}
+
+// ValidateTenantID checks that the tenant ID of tenant is a valid
+// X-Scope-OrgID, is not used by any other Tenant and, if old is set, has not
+// changed.
+func ValidateTenantID(tenant, old *observabilityv1alpha1.Tenant, tenants []observabilityv1alpha1.Tenant) field.ErrorList {
+	path := field.NewPath("spec", "tenantID")
+	id := tenant.GetTenantID()
+
+	var errs field.ErrorList
+	switch {
+	case len(id) > maxTenantIDLength:
+		errs = append(errs, field.TooLong(path, id, maxTenantIDLength))
+	case id == "." || id == "..":
+		errs = append(errs, field.Invalid(path, id, "must not be . or .."))
+	default:
+		for _, r := range id {
+			if !validTenantIDRune(r) {
+				errs = append(errs, field.Invalid(path, id, fmt.Sprintf("contains unsupported character %q", r)))
+				break
+			}
+		}
+	}
+
+	if old != nil && old.GetTenantID() != id {
+		errs = append(errs, field.Forbidden(path, fmt.Sprintf("is immutable, the tenant ID is %s", old.GetTenantID())))
+	}
+
+	for i := range tenants {
+		if tenants[i].Name != tenant.Name && tenants[i].GetTenantID() == id {
+			errs = append(errs, field.Duplicate(path, id))
+			break
+		}
+	}
+	return errs
+}
+
+// validTenantIDRune reports whether r may appear in a tenant ID.
+func validTenantIDRune(r rune) bool {
+	switch {
+	case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		return true
+	}
+	return strings.ContainsRune("!-_.*'()", r)
+}
+
+// ValidateBudgets returns the budgets tenant exceeds once it replaces its
+// current version in tenants, both the budget of its parent and its own.
+// A parent budget that is already exceeded only fails if the tenant makes it
+// worse, so that violations can be fixed one tenant at a time.
+func ValidateBudgets(tenant *observabilityv1alpha1.Tenant, tenants []observabilityv1alpha1.Tenant) (field.ErrorList, error) {
+	updated := make([]observabilityv1alpha1.Tenant, 0, len(tenants)+1)
+	var parent, current *observabilityv1alpha1.Tenant
+	for i := range tenants {
+		switch tenants[i].Name {
+		case tenant.Name:
+			current = &tenants[i]
+			continue
+		case tenant.Spec.Parent:
+			parent = &tenants[i]
+		}
+		updated = append(updated, tenants[i])
+	}
+	updated = append(updated, *tenant)
+
+	var errs field.ErrorList
+	if parent != nil && parent.Spec.Budget != nil {
+		violations, err := newViolations(parent.Spec.Budget, parent.Name, tenants, updated)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range violations {
+			errs = append(errs, field.Forbidden(field.NewPath("spec", "limits", v.Backend, v.Limit),
+				fmt.Sprintf("exceeds the budget of parent tenant %s: %s", parent.Name, v)))
+		}
+	}
+	// Changes to the tenant cannot affect its children, so its own budget is
+	// only checked when it changes.
+	if tenant.Spec.Budget != nil && (current == nil || !equality.Semantic.DeepEqual(current.Spec.Budget, tenant.Spec.Budget)) {
+		violations, err := newViolations(tenant.Spec.Budget, tenant.Name, nil, updated)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range violations {
+			errs = append(errs, field.Forbidden(field.NewPath("spec", "budget", v.Backend, v.Limit),
+				fmt.Sprintf("is exceeded by the child tenants: %s", v)))
+		}
+	}
+	return errs, nil
+}
+
+// newViolations returns the violations of the budget of the named tenant in
+// after that are new or worse than in before.
+func newViolations(budget *observabilityv1alpha1.BudgetSpec, name string, before, after []observabilityv1alpha1.Tenant) ([]limits.Violation, error) {
+	_, specs := limits.ChildLimits(name, after)
+	violations, err := limits.CheckBudget(budget, specs)
+	if err != nil || len(violations) == 0 {
+		return nil, err
+	}
+
+	_, specs = limits.ChildLimits(name, before)
+	existing, err := limits.CheckBudget(budget, specs)
+	if err != nil {
+		return nil, err
+	}
+
+	var worse []limits.Violation
+	for _, v := range violations {
+		if !coveredBy(v, existing) {
+			worse = append(worse, v)
+		}
+	}
+	return worse, nil
+}
+
+func coveredBy(v limits.Violation, existing []limits.Violation) bool {
+	for _, e := range existing {
+		if e.Path() == v.Path() && v.Allocated <= e.Allocated && v.Unallocated <= e.Unallocated {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtraWarnings warns about the keys of the extra limits of tenant that
+// collide with a modeled field, which takes precedence over them when both
+// are set.
+func ExtraWarnings(tenant *observabilityv1alpha1.Tenant) admission.Warnings {
+	warnings := extraWarnings(tenant.Spec.Limits, field.NewPath("spec", "limits"))
+	for i := range tenant.Spec.TemporaryLimits {
+		path := field.NewPath("spec", "temporaryLimits").Index(i).Child("limits")
+		warnings = append(warnings, extraWarnings(&tenant.Spec.TemporaryLimits[i].Limits, path)...)
+	}
+	return warnings
+}
+
+func extraWarnings(spec *observabilityv1alpha1.LimitSpec, path *field.Path) admission.Warnings {
+	if spec == nil {
+		return nil
+	}
+	var warnings admission.Warnings
+	v := reflect.ValueOf(spec).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		backendLimits := v.Field(i)
+		if backendLimits.IsNil() {
+			continue
+		}
+		extra, _ := backendLimits.Elem().FieldByName("Extra").Interface().(*observabilityv1alpha1.WrappedMap)
+		if extra == nil {
+			continue
+		}
+		modeled := map[string]bool{}
+		t := backendLimits.Elem().Type()
+		for j := 0; j < t.NumField(); j++ {
+			if t.Field(j).Name != "Extra" {
+				modeled[jsonName(t.Field(j))] = true
+			}
+		}
+
+		backendPath := path.Child(jsonName(v.Type().Field(i)))
+		keys := make([]string, 0, len(extra.Object))
+		for key := range extra.Object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if modeled[key] {
+				warnings = append(warnings, fmt.Sprintf("%s: collides with %s, which takes precedence",
+					backendPath.Child("extra", key), backendPath.Child(key)))
+			}
+		}
+	}
+	return warnings
+}
+
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	return name
+}
@@ -0,0 +1,141 @@
+package observability_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	observabilityv1alpha1 "github.com/traceshield/trace-shield-controller/api/observability/v1alpha1"
+	"github.com/traceshield/trace-shield-controller/internal/webhook/observability"
+)
+
+var _ = Describe("Tenant webhook", func() {
+	count := func(i int) *int { return &i }
+	tenant := func(name, parent string, series *int) observabilityv1alpha1.Tenant {
+		t := observabilityv1alpha1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       observabilityv1alpha1.TenantSpec{Parent: parent},
+		}
+		if series != nil {
+			t.Spec.Limits = &observabilityv1alpha1.LimitSpec{Mimir: &observabilityv1alpha1.MimirLimits{MaxGlobalSeriesPerUser: series}}
+		}
+		return t
+	}
+
+	var tenants []observabilityv1alpha1.Tenant
+
+	BeforeEach(func() {
+		org := tenant("org", "", nil)
+		org.Spec.Budget = &observabilityv1alpha1.BudgetSpec{
+			Mimir: &observabilityv1alpha1.MimirBudget{MaxGlobalSeriesPerUser: count(1000)},
+		}
+		tenants = []observabilityv1alpha1.Tenant{org, tenant("team-a", "org", count(600))}
+	})
+
+	It("accepts children within the budget of their parent", func() {
+		child := tenant("team-b", "org", count(400))
+		errs, err := observability.ValidateBudgets(&child, tenants)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errs).To(BeEmpty())
+	})
+
+	It("rejects children exceeding the budget of their parent", func() {
+		child := tenant("team-b", "org", count(500))
+		errs, err := observability.ValidateBudgets(&child, tenants)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.limits.mimir.max_global_series_per_user"))
+	})
+
+	It("accepts changes that reduce an existing violation", func() {
+		tenants = append(tenants, tenant("team-b", "org", count(900)))
+		child := tenant("team-b", "org", count(600))
+		errs, err := observability.ValidateBudgets(&child, tenants)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errs).To(BeEmpty())
+	})
+
+	It("rejects budgets the children already exceed", func() {
+		org := tenants[0].DeepCopy()
+		org.Spec.Budget.Mimir.MaxGlobalSeriesPerUser = count(500)
+		errs, err := observability.ValidateBudgets(org, tenants)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.budget.mimir.max_global_series_per_user"))
+	})
+
+	Describe("tenant IDs", func() {
+		It("defaults the tenant ID to the name of the Tenant", func() {
+			t := tenant("team-b", "", nil)
+			Expect(t.GetTenantID()).To(Equal("team-b"))
+			Expect(observability.ValidateTenantID(&t, nil, tenants)).To(BeEmpty())
+		})
+
+		It("rejects tenant IDs that are not valid X-Scope-OrgIDs", func() {
+			for _, id := range []string{"..", "team|b", "team/b", strings.Repeat("a", 151)} {
+				t := tenant("team-b", "", nil)
+				t.Spec.TenantID = id
+				Expect(observability.ValidateTenantID(&t, nil, tenants)).To(HaveLen(1), id)
+			}
+			t := tenant("team-b", "", nil)
+			t.Spec.TenantID = "Team_B.(prod)!"
+			Expect(observability.ValidateTenantID(&t, nil, tenants)).To(BeEmpty())
+		})
+
+		It("rejects tenant IDs used by another Tenant", func() {
+			t := tenant("team-b", "", nil)
+			t.Spec.TenantID = "team-a"
+			errs := observability.ValidateTenantID(&t, nil, tenants)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Type).To(Equal(field.ErrorTypeDuplicate))
+		})
+
+		It("rejects changes to the tenant ID", func() {
+			old := tenants[1].DeepCopy()
+			t := old.DeepCopy()
+			t.Spec.TenantID = "renamed"
+			errs := observability.ValidateTenantID(t, old, tenants)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Type).To(Equal(field.ErrorTypeForbidden))
+
+			t.Spec.TenantID = old.Name
+			Expect(observability.ValidateTenantID(t, old, tenants)).To(BeEmpty())
+		})
+	})
+
+	Describe("extra limits", func() {
+		It("warns about keys that collide with modeled limits", func() {
+			t := tenant("team-b", "", count(10))
+			t.Spec.Limits.Mimir.Extra = &observabilityv1alpha1.WrappedMap{Object: map[string]interface{}{
+				"max_global_series_per_user": 20,
+				"new_limit":                  5,
+			}}
+			t.Spec.TemporaryLimits = []observabilityv1alpha1.TemporaryLimitSpec{{
+				Name: "load-test",
+				Limits: observabilityv1alpha1.LimitSpec{
+					Loki: &observabilityv1alpha1.LokiLimits{
+						Extra: &observabilityv1alpha1.WrappedMap{Object: map[string]interface{}{"ingestion_rate_mb": 20}},
+					},
+				},
+			}}
+
+			Expect(observability.ExtraWarnings(&t)).To(ConsistOf(
+				"spec.limits.mimir.extra.max_global_series_per_user: collides with spec.limits.mimir.max_global_series_per_user, which takes precedence",
+				"spec.temporaryLimits[0].limits.loki.extra.ingestion_rate_mb: collides with spec.temporaryLimits[0].limits.loki.ingestion_rate_mb, which takes precedence",
+			))
+		})
+
+		It("does not warn about keys that are not modeled", func() {
+			t := tenant("team-b", "", nil)
+			t.Spec.Limits = &observabilityv1alpha1.LimitSpec{
+				Tempo: &observabilityv1alpha1.TempoLimits{
+					Extra: &observabilityv1alpha1.WrappedMap{Object: map[string]interface{}{"new_limit": 5}},
+				},
+			}
+			Expect(observability.ExtraWarnings(&t)).To(BeEmpty())
+		})
+	})
+})